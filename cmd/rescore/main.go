@@ -0,0 +1,46 @@
+// Command rescore re-grades stored attempts with the configured scorer and
+// rebuilds performance summaries. Run it after deploying a scoring change.
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"log"
+
+	_ "github.com/lib/pq"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/rescore"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/nilesh0729/PixelScribe/internal/util"
+)
+
+func main() {
+	force := flag.Bool("force", false, "re-score every attempt, not only those scored by another version")
+	scorerID := flag.String("scorer", "", "scorer to use, e.g. wordalign (defaults to SCORER from config)")
+	flag.Parse()
+
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		log.Fatal("cannot load config: ", err)
+	}
+	if *scorerID == "" {
+		*scorerID = config.Scorer
+	}
+
+	scorer, ok := scoring.LookupScorer(*scorerID)
+	if !ok {
+		log.Fatalf("unknown scorer %q, available: %v", *scorerID, scoring.Scorers())
+	}
+
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		log.Fatal("cannot connect to db: ", err)
+	}
+
+	report, err := rescore.Run(context.Background(), db.NewStore(conn), scorer, *force)
+	if err != nil {
+		log.Fatal("cannot rescore attempts: ", err)
+	}
+	log.Printf("rescored %d attempts with %s (%d skipped)", report.Rescored, scoring.VersionOf(scorer), report.Skipped)
+}
@@ -0,0 +1,4 @@
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "wrong_word_errors";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "insertion_errors";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "omission_errors";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "punctuation_errors";
@@ -0,0 +1,4 @@
+ALTER TABLE "attempts" ADD COLUMN "punctuation_errors" int;
+ALTER TABLE "attempts" ADD COLUMN "omission_errors" int;
+ALTER TABLE "attempts" ADD COLUMN "insertion_errors" int;
+ALTER TABLE "attempts" ADD COLUMN "wrong_word_errors" int;
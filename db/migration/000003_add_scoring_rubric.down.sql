@@ -0,0 +1,2 @@
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "mark_sheet";
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "scoring_options";
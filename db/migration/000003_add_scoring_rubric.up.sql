@@ -0,0 +1,2 @@
+ALTER TABLE "dictations" ADD COLUMN "scoring_options" jsonb;
+ALTER TABLE "attempts" ADD COLUMN "mark_sheet" jsonb;
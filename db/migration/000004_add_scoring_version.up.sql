@@ -0,0 +1 @@
+ALTER TABLE "attempts" ADD COLUMN "scoring_version" varchar;
@@ -0,0 +1,11 @@
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "rolling_char_accuracy";
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "rolling_cpm";
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "rolling_net_wpm";
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "rolling_gross_wpm";
+
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "kspc";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "keystrokes";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "char_accuracy";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "cpm";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "net_wpm";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "gross_wpm";
@@ -0,0 +1,11 @@
+ALTER TABLE "attempts" ADD COLUMN "gross_wpm" float;
+ALTER TABLE "attempts" ADD COLUMN "net_wpm" float;
+ALTER TABLE "attempts" ADD COLUMN "cpm" float;
+ALTER TABLE "attempts" ADD COLUMN "char_accuracy" float;
+ALTER TABLE "attempts" ADD COLUMN "keystrokes" int;
+ALTER TABLE "attempts" ADD COLUMN "kspc" float;
+
+ALTER TABLE "performance_summary" ADD COLUMN "rolling_gross_wpm" float;
+ALTER TABLE "performance_summary" ADD COLUMN "rolling_net_wpm" float;
+ALTER TABLE "performance_summary" ADD COLUMN "rolling_cpm" float;
+ALTER TABLE "performance_summary" ADD COLUMN "rolling_char_accuracy" float;
@@ -0,0 +1,5 @@
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "time_flagged";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "client_time_spent";
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "session_id";
+
+DROP TABLE IF EXISTS "attempt_sessions";
@@ -0,0 +1,15 @@
+CREATE TABLE "attempt_sessions" (
+  "id" uuid PRIMARY KEY,
+  "user_id" bigint NOT NULL,
+  "dictation_id" bigint NOT NULL,
+  "started_at" timestamptz NOT NULL DEFAULT NOW(),
+  "used_at" timestamptz
+);
+
+ALTER TABLE "attempt_sessions" ADD FOREIGN KEY ("user_id") REFERENCES "users" ("id");
+
+ALTER TABLE "attempt_sessions" ADD FOREIGN KEY ("dictation_id") REFERENCES "dictations" ("id") ON DELETE CASCADE;
+
+ALTER TABLE "attempts" ADD COLUMN "session_id" uuid;
+ALTER TABLE "attempts" ADD COLUMN "client_time_spent" float;
+ALTER TABLE "attempts" ADD COLUMN "time_flagged" boolean NOT NULL DEFAULT false;
@@ -0,0 +1 @@
+ALTER TABLE "attempts" ADD COLUMN "input_encoding" varchar NOT NULL DEFAULT 'unicode';
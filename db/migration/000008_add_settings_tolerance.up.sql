@@ -0,0 +1 @@
+ALTER TABLE "settings" ADD COLUMN "tolerance" jsonb;
@@ -0,0 +1,4 @@
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "average_weighted_accuracy";
+ALTER TABLE "performance_summary" DROP COLUMN IF EXISTS "best_weighted_accuracy";
+
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "weighted_accuracy";
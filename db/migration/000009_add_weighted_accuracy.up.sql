@@ -0,0 +1,4 @@
+ALTER TABLE "attempts" ADD COLUMN "weighted_accuracy" float;
+
+ALTER TABLE "performance_summary" ADD COLUMN "best_weighted_accuracy" float;
+ALTER TABLE "performance_summary" ADD COLUMN "average_weighted_accuracy" float;
@@ -0,0 +1 @@
+ALTER TABLE "attempts" ADD COLUMN "paragraph_errors" int;
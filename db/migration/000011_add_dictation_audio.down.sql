@@ -0,0 +1,5 @@
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "audio_attempts";
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "audio_error";
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "audio_status";
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "audio_duration_ms";
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "audio_key";
@@ -0,0 +1,7 @@
+ALTER TABLE "dictations" ADD COLUMN "audio_key" varchar;
+ALTER TABLE "dictations" ADD COLUMN "audio_duration_ms" bigint;
+ALTER TABLE "dictations" ADD COLUMN "audio_status" varchar;
+ALTER TABLE "dictations" ADD COLUMN "audio_error" text;
+ALTER TABLE "dictations" ADD COLUMN "audio_attempts" int NOT NULL DEFAULT 0;
+
+CREATE INDEX ON "dictations" ("audio_status");
@@ -0,0 +1 @@
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "transcript_segments";
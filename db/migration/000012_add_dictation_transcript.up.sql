@@ -0,0 +1 @@
+ALTER TABLE "dictations" ADD COLUMN "transcript_segments" jsonb;
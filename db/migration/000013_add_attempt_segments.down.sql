@@ -0,0 +1,3 @@
+ALTER TABLE "attempts" DROP COLUMN IF EXISTS "segment";
+
+ALTER TABLE "attempt_sessions" DROP COLUMN IF EXISTS "segment";
@@ -0,0 +1,3 @@
+ALTER TABLE "attempt_sessions" ADD COLUMN "segment" int;
+
+ALTER TABLE "attempts" ADD COLUMN "segment" int;
@@ -0,0 +1,3 @@
+ALTER TABLE "dictations" DROP COLUMN IF EXISTS "lexicon";
+
+ALTER TABLE "settings" DROP COLUMN IF EXISTS "lexicon";
@@ -0,0 +1,3 @@
+ALTER TABLE "settings" ADD COLUMN "lexicon" jsonb;
+
+ALTER TABLE "dictations" ADD COLUMN "lexicon" jsonb;
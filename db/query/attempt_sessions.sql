@@ -0,0 +1,38 @@
+-- name: CreateAttemptSession :one
+INSERT INTO attempt_sessions (
+  id, user_id, dictation_id, segment
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetAttemptSession :one
+SELECT * FROM attempt_sessions
+WHERE id = $1 LIMIT 1;
+
+-- name: UseAttemptSession :one
+-- Marks the session used, returning no rows if it was already used, and
+-- measures the elapsed time on the database clock that set started_at.
+UPDATE attempt_sessions
+SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL
+RETURNING
+  id,
+  user_id,
+  dictation_id,
+  started_at,
+  segment,
+  EXTRACT(EPOCH FROM (NOW() - started_at))::float AS elapsed_seconds;
+
+-- name: GetUnusedAttemptSession :one
+-- Returns the session if it has not been used yet, with the elapsed time
+-- measured like UseAttemptSession does.
+SELECT
+  id,
+  user_id,
+  dictation_id,
+  started_at,
+  segment,
+  EXTRACT(EPOCH FROM (NOW() - started_at))::float AS elapsed_seconds
+FROM attempt_sessions
+WHERE id = $1 AND used_at IS NULL;
@@ -6,12 +6,30 @@ INSERT INTO attempts (
   attempt_no, 
   total_words, 
   correct_words, 
-  grammatical_errors, 
+  wrong_word_errors, 
   spelling_errors, 
   case_errors, 
+  punctuation_errors,
+  omission_errors,
+  insertion_errors,
   accuracy, 
   comparison_data, 
   time_spent,
+  mark_sheet,
+  scoring_version,
+  gross_wpm,
+  net_wpm,
+  cpm,
+  char_accuracy,
+  keystrokes,
+  kspc,
+  session_id,
+  client_time_spent,
+  time_flagged,
+  input_encoding,
+  weighted_accuracy,
+  paragraph_errors,
+  segment,
   created_at
 ) VALUES (
   $1, $2, $3, 
@@ -20,7 +38,9 @@ INSERT INTO attempts (
     FROM attempts
     WHERE user_id = $1 AND dictation_id = $2
   ), 1), 
-  $4, $5, $6, $7, $8, $9, $10, $11, NOW()
+  $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+  $17, $18, $19, $20, $21, $22,
+  $23, $24, $25, $26, $27, $28, $29, NOW()
 )
 RETURNING *;
 
@@ -60,10 +80,52 @@ UPDATE attempts
 SET
   accuracy = $2,
   correct_words = $3,
-  grammatical_errors = $4,
+  wrong_word_errors = $4,
   spelling_errors = $5,
   case_errors = $6,
-  comparison_data = $7,
-  time_spent = $8
+  punctuation_errors = $7,
+  omission_errors = $8,
+  insertion_errors = $9,
+  comparison_data = $10,
+  time_spent = $11,
+  total_words = $12,
+  mark_sheet = $13,
+  scoring_version = $14,
+  gross_wpm = $15,
+  net_wpm = $16,
+  cpm = $17,
+  char_accuracy = $18,
+  kspc = $19,
+  weighted_accuracy = $20,
+  paragraph_errors = $21
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: ListAttemptsToRescore :many
+SELECT * FROM attempts
+WHERE (sqlc.arg('force')::boolean
+   OR scoring_version IS DISTINCT FROM sqlc.arg('scoring_version')::varchar)
+  AND id > sqlc.arg('after_id')::bigint
+ORDER BY id
+LIMIT sqlc.arg('batch_size')::int;
+
+-- name: GetWeightedAccuracyStats :one
+SELECT
+    COALESCE(MAX(weighted_accuracy), 0)::float AS best_weighted_accuracy,
+    COALESCE(AVG(weighted_accuracy), 0)::float AS average_weighted_accuracy
+FROM attempts
+WHERE user_id = $1 AND dictation_id = $2 AND segment IS NULL;
+
+-- name: GetRollingSpeed :one
+SELECT
+    COALESCE(AVG(gross_wpm), 0)::float AS rolling_gross_wpm,
+    COALESCE(AVG(net_wpm), 0)::float AS rolling_net_wpm,
+    COALESCE(AVG(cpm), 0)::float AS rolling_cpm,
+    COALESCE(AVG(char_accuracy), 0)::float AS rolling_char_accuracy
+FROM (
+    SELECT gross_wpm, net_wpm, cpm, char_accuracy
+    FROM attempts
+    WHERE user_id = $1 AND dictation_id = $2 AND segment IS NULL
+    ORDER BY created_at DESC, id DESC
+    LIMIT $3
+) AS recent;
@@ -6,10 +6,12 @@ INSERT INTO dictations (
   content, 
   language,
   created_at, 
-  updated_at
-
+  updated_at,
+  scoring_options,
+  audio_status,
+  lexicon
 ) VALUES (
-  $1, $2, 'text', $3, $4, $5, $6
+  $1, $2, 'text', $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -21,9 +23,15 @@ INSERT INTO dictations (
   audio_url,
   language,
   created_at,
-  updated_at
+  updated_at,
+  scoring_options,
+  audio_key,
+  audio_duration_ms,
+  content,
+  transcript_segments,
+  lexicon
 ) VALUES (
-  $1, $2, 'audio', $3, $4, $5, $6
+  $1, $2, 'audio', $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
 RETURNING *;
 
@@ -63,7 +71,62 @@ WHERE id = sqlc.arg('id')
   AND user_id = sqlc.arg('user_id')
 RETURNING *;
 
+-- name: SetDictationTranscript :one
+UPDATE dictations
+SET
+    content = sqlc.arg('content'),
+    transcript_segments = sqlc.arg('transcript_segments'),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+  AND type = 'audio'
+RETURNING *;
+
+-- name: SetDictationLexicon :one
+UPDATE dictations
+SET
+    lexicon = sqlc.narg('lexicon'),
+    updated_at = NOW()
+WHERE id = sqlc.arg('id')
+  AND user_id = sqlc.arg('user_id')
+RETURNING *;
 
 -- name: DeleteDictations :exec
 DELETE FROM dictations
-WHERE title = $1;
\ No newline at end of file
+WHERE title = $1;
+-- name: QueueDictationAudio :one
+UPDATE dictations
+SET
+    audio_status = 'pending',
+    audio_error = NULL,
+    audio_attempts = 0
+WHERE id = $1
+RETURNING *;
+
+-- name: ListDictationsByAudioStatus :many
+SELECT * FROM dictations
+WHERE audio_status = $1
+ORDER BY id;
+
+-- name: CompleteDictationAudio :one
+UPDATE dictations
+SET
+    audio_url = sqlc.arg('audio_url'),
+    audio_key = sqlc.arg('audio_key'),
+    audio_duration_ms = sqlc.arg('audio_duration_ms'),
+    audio_status = 'ready',
+    audio_error = NULL,
+    audio_attempts = audio_attempts + 1
+WHERE id = sqlc.arg('id')
+  AND content = sqlc.arg('content')
+  AND lexicon IS NOT DISTINCT FROM sqlc.narg('lexicon')
+RETURNING *;
+
+-- name: FailDictationAudio :one
+UPDATE dictations
+SET
+    audio_status = sqlc.arg('audio_status'),
+    audio_error = sqlc.arg('audio_error'),
+    audio_attempts = audio_attempts + 1
+WHERE id = sqlc.arg('id')
+RETURNING *;
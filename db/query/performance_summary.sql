@@ -1,8 +1,10 @@
 -- name: CreatePerformanceSummary :one
 INSERT INTO performance_summary (
-    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at,
+    rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy,
+    best_weighted_accuracy, average_weighted_accuracy
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
 RETURNING *;
 
@@ -29,7 +31,13 @@ SET
     best_accuracy = $2,
     average_accuracy = $3,
     average_time = $4,
-    last_attempt_at = $5
+    last_attempt_at = $5,
+    rolling_gross_wpm = $7,
+    rolling_net_wpm = $8,
+    rolling_cpm = $9,
+    rolling_char_accuracy = $10,
+    best_weighted_accuracy = $11,
+    average_weighted_accuracy = $12
 WHERE id = $6
 RETURNING *;
 
@@ -52,4 +60,37 @@ SELECT
 FROM performance_summary
 GROUP BY user_id;
 
+-- name: DeleteUserPerformanceSummaries :exec
+DELETE FROM performance_summary
+WHERE user_id IS NOT DISTINCT FROM sqlc.narg('user_id')::bigint;
 
+-- name: RebuildUserPerformanceSummaries :exec
+INSERT INTO performance_summary (
+    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at,
+    rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy,
+    best_weighted_accuracy, average_weighted_accuracy
+)
+SELECT
+    user_id,
+    dictation_id,
+    COUNT(*),
+    MAX(accuracy),
+    AVG(accuracy),
+    AVG(time_spent),
+    MAX(created_at),
+    AVG(gross_wpm) FILTER (WHERE recency <= sqlc.arg('rolling_window')::int),
+    AVG(net_wpm) FILTER (WHERE recency <= sqlc.arg('rolling_window')::int),
+    AVG(cpm) FILTER (WHERE recency <= sqlc.arg('rolling_window')::int),
+    AVG(char_accuracy) FILTER (WHERE recency <= sqlc.arg('rolling_window')::int),
+    MAX(weighted_accuracy),
+    AVG(weighted_accuracy)
+FROM (
+    SELECT
+        *,
+        ROW_NUMBER() OVER (PARTITION BY user_id, dictation_id ORDER BY created_at DESC, id DESC) AS recency
+    FROM attempts
+    -- Segment practice is kept out of the dictation's summary
+    WHERE segment IS NULL
+      AND user_id IS NOT DISTINCT FROM sqlc.narg('user_id')::bigint
+) AS ranked
+GROUP BY user_id, dictation_id;
@@ -2,19 +2,19 @@
 INSERT INTO settings (
     user_id, default_voice, default_speed, 
     highlight_color_grammar, highlight_color_spelling, highlight_color_case, 
-    created_at, updated_at
+    tolerance, lexicon, created_at, updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
 )
-RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at;
+RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon;
 
 -- name: GetSettingByID :one
-SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 FROM settings
 WHERE id = $1;
 
 -- name: GetSettingByUserID :one
-SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 FROM settings
 WHERE user_id = $1;
 
@@ -26,9 +26,11 @@ SET
     highlight_color_grammar = $4,
     highlight_color_spelling = $5,
     highlight_color_case = $6,
+    tolerance = $7,
+    lexicon = $8,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at;
+RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon;
 
 -- name: DeleteSetting :exec
 DELETE FROM settings
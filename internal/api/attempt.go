@@ -4,29 +4,119 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+    "errors"
     "fmt"
+    "math"
 	"net/http"
-    "strings"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/legacyfont"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
     "github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
 )
 
+const (
+	defaultAttemptSessionDuration = 2 * time.Hour
+	// A submission is flagged when the client's time_spent differs from the
+	// server's elapsed time by more than this many seconds and this fraction
+	// of the elapsed time.
+	timeMismatchSeconds  = 10.0
+	timeMismatchFraction = 0.2
+)
+
+type startAttemptRequest struct {
+	DictationID int64 `json:"dictation_id" binding:"required,min=1"`
+	// Segment, counting from 1, limits the attempt to one segment of an
+	// audio dictation; 0 is the whole dictation
+	Segment int32 `json:"segment" binding:"min=0"`
+}
+
+type startAttemptResponse struct {
+	SessionToken string    `json:"session_token"`
+	SessionID    string    `json:"session_id"`
+	DictationID  int64     `json:"dictation_id"`
+	Segment      int32     `json:"segment,omitempty"`
+	StartedAt    time.Time `json:"started_at"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
+
+// startAttempt opens a server-timed attempt session that submitAttempt must
+// present, so the time spent on an attempt is measured by the server.
+func (server *Server) startAttempt(ctx *gin.Context) {
+	var req startAttemptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dictation, err := server.store.GetDictation(ctx, req.DictationID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("dictation not found")))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if !scorable(dictation) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errNoTranscript))
+		return
+	}
+	if req.Segment > 0 {
+		if _, err := transcript.Lookup(dictation.TranscriptSegments.RawMessage, int(req.Segment)); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	session, err := server.store.CreateAttemptSession(ctx, db.CreateAttemptSessionParams{
+		ID:          uuid.New(),
+		UserID:      authPayload.UserID,
+		DictationID: req.DictationID,
+		Segment:     sql.NullInt32{Int32: req.Segment, Valid: req.Segment > 0},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	sessionToken, payload, err := server.sessions.CreateSession(session.ID, session.UserID, session.DictationID, session.StartedAt, server.config.AttemptSessionDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, startAttemptResponse{
+		SessionToken: sessionToken,
+		SessionID:    session.ID.String(),
+		DictationID:  session.DictationID,
+		Segment:      session.Segment.Int32,
+		StartedAt:    session.StartedAt,
+		ExpiresAt:    payload.ExpiresAt.Time,
+	})
+}
+
 type submitAttemptRequest struct {
 	DictationID       int64           `json:"dictation_id" binding:"required"`
+	// SessionToken comes from POST /attempts/start
+	SessionToken      string          `json:"session_token" binding:"required"`
 	TypedText         string          `json:"typed_text"`
+	// InputEncoding declares a legacy font the text was typed in, e.g.
+	// "krutidev"; it is converted to Unicode before scoring
+	InputEncoding     string          `json:"input_encoding"`
+	// TimeSpent is the client's own measurement, kept only to flag mismatches
 	TimeSpent         float64         `json:"time_spent"`
-    // Optional / Calculated server-side fields
-	TotalWords        int32           `json:"total_words"`
-	CorrectWords      int32           `json:"correct_words"`
-	GrammaticalErrors int32           `json:"grammatical_errors"`
-	SpellingErrors    int32           `json:"spelling_errors"`
-	CaseErrors        int32           `json:"case_errors"`
-	Accuracy          float64         `json:"accuracy"`
-	ComparisonData    json.RawMessage `json:"comparison_data"`
+	// Keystrokes counts every key pressed, corrections included, for KSPC
+	Keystrokes        int32           `json:"keystrokes" binding:"min=0"`
 }
 
 type attemptResponse struct {
@@ -34,18 +124,91 @@ type attemptResponse struct {
 	UserID            int64           `json:"user_id"`
 	DictationID       int64           `json:"dictation_id"`
 	TypedText         string          `json:"typed_text"`
+	InputEncoding     string          `json:"input_encoding"`
 	AttemptNo         int32           `json:"attempt_no"`
+	Segment           *int32          `json:"segment,omitempty"`
 	Accuracy          float64         `json:"accuracy"`
+	WeightedAccuracy  *float64        `json:"weighted_accuracy,omitempty"`
 	TimeSpent         float64         `json:"time_spent"`
+	ClientTimeSpent   *float64        `json:"client_time_spent,omitempty"`
+	TimeFlagged       bool            `json:"time_flagged"`
 	CreatedAt         time.Time       `json:"created_at"`
+	TotalWords        int32           `json:"total_words"`
+	CorrectWords      int32           `json:"correct_words"`
+	WrongWordErrors   int32           `json:"wrong_word_errors"`
+	// GrammaticalErrors is not checked by any scorer yet and stays 0
+	GrammaticalErrors int32           `json:"grammatical_errors"`
+	SpellingErrors    int32           `json:"spelling_errors"`
+	CaseErrors        int32           `json:"case_errors"`
+	PunctuationErrors int32           `json:"punctuation_errors"`
+	OmissionErrors    int32           `json:"omission_errors"`
+	InsertionErrors   int32           `json:"insertion_errors"`
+	ParagraphErrors   int32           `json:"paragraph_errors"`
+	GrossWPM          float64         `json:"gross_wpm"`
+	NetWPM            float64         `json:"net_wpm"`
+	CPM               float64         `json:"cpm"`
+	CharAccuracy      float64         `json:"char_accuracy"`
+	KSPC              *float64        `json:"kspc,omitempty"`
+	ComparisonData    json.RawMessage `json:"comparison_data,omitempty"`
+	MarkSheet         json.RawMessage `json:"mark_sheet,omitempty"`
 	PerformanceUpdate *performanceSum `json:"performance_update,omitempty"`
 }
 
+func newAttemptResponse(attempt db.Attempt) attemptResponse {
+	rsp := attemptResponse{
+		ID:                attempt.ID,
+		UserID:            attempt.UserID.Int64,
+		DictationID:       attempt.DictationID.Int64,
+		TypedText:         attempt.TypedText.String,
+		InputEncoding:     attempt.InputEncoding,
+		AttemptNo:         attempt.AttemptNo.Int32,
+		Accuracy:          attempt.Accuracy.Float64,
+		TimeSpent:         attempt.TimeSpent.Float64,
+		TimeFlagged:       attempt.TimeFlagged,
+		CreatedAt:         attempt.CreatedAt.Time,
+		TotalWords:        attempt.TotalWords.Int32,
+		CorrectWords:      attempt.CorrectWords.Int32,
+		WrongWordErrors:   attempt.WrongWordErrors.Int32,
+		GrammaticalErrors: attempt.GrammaticalErrors.Int32,
+		SpellingErrors:    attempt.SpellingErrors.Int32,
+		CaseErrors:        attempt.CaseErrors.Int32,
+		PunctuationErrors: attempt.PunctuationErrors.Int32,
+		OmissionErrors:    attempt.OmissionErrors.Int32,
+		InsertionErrors:   attempt.InsertionErrors.Int32,
+		ParagraphErrors:   attempt.ParagraphErrors.Int32,
+		GrossWPM:          attempt.GrossWpm.Float64,
+		NetWPM:            attempt.NetWpm.Float64,
+		CPM:               attempt.Cpm.Float64,
+		CharAccuracy:      attempt.CharAccuracy.Float64,
+		ComparisonData:    attempt.ComparisonData.RawMessage,
+		MarkSheet:         attempt.MarkSheet.RawMessage,
+	}
+	if attempt.WeightedAccuracy.Valid {
+		rsp.WeightedAccuracy = &attempt.WeightedAccuracy.Float64
+	}
+	if attempt.Kspc.Valid {
+		rsp.KSPC = &attempt.Kspc.Float64
+	}
+	if attempt.ClientTimeSpent.Valid {
+		rsp.ClientTimeSpent = &attempt.ClientTimeSpent.Float64
+	}
+	if attempt.Segment.Valid {
+		rsp.Segment = &attempt.Segment.Int32
+	}
+	return rsp
+}
+
 type performanceSum struct {
-	TotalAttempts   int32   `json:"total_attempts"`
-	BestAccuracy    float64 `json:"best_accuracy"`
-	AverageAccuracy float64 `json:"average_accuracy"`
-	AverageTime     float64 `json:"average_time"`
+	TotalAttempts           int32   `json:"total_attempts"`
+	BestAccuracy            float64 `json:"best_accuracy"`
+	AverageAccuracy         float64 `json:"average_accuracy"`
+	AverageTime             float64 `json:"average_time"`
+	RollingGrossWPM         float64 `json:"rolling_gross_wpm"`
+	RollingNetWPM           float64 `json:"rolling_net_wpm"`
+	RollingCPM              float64 `json:"rolling_cpm"`
+	RollingCharAccuracy     float64 `json:"rolling_char_accuracy"`
+	BestWeightedAccuracy    float64 `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy float64 `json:"average_weighted_accuracy"`
 }
 
 func (server *Server) submitAttempt(ctx *gin.Context) {
@@ -55,6 +218,42 @@ func (server *Server) submitAttempt(ctx *gin.Context) {
 		return
 	}
 
+	// Text typed in a legacy Hindi font is converted so it can be scored
+	// against a Unicode dictation
+	encoding, err := legacyfont.Parse(req.InputEncoding)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	typedText, err := legacyfont.ToUnicode(req.TypedText, encoding)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+    // Get UserID from auth payload
+    authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+    session, err := server.sessions.VerifySession(req.SessionToken)
+    if err != nil {
+        if errors.Is(err, token.ErrExpiredToken) {
+            err = fmt.Errorf("attempt session expired")
+        } else {
+            err = fmt.Errorf("invalid attempt session")
+        }
+        ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+        return
+    }
+    if session.UserID != authPayload.UserID || session.DictationID != req.DictationID {
+        ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("attempt session doesn't match this user and dictation")))
+        return
+    }
+    sessionID, err := session.SessionID()
+    if err != nil {
+        ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("invalid attempt session")))
+        return
+    }
+
 	// Fetch original dictation for verification
     dictation, err := server.store.GetDictation(ctx, req.DictationID)
     if err != nil {
@@ -65,85 +264,160 @@ func (server *Server) submitAttempt(ctx *gin.Context) {
         ctx.JSON(http.StatusInternalServerError, errorResponse(err))
         return
     }
+    if !scorable(dictation) {
+        ctx.JSON(http.StatusUnprocessableEntity, errorResponse(errNoTranscript))
+        return
+    }
 
-    // Server-side calculation
-    originalText := dictation.Content.String
-    typedText := req.TypedText
-
-    originalWords := strings.Fields(originalText)
-    typedWords := strings.Fields(typedText)
-    
-    totalWords := int32(len(originalWords))
-    correctWords := int32(0)
-    
-    // Simple verification algorithm: Word-by-word match
-    // Note: This is a basic comparison. For more advanced diffing (insertions/deletions),
-    // we would need a diff library (e.g. sergi/go-diff), but this suffices for exact matching verification.
-    limit := len(originalWords)
-    if len(typedWords) < limit {
-        limit = len(typedWords)
+    // A session that was already used is a replay. It is only used up when
+    // the attempt is stored, so a submission that fails can be retried.
+    openSession, err := server.store.GetUnusedAttemptSession(ctx, sessionID)
+    if err != nil {
+        if err == sql.ErrNoRows {
+            ctx.JSON(http.StatusConflict, errorResponse(db.ErrAttemptSessionUsed))
+            return
+        }
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
     }
+    timeSpent := openSession.ElapsedSeconds
 
-    for i := 0; i < limit; i++ {
-        // Case-insensitive comparison could be used, but strict typing usually requires exact case
-        if originalWords[i] == typedWords[i] {
-            correctWords++
+    // Server-side calculation, against only the segment the session was
+    // started for, if any
+    originalText := dictation.Content.String
+    if openSession.Segment.Valid {
+        segment, err := transcript.Lookup(dictation.TranscriptSegments.RawMessage, int(openSession.Segment.Int32))
+        if err != nil {
+            // The transcript was changed since the session started
+            ctx.JSON(http.StatusConflict, errorResponse(err))
+            return
         }
+        originalText = segment.Text
     }
-    
-    // Calculate accuracy
-    accuracy := float64(0)
-    if totalWords > 0 {
-        accuracy = (float64(correctWords) / float64(totalWords)) * 100
+    if words, limit := len(strings.Fields(typedText)), scoring.MaxTypedWords(len(strings.Fields(originalText))); words > limit {
+        ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("typed text has %d words, at most %d are accepted for this dictation", words, limit)))
+        return
     }
 
-    // Simplified error category estimation (can be improved with proper diffing later)
-    errors := totalWords - correctWords
-    
-    // Get UserID from auth payload
-    authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+    // Grade against the dictation's rubric (e.g. stenography exam marking), if it has one
+    scoringOptions, err := scoring.ParseOptions(dictation.ScoringOptions.RawMessage)
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
+    }
+    // The user's own tolerance profile applies unless the dictation sets one,
+    // and their lexicon after the dictation's, as when it was read aloud
+    userTolerance, userLexicon, err := server.userScoring(ctx, authPayload.UserID)
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
+    }
+    dictationLexicon, err := lexicon.Parse(dictation.Lexicon.RawMessage)
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
+    }
+    score, err := server.scorer.Score(scoring.Input{
+        Original:      originalText,
+        Typed:         typedText,
+        Options:       scoringOptions,
+        Language:      dictation.Language.String,
+        Seconds:       timeSpent,
+        Keystrokes:    int(req.Keystrokes),
+        UserTolerance: userTolerance,
+        Lexicon:       append(dictationLexicon, userLexicon...),
+    })
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
+    }
+
+    // comparison_data is always generated here so the UI highlights exactly what was scored
+    comparisonData, err := score.ComparisonJSON()
+    if err != nil {
+        ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+        return
+    }
+    var markSheetData pqtype.NullRawMessage
+    if score.MarkSheet != nil {
+        data, err := score.MarkSheetJSON()
+        if err != nil {
+            ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+            return
+        }
+        markSheetData = pqtype.NullRawMessage{RawMessage: data, Valid: true}
+    }
 
 	arg := db.CreateAttemptsParams{
 		UserID:            sql.NullInt64{Int64: authPayload.UserID, Valid: true},
 		DictationID:       sql.NullInt64{Int64: req.DictationID, Valid: true},
-		TypedText:         sql.NullString{String: req.TypedText, Valid: true},
-		TotalWords:        sql.NullInt32{Int32: totalWords, Valid: true},
-		CorrectWords:      sql.NullInt32{Int32: correctWords, Valid: true},
-		GrammaticalErrors: sql.NullInt32{Int32: 0, Valid: true}, // Placeholder
-		SpellingErrors:    sql.NullInt32{Int32: errors, Valid: true}, // Lump all errors here for now
-		CaseErrors:        sql.NullInt32{Int32: 0, Valid: true}, // Placeholder
-		Accuracy:          sql.NullFloat64{Float64: accuracy, Valid: true},
-		ComparisonData:    pqtype.NullRawMessage{RawMessage: req.ComparisonData, Valid: len(req.ComparisonData) > 0},
-		TimeSpent:         sql.NullFloat64{Float64: req.TimeSpent, Valid: true},
+		TypedText:         sql.NullString{String: typedText, Valid: true},
+		TotalWords:        sql.NullInt32{Int32: int32(score.TotalWords), Valid: true},
+		CorrectWords:      sql.NullInt32{Int32: int32(score.CorrectWords), Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: int32(score.Errors.WrongWord), Valid: true},
+		SpellingErrors:    sql.NullInt32{Int32: int32(score.Errors.Spelling), Valid: true},
+		CaseErrors:        sql.NullInt32{Int32: int32(score.Errors.Case), Valid: true},
+		PunctuationErrors: sql.NullInt32{Int32: int32(score.Errors.Punctuation), Valid: true},
+		OmissionErrors:    sql.NullInt32{Int32: int32(score.Errors.Omission), Valid: true},
+		InsertionErrors:   sql.NullInt32{Int32: int32(score.Errors.Insertion), Valid: true},
+		ParagraphErrors:   sql.NullInt32{Int32: int32(score.Errors.Paragraph), Valid: true},
+		Accuracy:          sql.NullFloat64{Float64: score.Accuracy, Valid: true},
+		WeightedAccuracy:  sql.NullFloat64{Float64: score.WeightedAccuracy, Valid: true},
+		ComparisonData:    pqtype.NullRawMessage{RawMessage: comparisonData, Valid: true},
+		TimeSpent:         sql.NullFloat64{Float64: timeSpent, Valid: true},
+		MarkSheet:         markSheetData,
+		ScoringVersion:    sql.NullString{String: score.Version, Valid: true},
+		// Speed is only meaningful for a timed attempt
+		GrossWpm:          sql.NullFloat64{Float64: score.Speed.GrossWPM, Valid: timeSpent > 0},
+		NetWpm:            sql.NullFloat64{Float64: score.Speed.NetWPM, Valid: timeSpent > 0},
+		Cpm:               sql.NullFloat64{Float64: score.Speed.CPM, Valid: timeSpent > 0},
+		CharAccuracy:      sql.NullFloat64{Float64: score.Speed.CharAccuracy, Valid: true},
+		Keystrokes:        sql.NullInt32{Int32: req.Keystrokes, Valid: req.Keystrokes > 0},
+		Kspc:              sql.NullFloat64{Float64: score.Speed.KSPC, Valid: score.Speed.KSPC > 0},
+		SessionID:         uuid.NullUUID{UUID: sessionID, Valid: true},
+		ClientTimeSpent:   sql.NullFloat64{Float64: req.TimeSpent, Valid: req.TimeSpent > 0},
+		TimeFlagged:       req.TimeSpent > 0 && timeMismatch(req.TimeSpent, timeSpent),
+		InputEncoding:     string(encoding),
+		Segment:           openSession.Segment,
 	}
 
 	// Use Transaction
 	result, err := server.store.SubmitAttemptTx(context.Background(), arg)
 	if err != nil {
+		if errors.Is(err, db.ErrAttemptSessionUsed) {
+			ctx.JSON(http.StatusConflict, errorResponse(err))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	rsp := attemptResponse{
-		ID:          result.Attempt.ID,
-		UserID:      result.Attempt.UserID.Int64,
-		DictationID: result.Attempt.DictationID.Int64,
-		TypedText:   result.Attempt.TypedText.String,
-		AttemptNo:   result.Attempt.AttemptNo.Int32,
-		Accuracy:    result.Attempt.Accuracy.Float64,
-		TimeSpent:   result.Attempt.TimeSpent.Float64,
-		CreatedAt:   result.Attempt.CreatedAt.Time,
-		PerformanceUpdate: &performanceSum{
-			TotalAttempts:   result.PerformanceSummary.TotalAttempts.Int32,
-			BestAccuracy:    result.PerformanceSummary.BestAccuracy.Float64,
-			AverageAccuracy: result.PerformanceSummary.AverageAccuracy.Float64,
-			AverageTime:     result.PerformanceSummary.AverageTime.Float64,
-		},
+	rsp := newAttemptResponse(result.Attempt)
+	if !openSession.Segment.Valid {
+		rsp.PerformanceUpdate = &performanceSum{
+			TotalAttempts:           result.PerformanceSummary.TotalAttempts.Int32,
+			BestAccuracy:            result.PerformanceSummary.BestAccuracy.Float64,
+			AverageAccuracy:         result.PerformanceSummary.AverageAccuracy.Float64,
+			AverageTime:             result.PerformanceSummary.AverageTime.Float64,
+			RollingGrossWPM:         result.PerformanceSummary.RollingGrossWpm.Float64,
+			RollingNetWPM:           result.PerformanceSummary.RollingNetWpm.Float64,
+			RollingCPM:              result.PerformanceSummary.RollingCpm.Float64,
+			RollingCharAccuracy:     result.PerformanceSummary.RollingCharAccuracy.Float64,
+			BestWeightedAccuracy:    result.PerformanceSummary.BestWeightedAccuracy.Float64,
+			AverageWeightedAccuracy: result.PerformanceSummary.AverageWeightedAccuracy.Float64,
+		}
 	}
 
 	ctx.JSON(http.StatusOK, rsp)
 }
 
+// timeMismatch reports whether the client's time differs a lot from the
+// server's elapsed time, which suggests a tampered or broken client clock.
+func timeMismatch(clientSeconds, serverSeconds float64) bool {
+	diff := math.Abs(clientSeconds - serverSeconds)
+	return diff > timeMismatchSeconds && diff > serverSeconds*timeMismatchFraction
+}
+
 type listAttemptsRequest struct {
 	UserID      int64 `form:"user_id"`
 	DictationID int64 `form:"dictation_id"`
@@ -182,16 +456,9 @@ func (server *Server) listAttempts(ctx *gin.Context) {
 	// Simplifying response for list (lightweight)
 	var rsp []attemptResponse
 	for _, attempt := range attempts {
-		rsp = append(rsp, attemptResponse{
-			ID:          attempt.ID,
-			UserID:      attempt.UserID.Int64,
-			DictationID: attempt.DictationID.Int64,
-			TypedText:   attempt.TypedText.String,
-			AttemptNo:   attempt.AttemptNo.Int32,
-			Accuracy:    attempt.Accuracy.Float64,
-			TimeSpent:   attempt.TimeSpent.Float64,
-			CreatedAt:   attempt.CreatedAt.Time,
-		})
+		item := newAttemptResponse(attempt)
+		item.ComparisonData = nil
+		rsp = append(rsp, item)
 	}
 	ctx.JSON(http.StatusOK, rsp)
 }
@@ -226,16 +493,7 @@ func (server *Server) getAttempt(ctx *gin.Context) {
     }
 
     // Construct response
-	rsp := attemptResponse{
-		ID:          attempt.ID,
-		UserID:      attempt.UserID.Int64,
-		DictationID: attempt.DictationID.Int64,
-		TypedText:   attempt.TypedText.String,
-		AttemptNo:   attempt.AttemptNo.Int32,
-		Accuracy:    attempt.Accuracy.Float64,
-		TimeSpent:   attempt.TimeSpent.Float64,
-		CreatedAt:   attempt.CreatedAt.Time,
-	}
+	rsp := newAttemptResponse(attempt)
 
 	ctx.JSON(http.StatusOK, rsp)
 }
@@ -4,14 +4,18 @@ import (
 	"bytes"
 	"database/sql"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
 	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
 	"github.com/nilesh0729/PixelScribe/internal/token"
 	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
@@ -40,12 +44,30 @@ func TestSubmitAttempt(t *testing.T) {
 		PerformanceSummary: summary,
 	}
 
+	// comparison_data the server is expected to build for "Hello world"
+	tokens := scoring.Tokenize("Hello world")
+	alignment := scoring.Align(scoring.Words(tokens), scoring.Words(tokens))
+	comparisonData, err := json.Marshal(scoring.NewComparison(alignment, tokens, tokens))
+	require.NoError(t, err)
+	// 11 characters typed in 10.5 seconds with 13 keystrokes
+	speed := scoring.MeasureSpeed(alignment, tokens, tokens, 10.5, 0, 13)
+
 	// User for auth
 	user, _ := randomUserForLogin(t)
 
+	// Attempt session for dictation 1, as issued by POST /attempts/start
+	sessionID := uuid.New()
+	validSession := func(t *testing.T, maker *token.SessionMaker) string {
+		return newAttemptSession(t, maker, sessionID, user.ID, 1, time.Now(), time.Hour)
+	}
+	openSession := func(elapsed float64) db.GetUnusedAttemptSessionRow {
+		return db.GetUnusedAttemptSessionRow{ID: sessionID, UserID: user.ID, DictationID: 1, ElapsedSeconds: elapsed}
+	}
+
 	testCases := []struct {
 		name          string
 		body          gin.H
+		session       func(t *testing.T, maker *token.SessionMaker) string
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
@@ -53,18 +75,14 @@ func TestSubmitAttempt(t *testing.T) {
 		{
 			name: "OK",
 			body: gin.H{
-				"user_id":            1,
-				"dictation_id":       1,
-				"typed_text":         "Hello world",
-				"total_words":        2,
-				"correct_words":      2,
-				"grammatical_errors": 0,
-				"spelling_errors":    0,
-				"case_errors":        0,
-				"accuracy":           100.0,
-				"comparison_data":    []interface{}{}, 
-				"time_spent":         10.5,
+				"user_id":         1,
+				"dictation_id":    1,
+				"typed_text":      "Hello world",
+				"comparison_data": []interface{}{}, 
+				"time_spent":      10.5,
+				"keystrokes":      13,
 			},
+			session: validSession,
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
 			},
@@ -75,12 +93,27 @@ func TestSubmitAttempt(t *testing.T) {
 					TypedText:         sql.NullString{String: "Hello world", Valid: true},
 					TotalWords:        sql.NullInt32{Int32: 2, Valid: true},
 					CorrectWords:      sql.NullInt32{Int32: 2, Valid: true},
-					GrammaticalErrors: sql.NullInt32{Int32: 0, Valid: true},
+					WrongWordErrors:   sql.NullInt32{Int32: 0, Valid: true},
 					SpellingErrors:    sql.NullInt32{Int32: 0, Valid: true},
 					CaseErrors:        sql.NullInt32{Int32: 0, Valid: true},
+					PunctuationErrors: sql.NullInt32{Int32: 0, Valid: true},
+					OmissionErrors:    sql.NullInt32{Int32: 0, Valid: true},
+					InsertionErrors:   sql.NullInt32{Int32: 0, Valid: true},
+					ParagraphErrors:   sql.NullInt32{Int32: 0, Valid: true},
 					Accuracy:          sql.NullFloat64{Float64: 100.0, Valid: true},
-					ComparisonData:    pqtype.NullRawMessage{RawMessage: json.RawMessage("[]"), Valid: true},
+					WeightedAccuracy:  sql.NullFloat64{Float64: 100.0, Valid: true},
+					ComparisonData:    pqtype.NullRawMessage{RawMessage: comparisonData, Valid: true},
 					TimeSpent:         sql.NullFloat64{Float64: 10.5, Valid: true},
+					ScoringVersion:    sql.NullString{String: "wordalign/v7", Valid: true},
+					GrossWpm:          sql.NullFloat64{Float64: speed.GrossWPM, Valid: true},
+					NetWpm:            sql.NullFloat64{Float64: speed.NetWPM, Valid: true},
+					Cpm:               sql.NullFloat64{Float64: speed.CPM, Valid: true},
+					CharAccuracy:      sql.NullFloat64{Float64: 100, Valid: true},
+					Keystrokes:        sql.NullInt32{Int32: 13, Valid: true},
+					Kspc:              sql.NullFloat64{Float64: 13.0 / 11, Valid: true},
+					SessionID:         uuid.NullUUID{UUID: sessionID, Valid: true},
+					ClientTimeSpent:   sql.NullFloat64{Float64: 10.5, Valid: true},
+					InputEncoding:     "unicode",
 				}
 				// Mock GetDictation call
 				store.EXPECT().
@@ -90,6 +123,14 @@ func TestSubmitAttempt(t *testing.T) {
 						ID:       1,
 						Content:  sql.NullString{String: "Hello world", Valid: true},
 					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
 				// Mock SubmitAttemptTx call
 				store.EXPECT().
 					SubmitAttemptTx(gomock.Any(), gomock.Eq(arg)).
@@ -100,6 +141,610 @@ func TestSubmitAttempt(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "DroppedWordOnlyCostsOneWord",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "the brown fox jumps",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:      1,
+						Content: sql.NullString{String: "the quick brown fox jumps", Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, int32(5), arg.TotalWords.Int32)
+						require.Equal(t, int32(4), arg.CorrectWords.Int32)
+						require.InDelta(t, 80.0, arg.Accuracy.Float64, 0.001)
+						require.InDelta(t, 80.0, arg.WeightedAccuracy.Float64, 0.001)
+						require.Equal(t, int32(1), arg.OmissionErrors.Int32)
+						require.Equal(t, int32(0), arg.SpellingErrors.Int32)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "OK_Segment",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "thank you for your letter.",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:                 1,
+						Type:               sql.NullString{String: "audio", Valid: true},
+						Content:            sql.NullString{String: "Dear Sir, thank you for your letter.", Valid: true},
+						TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":900,"text":"Dear Sir,"},{"start_ms":900,"end_ms":3000,"text":"thank you for your letter."}]`), Valid: true},
+					}, nil)
+				// The session was started for segment 2
+				session := openSession(6)
+				session.Segment = sql.NullInt32{Int32: 2, Valid: true}
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(session, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						// Scored against the segment alone, so nothing is missing
+						require.Equal(t, int32(5), arg.TotalWords.Int32)
+						require.Equal(t, 100.0, arg.Accuracy.Float64)
+						require.Equal(t, sql.NullInt32{Int32: 2, Valid: true}, arg.Segment)
+
+						segmentAttempt := attempt
+						segmentAttempt.Segment = arg.Segment
+						return db.SubmitAttemptTxResult{Attempt: segmentAttempt}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp attemptResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int32(2), *rsp.Segment)
+				require.Nil(t, rsp.PerformanceUpdate)
+			},
+		},
+		{
+			name: "StenoRubricMarkSheet",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "the Quick brown fox",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:             1,
+						Content:        sql.NullString{String: "the quick brown fox jumps", Valid: true},
+						ScoringOptions: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"rubric":"steno"}`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.True(t, arg.MarkSheet.Valid)
+
+						var sheet scoring.MarkSheet
+						require.NoError(t, json.Unmarshal(arg.MarkSheet.RawMessage, &sheet))
+						require.Equal(t, "steno", sheet.Rubric)
+						require.Equal(t, 1, sheet.FullMistakes)
+						require.Equal(t, 1, sheet.HalfMistakes)
+						require.InDelta(t, 30.0, sheet.ErrorPercentage, 0.001)
+						require.False(t, sheet.Passed)
+
+						withSheet := result
+						withSheet.Attempt.MarkSheet = arg.MarkSheet
+						return withSheet, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got attemptResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.Contains(t, string(got.MarkSheet), `"passed":false`)
+			},
+		},
+		{
+			name: "UserToleranceApplied",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "We don't like gray skies",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:      1,
+						Content: sql.NullString{String: "We do not like grey skies", Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{
+						Tolerance: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"profile":"standard"}`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, int32(6), arg.CorrectWords.Int32)
+						require.InDelta(t, 100.0, arg.Accuracy.Float64, 0.001)
+						require.Contains(t, string(arg.ComparisonData.RawMessage), `"tolerance":"contraction"`)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DictationToleranceOverridesUser",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "We don't like gray skies",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:             1,
+						Content:        sql.NullString{String: "We do not like grey skies", Valid: true},
+						ScoringOptions: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"tolerance":{"profile":"strict"}}`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{
+						Tolerance: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"profile":"standard"}`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, int32(3), arg.CorrectWords.Int32)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "SpokenFormsAccepted",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Doctor Win arrived in nineteen ninety-eight",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:      1,
+						Content: sql.NullString{String: "Dr. Nguyen arrived in 1998", Valid: true},
+						Lexicon: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"written":"Nguyen","spoken":"Win"}]`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, int32(5), arg.CorrectWords.Int32)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "LayoutParagraphErrors",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Dear Sir, thank you.\nYours truly",
+				"time_spent":   10.5,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:             1,
+						Content:        sql.NullString{String: "Dear Sir,\n\nthank you.\n\nYours truly", Valid: true},
+						ScoringOptions: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"layout":true}`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, int32(6), arg.TotalWords.Int32)
+						require.Equal(t, int32(6), arg.CorrectWords.Int32)
+						require.InDelta(t, 100.0, arg.Accuracy.Float64, 0.001)
+						require.Equal(t, int32(2), arg.ParagraphErrors.Int32)
+						require.Contains(t, string(arg.ComparisonData.RawMessage), `"category":"paragraph"`)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			// Server time wins; a client claiming 2 seconds for a minute's work is flagged
+			name: "ClientTimeMismatchFlagged",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "the quick brown fox jumps",
+				"time_spent":   2,
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:      1,
+						Content: sql.NullString{String: "the quick brown fox jumps", Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(60), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, 60.0, arg.TimeSpent.Float64)
+						require.Equal(t, 2.0, arg.ClientTimeSpent.Float64)
+						require.True(t, arg.TimeFlagged)
+						// 25 characters in one minute
+						require.InDelta(t, 5.0, arg.GrossWpm.Float64, 0.001)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "KrutiDevInputConverted",
+			body: gin.H{
+				"dictation_id":   1,
+				"typed_text":     "Hkkjr dk;Z",
+				"input_encoding": "krutidev",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:       1,
+						Content:  sql.NullString{String: "भारत कार्य", Valid: true},
+						Language: sql.NullString{String: "hi-IN", Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: user.ID, Valid: true})).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
+						require.Equal(t, "भारत कार्य", arg.TypedText.String)
+						require.Equal(t, "krutidev", arg.InputEncoding)
+						require.Equal(t, 100.0, arg.Accuracy.Float64)
+						return result, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnsupportedInputEncoding",
+			body: gin.H{
+				"dictation_id":   1,
+				"typed_text":     "Hkkjr",
+				"input_encoding": "shivaji",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ReplayedSession",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Hello world",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Content: sql.NullString{String: "Hello world", Valid: true}}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(db.GetUnusedAttemptSessionRow{}, sql.ErrNoRows)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "SessionUsedWhileScoring",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Hello world",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Content: sql.NullString{String: "Hello world", Valid: true}}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+				// Another submission used the session first
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.SubmitAttemptTxResult{}, db.ErrAttemptSessionUsed)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "TypedTextTooLong",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   strings.Repeat("Hello world ", 30),
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Content: sql.NullString{String: "Hello world", Valid: true}}, nil)
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Eq(sessionID)).
+					Times(1).
+					Return(openSession(10.5), nil)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoTranscript",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Hello world",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Type: sql.NullString{String: "audio", Valid: true}}, nil)
+				// The session is left unused for when a transcript is added
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredSession",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Hello world",
+			},
+			session: func(t *testing.T, maker *token.SessionMaker) string {
+				return newAttemptSession(t, maker, sessionID, user.ID, 1, time.Now().Add(-2*time.Hour), time.Hour)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "SessionForAnotherDictation",
+			body: gin.H{
+				"dictation_id": 2,
+				"typed_text":   "Hello world",
+			},
+			session: validSession,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUnusedAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "MissingSession",
+			body: gin.H{
+				"dictation_id": 1,
+				"typed_text":   "Hello world",
+				"time_spent":   0.1,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SubmitAttemptTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -113,6 +758,9 @@ func TestSubmitAttempt(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
+			if tc.session != nil {
+				tc.body["session_token"] = tc.session(t, server.sessions)
+			}
 			data, err := json.Marshal(tc.body)
 			require.NoError(t, err)
 
@@ -125,3 +773,272 @@ func TestSubmitAttempt(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAttempt(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	comparisonData := json.RawMessage(`{"version":1,"tokens":[{"op":"match","original":"Hello","typed":"Hello"}]}`)
+
+	attempt := db.Attempt{
+		ID:             7,
+		UserID:         sql.NullInt64{Int64: user.ID, Valid: true},
+		DictationID:    sql.NullInt64{Int64: 1, Valid: true},
+		TypedText:      sql.NullString{String: "Hello", Valid: true},
+		Accuracy:       sql.NullFloat64{Float64: 100.0, Valid: true},
+		ComparisonData: pqtype.NullRawMessage{RawMessage: comparisonData, Valid: true},
+		CreatedAt:      sql.NullTime{Time: time.Now(), Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		attemptID     int64
+		userID        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			attemptID: attempt.ID,
+			userID:    user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAttemptById(gomock.Any(), gomock.Eq(attempt.ID)).
+					Times(1).
+					Return(attempt, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got attemptResponse
+				err := json.Unmarshal(recorder.Body.Bytes(), &got)
+				require.NoError(t, err)
+				require.Equal(t, attempt.ID, got.ID)
+				require.JSONEq(t, string(comparisonData), string(got.ComparisonData))
+			},
+		},
+		{
+			name:      "OtherUsersAttempt",
+			attemptID: attempt.ID,
+			userID:    user.ID + 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAttemptById(gomock.Any(), gomock.Eq(attempt.ID)).
+					Times(1).
+					Return(attempt, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:      "NotFound",
+			attemptID: attempt.ID,
+			userID:    user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetAttemptById(gomock.Any(), gomock.Eq(attempt.ID)).
+					Times(1).
+					Return(db.Attempt{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/attempts/%d", tc.attemptID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, "bearer", tc.userID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestStartAttempt(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"dictation_id": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Content: sql.NullString{String: "Hello world", Valid: true}}, nil)
+				store.EXPECT().
+					CreateAttemptSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptSessionParams) (db.AttemptSession, error) {
+						require.NotEqual(t, uuid.Nil, arg.ID)
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, int64(1), arg.DictationID)
+						return db.AttemptSession{
+							ID:          arg.ID,
+							UserID:      arg.UserID,
+							DictationID: arg.DictationID,
+							StartedAt:   time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp startAttemptResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+
+				payload, err := server.sessions.VerifySession(rsp.SessionToken)
+				require.NoError(t, err)
+				require.Equal(t, rsp.SessionID, payload.ID)
+				require.Equal(t, user.ID, payload.UserID)
+				require.Equal(t, int64(1), payload.DictationID)
+				require.WithinDuration(t, rsp.StartedAt.Add(defaultAttemptSessionDuration), rsp.ExpiresAt, time.Second)
+			},
+		},
+		{
+			name: "OK_Segment",
+			body: gin.H{"dictation_id": 1, "segment": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:                 1,
+						Content:            sql.NullString{String: "Dear Sir, thank you for your letter.", Valid: true},
+						TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":900,"text":"Dear Sir,"},{"start_ms":900,"end_ms":3000,"text":"thank you for your letter."}]`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					CreateAttemptSession(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAttemptSessionParams) (db.AttemptSession, error) {
+						require.Equal(t, sql.NullInt32{Int32: 2, Valid: true}, arg.Segment)
+						return db.AttemptSession{
+							ID:          arg.ID,
+							UserID:      arg.UserID,
+							DictationID: arg.DictationID,
+							Segment:     arg.Segment,
+							StartedAt:   time.Now(),
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp startAttemptResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, int32(2), rsp.Segment)
+			},
+		},
+		{
+			name: "NoSuchSegment",
+			body: gin.H{"dictation_id": 1, "segment": 3},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{
+						ID:                 1,
+						Content:            sql.NullString{String: "Dear Sir, thank you for your letter.", Valid: true},
+						TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":900,"text":"Dear Sir,"},{"start_ms":900,"end_ms":3000,"text":"thank you for your letter."}]`), Valid: true},
+					}, nil)
+				store.EXPECT().
+					CreateAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DictationNotFound",
+			body: gin.H{"dictation_id": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{}, sql.ErrNoRows)
+				store.EXPECT().
+					CreateAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NoTranscript",
+			body: gin.H{"dictation_id": 1},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(1))).
+					Times(1).
+					Return(db.Dictation{ID: 1, Type: sql.NullString{String: "audio", Valid: true}}, nil)
+				store.EXPECT().
+					CreateAttemptSession(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidDictationID",
+			body: gin.H{"dictation_id": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/attempts/start", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func newAttemptSession(t *testing.T, maker *token.SessionMaker, sessionID uuid.UUID, userID, dictationID int64, startedAt time.Time, duration time.Duration) string {
+	sessionToken, _, err := maker.CreateSession(sessionID, userID, dictationID, startedAt, duration)
+	require.NoError(t, err)
+	return sessionToken
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
+)
+
+// defaultUploadMaxBytes caps uploaded audio when UPLOAD_MAX_BYTES is unset;
+// about 50 minutes of 128 kbit/s MP3.
+const defaultUploadMaxBytes = 50 << 20
+
+// dictationAudioURL is where clients fetch a dictation's stored audio.
+func dictationAudioURL(dictationID int64) string {
+	return fmt.Sprintf("/dictations/%d/audio", dictationID)
+}
+
+type dictationAudioRequest struct {
+	ID int64 `uri:"id" binding:"required,min=1"`
+}
+
+// ownDictation loads the dictation in the URI and checks it belongs to the
+// authenticated user. On failure it writes the error response and returns
+// false.
+func (server *Server) ownDictation(ctx *gin.Context) (db.Dictation, bool) {
+	var req dictationAudioRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return db.Dictation{}, false
+	}
+
+	dictation, err := server.store.GetDictation(ctx, req.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("dictation not found")))
+			return db.Dictation{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return db.Dictation{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if dictation.UserID.Int64 != authPayload.UserID {
+		err := fmt.Errorf("dictation doesn't belong to the authenticated user")
+		ctx.JSON(http.StatusUnauthorized, errorResponse(err))
+		return db.Dictation{}, false
+	}
+	return dictation, true
+}
+
+type dictationAudioQuery struct {
+	// Speed plays the audio faster or slower without changing its pitch,
+	// rounded to a step of 0.05
+	Speed float64 `form:"speed" binding:"omitempty,min=0.5,max=2"`
+}
+
+// getDictationAudio streams a dictation's stored audio to its owner,
+// optionally time-stretched to another speed.
+func (server *Server) getDictationAudio(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	var query dictationAudioQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if server.blobs == nil || !dictation.AudioKey.Valid {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":        "dictation has no stored audio",
+			"audio_status": dictation.AudioStatus.String,
+		})
+		return
+	}
+
+	key := dictation.AudioKey.String
+	if speed := audio.RoundSpeed(query.Speed); query.Speed != 0 && math.Abs(speed-1) > audio.SpeedStep/2 {
+		if key, ok = server.speedVariant(ctx, key, dictation.ID, speed); !ok {
+			return
+		}
+	}
+
+	object, err := server.blobs.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer object.Close()
+
+	serveContent(ctx, object, object.ContentType, object.ETag, object.ModTime)
+}
+
+// serveContent writes content with its length and answers Range, If-Range,
+// If-None-Match and If-Modified-Since requests, so audio players can seek
+// and resume without downloading it again. An empty etag or zero modTime is
+// left out.
+func serveContent(ctx *gin.Context, content io.ReadSeeker, contentType, etag string, modTime time.Time) {
+	ctx.Header("Content-Type", contentType)
+	if etag != "" {
+		ctx.Header("ETag", etag)
+	}
+	http.ServeContent(ctx.Writer, ctx.Request, "", modTime, content)
+}
+
+// speedVariant returns the key of the audio at key stretched to speed. The
+// first request for a speed queues the stretch in the background and is
+// answered with 404 until it is stored, like a waveform. On failure it
+// writes the error response and returns false.
+func (server *Server) speedVariant(ctx *gin.Context, key string, dictationID int64, speed float64) (string, bool) {
+	variant := storage.VariantKey(key, jobs.SpeedVariant(speed))
+	object, err := server.blobs.Open(ctx, variant)
+	if err == nil {
+		object.Close()
+		return variant, true
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return "", false
+	}
+
+	// Refuse what the job would, rather than report it pending forever
+	object, err = server.blobs.Open(ctx, key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return "", false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return "", false
+	}
+	header := make([]byte, 12)
+	n, _ := io.ReadFull(object, header)
+	object.Close()
+	if !audio.IsWAV(header[:n]) {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(fmt.Errorf("only WAV audio can be played at another speed")))
+		return "", false
+	}
+	if object.Size > jobs.MaxStretchBytes {
+		ctx.JSON(http.StatusUnprocessableEntity, errorResponse(jobs.ErrTooLongToStretch))
+		return "", false
+	}
+
+	server.jobs.EnqueueSpeedVariant(dictationID, speed)
+	ctx.JSON(http.StatusNotFound, gin.H{
+		"error":        "audio at this speed is not ready yet",
+		"speed_status": jobs.AudioPending,
+	})
+	return "", false
+}
+
+// retryDictationAudio queues a text dictation's audio to be generated
+// again, after a failure or to replace audio made with an old voice.
+func (server *Server) retryDictationAudio(ctx *gin.Context) {
+	if server.jobs == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "audio generation is disabled"})
+		return
+	}
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	if dictation.Type.String != "text" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("only text dictations have generated audio")))
+		return
+	}
+	if dictation.AudioStatus.String == jobs.AudioPending {
+		ctx.JSON(http.StatusConflict, errorResponse(fmt.Errorf("audio is already being generated")))
+		return
+	}
+
+	dictation, err := server.store.QueueDictationAudio(ctx, dictation.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	server.jobs.Enqueue(dictation.ID)
+
+	ctx.JSON(http.StatusAccepted, newDictationResponse(dictation))
+}
+
+type uploadDictationRequest struct {
+	Title    string `form:"title" binding:"required"`
+	Language string `form:"language" binding:"required"`
+	// ScoringOptions is the JSON accepted as scoring_options by
+	// POST /dictations.
+	ScoringOptions string `form:"scoring_options"`
+	// Transcript is the reference text attempts are scored against; the
+	// dictation can't be scored until it has one.
+	Transcript string `form:"transcript"`
+	// TranscriptSegments is a JSON array of timed segments, as accepted by
+	// PUT /dictations/:id/transcript.
+	TranscriptSegments string `form:"transcript_segments"`
+	// Lexicon is a JSON array of pronunciation entries, as accepted by
+	// PUT /dictations/:id/lexicon. Scoring accepts their spoken forms.
+	Lexicon string `form:"lexicon"`
+}
+
+// uploadDictation creates an audio dictation from a WAV or MP3 file sent as
+// the "audio" field of a multipart form. The file's type is sniffed from its
+// content and its duration measured before it is stored.
+func (server *Server) uploadDictation(ctx *gin.Context) {
+	if server.blobs == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "audio storage is disabled"})
+		return
+	}
+	maxBytes := server.config.UploadMaxBytes
+	// Leave room for the other form fields and multipart framing
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxBytes+1<<20)
+
+	var req uploadDictationRequest
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(uploadErrorStatus(err), errorResponse(err))
+		return
+	}
+	var opts *scoring.Options
+	if req.ScoringOptions != "" {
+		if err := json.Unmarshal([]byte(req.ScoringOptions), &opts); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("invalid scoring_options: %w", err)))
+			return
+		}
+	}
+	scoringOptions, err := scoringOptionsParam(opts)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	segments, err := transcript.Parse([]byte(req.TranscriptSegments))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	entries, err := lexicon.Parse([]byte(req.Lexicon))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	dictationLexicon, err := lexiconParam(entries)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	header, err := ctx.FormFile("audio")
+	if err != nil {
+		ctx.JSON(uploadErrorStatus(err), errorResponse(err))
+		return
+	}
+	if header.Size > maxBytes {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("audio file is larger than %d bytes", maxBytes)))
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	info, err := audio.Probe(data)
+	if err != nil {
+		ctx.JSON(http.StatusUnsupportedMediaType, errorResponse(err))
+		return
+	}
+	content, transcriptSegments, err := transcriptParams(req.Transcript, segments, info.Duration.Milliseconds())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	userID := sql.NullInt64{Int64: authPayload.UserID, Valid: true}
+	key := fmt.Sprintf("uploads/%d/%s.%s", authPayload.UserID, uuid.New(), info.Format)
+	if err := server.blobs.Put(ctx, key, bytes.NewReader(data), int64(len(data)), info.ContentType); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	dictation, err := server.store.CreateAudioDictations(ctx, db.CreateAudioDictationsParams{
+		UserID:             userID,
+		Title:              sql.NullString{String: req.Title, Valid: true},
+		Language:           sql.NullString{String: req.Language, Valid: true},
+		ScoringOptions:     scoringOptions,
+		AudioKey:           sql.NullString{String: key, Valid: true},
+		AudioDurationMs:    sql.NullInt64{Int64: info.Duration.Milliseconds(), Valid: true},
+		Content:            content,
+		TranscriptSegments: transcriptSegments,
+		Lexicon:            dictationLexicon,
+	})
+	if err != nil {
+		server.blobs.Delete(ctx, key)
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	// The URL needs the new dictation's ID
+	dictation, err = server.store.UpdateDictation(ctx, db.UpdateDictationParams{
+		ID:       dictation.ID,
+		UserID:   userID,
+		AudioUrl: sql.NullString{String: dictationAudioURL(dictation.ID), Valid: true},
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	server.jobs.EnqueueWaveform(dictation.ID)
+	ctx.JSON(http.StatusOK, newDictationResponse(dictation))
+}
+
+// uploadErrorStatus distinguishes a request body over the size limit from
+// other malformed uploads.
+func uploadErrorStatus(err error) int {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
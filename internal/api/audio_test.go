@@ -0,0 +1,663 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// newTestServerWithStorage returns a test server that stores audio in a
+// temporary directory. Its background jobs are not started.
+func newTestServerWithStorage(t *testing.T, store db.Store) *Server {
+	config := util.Config{
+		TokenSymmetricKey:   util.RandomString(32),
+		AccessTokenDuration: time.Minute,
+		TTSProvider:         "fake",
+		StorageDir:          t.TempDir(),
+	}
+
+	server, err := NewServer(config, store)
+	require.NoError(t, err)
+	return server
+}
+
+func TestCreateTextDictationQueuesAudio(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUsers(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+	store.EXPECT().
+		CreateTextDictations(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateTextDictationsParams) (db.Dictation, error) {
+			require.Equal(t, sql.NullString{String: jobs.AudioPending, Valid: true}, arg.AudioStatus)
+			return db.Dictation{
+				ID:          1,
+				Type:        sql.NullString{String: "text", Valid: true},
+				Content:     arg.Content,
+				AudioStatus: arg.AudioStatus,
+			}, nil
+		})
+
+	server := newTestServerWithStorage(t, store)
+	recorder := httptest.NewRecorder()
+	data, err := json.Marshal(gin.H{"title": "Letter", "type": "text", "content": "Dear Sir", "language": "en-US"})
+	require.NoError(t, err)
+	request, err := http.NewRequest(http.MethodPost, "/dictations", bytes.NewReader(data))
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp dictationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, jobs.AudioPending, rsp.AudioStatus)
+}
+
+func TestGetDictationAudio(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := db.Dictation{
+		ID:          5,
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Type:        sql.NullString{String: "text", Valid: true},
+		AudioKey:    sql.NullString{String: "dictations/5/speech.wav", Valid: true},
+		AudioStatus: sql.NullString{String: jobs.AudioReady, Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "bytes", recorder.Header().Get("Accept-Ranges"))
+				require.Equal(t, "10", recorder.Header().Get("Content-Length"))
+				require.NotEmpty(t, recorder.Header().Get("ETag"))
+				require.NotEmpty(t, recorder.Header().Get("Last-Modified"))
+				require.Equal(t, "RIFF audio", recorder.Body.String())
+			},
+		},
+		{
+			name: "Range",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				request.Header.Set("Range", "bytes=5-")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPartialContent, recorder.Code)
+				require.Equal(t, "bytes 5-9/10", recorder.Header().Get("Content-Range"))
+				require.Equal(t, "5", recorder.Header().Get("Content-Length"))
+				require.Equal(t, "audio", recorder.Body.String())
+			},
+		},
+		{
+			name: "StaleIfRange",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				request.Header.Set("Range", "bytes=5-")
+				request.Header.Set("If-Range", `"stale"`)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "RIFF audio", recorder.Body.String())
+			},
+		},
+		{
+			name: "NotModified",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				request.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Zero(t, recorder.Body.Len())
+			},
+		},
+		{
+			name: "StillPending",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				pending := dictation
+				pending.AudioKey = sql.NullString{}
+				pending.AudioStatus = sql.NullString{String: jobs.AudioPending, Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(pending, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"audio_status":"pending"`)
+			},
+		},
+		{
+			name: "OtherUsersDictation",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID+1, "someone-else", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(db.Dictation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			audio := "RIFF audio"
+			err := server.blobs.Put(context.Background(), dictation.AudioKey.String, strings.NewReader(audio), int64(len(audio)), "audio/wav")
+			require.NoError(t, err)
+
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/dictations/%d/audio", dictation.ID)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetDictationAudioSpeed(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := db.Dictation{
+		ID:       6,
+		UserID:   sql.NullInt64{Int64: user.ID, Valid: true},
+		Type:     sql.NullString{String: "audio", Valid: true},
+		AudioKey: sql.NullString{String: "uploads/1/passage.wav", Valid: true},
+	}
+
+	// One second of 8 kHz mono tone
+	recording := audio.PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 16000)}
+	for i := 0; i < len(recording.Data); i += 2 {
+		v := int16(8000 * math.Sin(2*math.Pi*440*float64(i/2)/8000))
+		recording.Data[i], recording.Data[i+1] = byte(v), byte(v>>8)
+	}
+	wav := audio.EncodeWAV(recording)
+
+	testCases := []struct {
+		name          string
+		query         string
+		original      []byte
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "Slower",
+			query:    "?speed=0.8",
+			original: wav,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder) {
+				// The first request queues the stretch
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				var rsp gin.H
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, jobs.AudioPending, rsp["speed_status"])
+
+				require.NoError(t, server.jobs.GenerateSpeedVariant(context.Background(), dictation.ID, 0.8))
+				recorder = get()
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+
+				clip, err := audio.DecodeWAV(recorder.Body.Bytes())
+				require.NoError(t, err)
+				require.InDelta(t, 1250*time.Millisecond, clip.Duration(), float64(10*time.Millisecond))
+			},
+		},
+		{
+			name:     "RoundedToStep",
+			query:    "?speed=1.23",
+			original: wav,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.NoError(t, server.jobs.GenerateSpeedVariant(context.Background(), dictation.ID, 1.25))
+				object, err := server.blobs.Open(context.Background(), "uploads/1/passage.x1.25.wav")
+				require.NoError(t, err)
+				object.Close()
+				require.Equal(t, http.StatusOK, get().Code)
+			},
+		},
+		{
+			name:     "NormalSpeed",
+			query:    "?speed=1",
+			original: wav,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, wav, recorder.Body.Bytes())
+			},
+		},
+		{
+			name:     "TooFast",
+			query:    "?speed=3",
+			original: wav,
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "NotWAV",
+			query:    "?speed=1.2",
+			original: []byte("ID3 mp3 audio"),
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder, get func() *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+				AnyTimes().
+				Return(dictation, nil)
+
+			server := newTestServerWithStorage(t, store)
+			err := server.blobs.Put(context.Background(), dictation.AudioKey.String, bytes.NewReader(tc.original), int64(len(tc.original)), "audio/wav")
+			require.NoError(t, err)
+
+			get := func() *httptest.ResponseRecorder {
+				recorder := httptest.NewRecorder()
+				url := fmt.Sprintf("/dictations/%d/audio%s", dictation.ID, tc.query)
+				request, err := http.NewRequest(http.MethodGet, url, nil)
+				require.NoError(t, err)
+
+				addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				server.router.ServeHTTP(recorder, request)
+				return recorder
+			}
+			tc.checkResponse(t, server, get(), get)
+		})
+	}
+}
+
+func TestRetryDictationAudio(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	failed := db.Dictation{
+		ID:          5,
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		Type:        sql.NullString{String: "text", Valid: true},
+		Content:     sql.NullString{String: "Dear Sir", Valid: true},
+		AudioStatus: sql.NullString{String: jobs.AudioFailed, Valid: true},
+		AudioError:  sql.NullString{String: "provider unavailable", Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				queued := failed
+				queued.AudioStatus = sql.NullString{String: jobs.AudioPending, Valid: true}
+				queued.AudioError = sql.NullString{}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(failed.ID)).
+					Times(1).
+					Return(failed, nil)
+				store.EXPECT().
+					QueueDictationAudio(gomock.Any(), gomock.Eq(failed.ID)).
+					Times(1).
+					Return(queued, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, jobs.AudioPending, rsp.AudioStatus)
+				require.Empty(t, rsp.AudioError)
+			},
+		},
+		{
+			name: "AlreadyPending",
+			buildStubs: func(store *mockdb.MockStore) {
+				pending := failed
+				pending.AudioStatus = sql.NullString{String: jobs.AudioPending, Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(failed.ID)).
+					Times(1).
+					Return(pending, nil)
+				store.EXPECT().
+					QueueDictationAudio(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusConflict, recorder.Code)
+			},
+		},
+		{
+			name: "AudioDictation",
+			buildStubs: func(store *mockdb.MockStore) {
+				audio := failed
+				audio.Type = sql.NullString{String: "audio", Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(failed.ID)).
+					Times(1).
+					Return(audio, nil)
+				store.EXPECT().
+					QueueDictationAudio(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			recorder := httptest.NewRecorder()
+			url := fmt.Sprintf("/dictations/%d/audio/retry", failed.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// multipartUpload builds a multipart body with the given form fields and,
+// unless audio is nil, an "audio" file part.
+func multipartUpload(t *testing.T, fields map[string]string, audio []byte) (*bytes.Buffer, string) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, writer.WriteField(name, value))
+	}
+	if audio != nil {
+		part, err := writer.CreateFormFile("audio", "passage.wav")
+		require.NoError(t, err)
+		_, err = part.Write(audio)
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+	return body, writer.FormDataContentType()
+}
+
+func TestUploadDictation(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	userID := sql.NullInt64{Int64: user.ID, Valid: true}
+	fields := map[string]string{"title": "Exam passage", "language": "en-US"}
+
+	// One second of 16 kHz mono silence
+	wav := audio.EncodeWAV(audio.Silence(audio.PCM{SampleRate: 16000, Channels: 1, BitsPerSample: 16}, time.Second))
+
+	testCases := []struct {
+		name          string
+		fields        map[string]string
+		audio         []byte
+		maxBytes      int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			fields: map[string]string{"title": "Exam passage", "language": "en-US", "scoring_options": `{"rubric":"steno"}`, "transcript": "Dear Sir,"},
+			audio:  wav,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateAudioDictationsParams) (db.Dictation, error) {
+						require.Equal(t, userID, arg.UserID)
+						require.Equal(t, "Exam passage", arg.Title.String)
+						require.JSONEq(t, `{"rubric":"steno"}`, string(arg.ScoringOptions.RawMessage))
+						require.Regexp(t, fmt.Sprintf(`^uploads/%d/[0-9a-f-]{36}\.wav$`, user.ID), arg.AudioKey.String)
+						require.Equal(t, int64(1000), arg.AudioDurationMs.Int64)
+						require.Equal(t, "Dear Sir,", arg.Content.String)
+						return db.Dictation{
+							ID:              3,
+							UserID:          arg.UserID,
+							Type:            sql.NullString{String: "audio", Valid: true},
+							AudioKey:        arg.AudioKey,
+							AudioDurationMs: arg.AudioDurationMs,
+						}, nil
+					})
+				store.EXPECT().
+					UpdateDictation(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.UpdateDictationParams) (db.Dictation, error) {
+						require.Equal(t, int64(3), arg.ID)
+						require.Equal(t, userID, arg.UserID)
+						return db.Dictation{
+							ID:              3,
+							UserID:          userID,
+							Type:            sql.NullString{String: "audio", Valid: true},
+							AudioUrl:        arg.AudioUrl,
+							AudioDurationMs: sql.NullInt64{Int64: 1000, Valid: true},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, "/dictations/3/audio", rsp.AudioURL)
+				require.Equal(t, int64(1000), rsp.AudioDurationMs)
+			},
+		},
+		{
+			name:   "NotAudio",
+			fields: fields,
+			audio:  []byte("%PDF-1.7 pretending to be audio"),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnsupportedMediaType, recorder.Code)
+			},
+		},
+		{
+			name:   "SegmentPastEndOfAudio",
+			fields: map[string]string{"title": "Exam passage", "language": "en-US", "transcript_segments": `[{"start_ms":0,"end_ms":2000,"text":"Dear Sir,"}]`},
+			audio:  wav,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "TooLarge",
+			fields:   fields,
+			audio:    wav,
+			maxBytes: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:     "BodyOverLimit",
+			fields:   fields,
+			audio:    bytes.Repeat(wav, 40),
+			maxBytes: 1000,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingFile",
+			fields: fields,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidScoringOptions",
+			fields: map[string]string{"title": "Exam passage", "language": "en-US", "scoring_options": `{"rubric":"olympic"}`},
+			audio:  wav,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, server *Server, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			if tc.maxBytes > 0 {
+				server.config.UploadMaxBytes = tc.maxBytes
+			}
+			recorder := httptest.NewRecorder()
+
+			body, contentType := multipartUpload(t, tc.fields, tc.audio)
+			request, err := http.NewRequest(http.MethodPost, "/dictations/upload", body)
+			require.NoError(t, err)
+			request.Header.Set("Content-Type", contentType)
+
+			addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, server, recorder)
+		})
+	}
+}
+
+func TestUploadDictationStorageDisabled(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+	body, contentType := multipartUpload(t, map[string]string{"title": "t", "language": "en"}, []byte("RIFF"))
+	request, err := http.NewRequest(http.MethodPost, "/dictations/upload", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", contentType)
+	addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
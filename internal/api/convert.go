@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nilesh0729/PixelScribe/internal/legacyfont"
+)
+
+type convertRequest struct {
+	Text string `json:"text" binding:"required"`
+	// From is the legacy encoding of Text, e.g. "krutidev" or "devlys"
+	From string `json:"from" binding:"required"`
+}
+
+type convertResponse struct {
+	Text string `json:"text"`
+	From string `json:"from"`
+}
+
+// convertText converts text typed in a legacy Hindi font to Unicode.
+func (server *Server) convertText(ctx *gin.Context) {
+	var req convertRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	encoding, err := legacyfont.Parse(req.From)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	text, err := legacyfont.ToUnicode(req.Text, encoding)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convertResponse{Text: text, From: string(encoding)})
+}
@@ -2,21 +2,32 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
 	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
+	"github.com/sqlc-dev/pqtype"
 )
 
 type createDictationRequest struct {
-	UserID   int64  `json:"user_id"`
-	Title    string `json:"title" binding:"required"`
-	Type     string `json:"type" binding:"required,oneof=text audio"`
-	Content  string `json:"content"`   // Required for text
-	AudioURL string `json:"audio_url"` // Required for audio
-	Language string `json:"language" binding:"required"`
+	UserID         int64            `json:"user_id"`
+	Title          string           `json:"title" binding:"required"`
+	Type           string           `json:"type" binding:"required,oneof=text audio"`
+	Content        string           `json:"content"`   // Required for text; the reference transcript for audio
+	AudioURL       string           `json:"audio_url"` // Required for audio
+	Language       string           `json:"language" binding:"required"`
+	ScoringOptions *scoring.Options `json:"scoring_options"`
+	// Optional timing of an audio dictation's transcript
+	TranscriptSegments []transcript.Segment `json:"transcript_segments"`
+	// Optional pronunciations for this dictation, winning over the user's
+	Lexicon []lexicon.Entry `json:"lexicon"`
 }
 // ... func newDictationResponse ...
 // ... func createDictation ... (will be replaced in next call or same call if contiguous)
@@ -26,29 +37,59 @@ type createDictationRequest struct {
 // I'll use multi_replace to be efficient.
 
 type dictationResponse struct {
-	ID        int64     `json:"id"`
-	UserID    int64     `json:"user_id"`
-	Title     string    `json:"title"`
-	Type      string    `json:"type"`
-	Content   string    `json:"content,omitempty"`
-	AudioURL  string    `json:"audio_url,omitempty"`
-	Language  string    `json:"language"`
-	CreatedAt time.Time `json:"created_at"`
+	ID                 int64           `json:"id"`
+	UserID             int64           `json:"user_id"`
+	Title              string          `json:"title"`
+	Type               string          `json:"type"`
+	Content            string          `json:"content,omitempty"`
+	AudioURL           string          `json:"audio_url,omitempty"`
+	AudioStatus        string          `json:"audio_status,omitempty"` // pending, ready or failed for generated audio
+	AudioError         string          `json:"audio_error,omitempty"`
+	AudioDurationMs    int64           `json:"audio_duration_ms,omitempty"`
+	Scorable           bool            `json:"scorable"` // false for audio dictations without a transcript
+	TranscriptSegments json.RawMessage `json:"transcript_segments,omitempty"`
+	Language           string          `json:"language"`
+	ScoringOptions     json.RawMessage `json:"scoring_options,omitempty"`
+	Lexicon            json.RawMessage `json:"lexicon,omitempty"`
+	CreatedAt          time.Time       `json:"created_at"`
 }
 
 func newDictationResponse(d db.Dictation) dictationResponse {
 	return dictationResponse{
-		ID:        d.ID,
-		UserID:    d.UserID.Int64,
-		Title:     d.Title.String,
-		Type:      d.Type.String,
-		Content:   d.Content.String,
-		AudioURL:  d.AudioUrl.String,
-		Language:  d.Language.String,
-		CreatedAt: d.CreatedAt,
+		ID:                 d.ID,
+		UserID:             d.UserID.Int64,
+		Title:              d.Title.String,
+		Type:               d.Type.String,
+		Content:            d.Content.String,
+		AudioURL:           d.AudioUrl.String,
+		AudioStatus:        d.AudioStatus.String,
+		AudioError:         d.AudioError.String,
+		AudioDurationMs:    d.AudioDurationMs.Int64,
+		Scorable:           scorable(d),
+		TranscriptSegments: d.TranscriptSegments.RawMessage,
+		Language:           d.Language.String,
+		ScoringOptions:     d.ScoringOptions.RawMessage,
+		Lexicon:            d.Lexicon.RawMessage,
+		CreatedAt:          d.CreatedAt,
 	}
 }
 
+// scoringOptionsParam validates a dictation's scoring options and encodes
+// them for storage. Nil options are stored as NULL.
+func scoringOptionsParam(opts *scoring.Options) (pqtype.NullRawMessage, error) {
+	if opts == nil {
+		return pqtype.NullRawMessage{}, nil
+	}
+	if err := opts.Validate(); err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	data, err := json.Marshal(opts)
+	if err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	return pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
 func (server *Server) createDictation(ctx *gin.Context) {
 	var req createDictationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
@@ -56,6 +97,17 @@ func (server *Server) createDictation(ctx *gin.Context) {
 		return
 	}
 
+	scoringOptions, err := scoringOptionsParam(req.ScoringOptions)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	dictationLexicon, err := lexiconParam(req.Lexicon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 	user, err := server.store.GetUsers(ctx, authPayload.Username)
 	if err != nil {
@@ -71,10 +123,15 @@ func (server *Server) createDictation(ctx *gin.Context) {
 			return
 		}
 		arg := db.CreateTextDictationsParams{
-			UserID:   sql.NullInt64{Int64: user.ID, Valid: true},
-			Title:    sql.NullString{String: req.Title, Valid: true},
-			Content:  sql.NullString{String: req.Content, Valid: true},
-			Language: sql.NullString{String: req.Language, Valid: true},
+			UserID:         sql.NullInt64{Int64: user.ID, Valid: true},
+			Title:          sql.NullString{String: req.Title, Valid: true},
+			Content:        sql.NullString{String: req.Content, Valid: true},
+			Language:       sql.NullString{String: req.Language, Valid: true},
+			ScoringOptions: scoringOptions,
+			Lexicon:        dictationLexicon,
+		}
+		if server.jobs != nil {
+			arg.AudioStatus = sql.NullString{String: jobs.AudioPending, Valid: true}
 		}
 		dictation, err = server.store.CreateTextDictations(ctx, arg)
 	} else if req.Type == "audio" {
@@ -82,11 +139,21 @@ func (server *Server) createDictation(ctx *gin.Context) {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": "audio_url is required for audio dictation"})
 			return
 		}
+		// Without a transcript the dictation is saved but can't be scored
+		content, segments, err := transcriptParams(req.Content, req.TranscriptSegments, 0)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
 		arg := db.CreateAudioDictationsParams{
-			UserID:   sql.NullInt64{Int64: user.ID, Valid: true},
-			Title:    sql.NullString{String: req.Title, Valid: true},
-			AudioUrl: sql.NullString{String: req.AudioURL, Valid: true},
-			Language: sql.NullString{String: req.Language, Valid: true},
+			UserID:             sql.NullInt64{Int64: user.ID, Valid: true},
+			Title:              sql.NullString{String: req.Title, Valid: true},
+			AudioUrl:           sql.NullString{String: req.AudioURL, Valid: true},
+			Language:           sql.NullString{String: req.Language, Valid: true},
+			ScoringOptions:     scoringOptions,
+			Content:            content,
+			TranscriptSegments: segments,
+			Lexicon:            dictationLexicon,
 		}
 		dictation, err = server.store.CreateAudioDictations(ctx, arg)
 	}
@@ -95,6 +162,9 @@ func (server *Server) createDictation(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if dictation.AudioStatus.String == jobs.AudioPending {
+		server.jobs.Enqueue(dictation.ID)
+	}
 
 	ctx.JSON(http.StatusOK, newDictationResponse(dictation))
 }
@@ -160,12 +230,29 @@ func (server *Server) deleteDictation(ctx *gin.Context) {
 		return
 	}
 
+	// Stored audio goes with the dictation
+	var audioKey sql.NullString
+	if server.blobs != nil {
+		dictation, err := server.store.GetDictation(ctx, req.ID)
+		if err != nil && err != sql.ErrNoRows {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		audioKey = dictation.AudioKey
+	}
+
 	// Use Transaction for deleting dictation (cascading)
 	err := server.store.DeleteDictationTx(ctx, req.ID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
+	if audioKey.Valid {
+		if err := jobs.DeleteAudio(ctx, server.blobs, audioKey.String); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+	}
 
 	ctx.JSON(http.StatusOK, gin.H{"status": "deleted"})
 }
@@ -14,6 +14,7 @@ import (
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
 	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
 	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -156,6 +157,162 @@ func TestCreateDictation(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "OK_StenoRubric",
+			body: gin.H{
+				"title":           "Test Dictation",
+				"type":            "text",
+				"content":         "Content",
+				"language":        "en-US",
+				"scoring_options": gin.H{"rubric": "steno", "pass_threshold": 7},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateTextDictationsParams{
+					UserID:         sql.NullInt64{Int64: 0, Valid: true},
+					Title:          sql.NullString{String: "Test Dictation", Valid: true},
+					Content:        sql.NullString{String: "Content", Valid: true},
+					Language:       sql.NullString{String: "en-US", Valid: true},
+					ScoringOptions: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"rubric":"steno","pass_threshold":7}`), Valid: true},
+				}
+				store.EXPECT().
+					GetUsers(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateTextDictations(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(d, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownRubric",
+			body: gin.H{
+				"title":           "Test Dictation",
+				"type":            "text",
+				"content":         "Content",
+				"language":        "en-US",
+				"scoring_options": gin.H{"rubric": "nope"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateTextDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "OK_AudioWithTranscript",
+			body: gin.H{
+				"title":     "Test Dictation",
+				"type":      "audio",
+				"audio_url": "https://example.com/passage.mp3",
+				"language":  "en-US",
+				"transcript_segments": []gin.H{
+					{"start_ms": 0, "end_ms": 1500, "text": "Dear Sir,"},
+					{"start_ms": 1500, "end_ms": 4000, "text": "thank you."},
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.CreateAudioDictationsParams{
+					UserID:             sql.NullInt64{Int64: 0, Valid: true},
+					Title:              sql.NullString{String: "Test Dictation", Valid: true},
+					AudioUrl:           sql.NullString{String: "https://example.com/passage.mp3", Valid: true},
+					Language:           sql.NullString{String: "en-US", Valid: true},
+					Content:            sql.NullString{String: "Dear Sir, thank you.", Valid: true},
+					TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":1500,"text":"Dear Sir,"},{"start_ms":1500,"end_ms":4000,"text":"thank you."}]`), Valid: true},
+				}
+				store.EXPECT().
+					GetUsers(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Dictation{ID: 2, Type: sql.NullString{String: "audio", Valid: true}, Content: arg.Content}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Scorable)
+			},
+		},
+		{
+			name: "OK_AudioWithoutTranscript",
+			body: gin.H{
+				"title":     "Test Dictation",
+				"type":      "audio",
+				"audio_url": "https://example.com/passage.mp3",
+				"language":  "en-US",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUsers(gomock.Any(), gomock.Eq(user.Username)).
+					Times(1).
+					Return(user, nil)
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ interface{}, arg db.CreateAudioDictationsParams) (db.Dictation, error) {
+						require.False(t, arg.Content.Valid)
+						require.False(t, arg.TranscriptSegments.Valid)
+						return db.Dictation{ID: 2, AudioUrl: arg.AudioUrl}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.False(t, rsp.Scorable)
+			},
+		},
+		{
+			name: "AudioTranscriptMismatch",
+			body: gin.H{
+				"title":     "Test Dictation",
+				"type":      "audio",
+				"audio_url": "https://example.com/passage.mp3",
+				"content":   "Dear Madam,",
+				"language":  "en-US",
+				"transcript_segments": []gin.H{
+					{"start_ms": 0, "end_ms": 1500, "text": "Dear Sir,"},
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUsers(gomock.Any(), gomock.Any()).
+					AnyTimes().
+					Return(user, nil)
+				store.EXPECT().
+					CreateAudioDictations(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,88 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// lexiconParam validates a pronunciation lexicon and encodes it for storage.
+// An empty lexicon is stored as NULL.
+func lexiconParam(entries []lexicon.Entry) (pqtype.NullRawMessage, error) {
+	if len(entries) == 0 {
+		return pqtype.NullRawMessage{}, nil
+	}
+	if err := lexicon.Validate(entries); err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return pqtype.NullRawMessage{}, err
+	}
+	return pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
+// parseLexicons decodes stored lexicons, keeping their order.
+func parseLexicons(stored ...pqtype.NullRawMessage) ([][]lexicon.Entry, error) {
+	lexicons := make([][]lexicon.Entry, len(stored))
+	for i, data := range stored {
+		entries, err := lexicon.Parse(data.RawMessage)
+		if err != nil {
+			return nil, err
+		}
+		lexicons[i] = entries
+	}
+	return lexicons, nil
+}
+
+type setLexiconRequest struct {
+	// Lexicon replaces the dictation's; empty removes it
+	Lexicon []lexicon.Entry `json:"lexicon"`
+}
+
+// setDictationLexicon replaces how a dictation's words are read aloud. Its
+// entries win over the user's own lexicon. A text dictation's generated
+// audio is made again with the new lexicon.
+func (server *Server) setDictationLexicon(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+
+	var req setLexiconRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	entries, err := lexiconParam(req.Lexicon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dictation, err = server.store.SetDictationLexicon(ctx, db.SetDictationLexiconParams{
+		ID:      dictation.ID,
+		UserID:  dictation.UserID,
+		Lexicon: entries,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if dictation.Type.String == "text" && server.jobs != nil {
+		// Audio still being made with the old lexicon is discarded when
+		// it completes
+		dictation, err = server.store.QueueDictationAudio(ctx, dictation.ID)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		server.jobs.Enqueue(dictation.ID)
+	}
+
+	ctx.JSON(http.StatusOK, newDictationResponse(dictation))
+}
@@ -0,0 +1,174 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetDictationLexicon(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	userID := sql.NullInt64{Int64: user.ID, Valid: true}
+	dictation := db.Dictation{
+		ID:          8,
+		UserID:      userID,
+		Type:        sql.NullString{String: "text", Valid: true},
+		Content:     sql.NullString{String: "Dr. Nguyen will see you.", Valid: true},
+		AudioStatus: sql.NullString{String: jobs.AudioReady, Valid: true},
+	}
+	entries := []gin.H{{"written": "Nguyen", "spoken": "Win"}}
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"lexicon": entries},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				updated := dictation
+				store.EXPECT().
+					SetDictationLexicon(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetDictationLexiconParams) (db.Dictation, error) {
+						require.Equal(t, dictation.ID, arg.ID)
+						require.Equal(t, userID, arg.UserID)
+						require.JSONEq(t, `[{"written":"Nguyen","spoken":"Win"}]`, string(arg.Lexicon.RawMessage))
+						updated.Lexicon = arg.Lexicon
+						return updated, nil
+					})
+				// The audio is read again with the new lexicon
+				store.EXPECT().
+					QueueDictationAudio(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					DoAndReturn(func(_ context.Context, _ int64) (db.Dictation, error) {
+						queued := updated
+						queued.AudioStatus = sql.NullString{String: jobs.AudioPending, Valid: true}
+						return queued, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, jobs.AudioPending, rsp.AudioStatus)
+				require.JSONEq(t, `[{"written":"Nguyen","spoken":"Win"}]`, string(rsp.Lexicon))
+			},
+		},
+		{
+			name: "ClearOnAudioDictation",
+			body: gin.H{"lexicon": []gin.H{}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				uploaded := dictation
+				uploaded.Type = sql.NullString{String: "audio", Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(uploaded, nil)
+				store.EXPECT().
+					SetDictationLexicon(gomock.Any(), gomock.Eq(db.SetDictationLexiconParams{ID: dictation.ID, UserID: userID})).
+					Times(1).
+					Return(uploaded, nil)
+				store.EXPECT().
+					QueueDictationAudio(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateEntry",
+			body: gin.H{"lexicon": []gin.H{
+				{"written": "Nguyen", "spoken": "Win"},
+				{"written": "NGUYEN", "spoken": "Nwin"},
+			}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationLexicon(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnauthorizedUser",
+			body: gin.H{"lexicon": entries},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID+1, "other", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationLexicon(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/dictations/%d/lexicon", dictation.ID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
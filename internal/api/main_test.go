@@ -18,6 +18,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		TTSProvider:         "fake",
 	}
 
 	server, err := NewServer(config, store)
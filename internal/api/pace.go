@@ -0,0 +1,245 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+)
+
+type pacedDictationQuery struct {
+	WPM   float64 `form:"wpm" binding:"required"`
+	Voice string  `form:"voice"`
+	Speed float64 `form:"speed"`
+}
+
+type phraseTimingResponse struct {
+	Text    string `json:"text"`
+	Words   int    `json:"words"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+}
+
+type pacedTimingResponse struct {
+	DictationID int64                  `json:"dictation_id"`
+	Voice       string                 `json:"voice"`
+	Speed       float64                `json:"speed"`
+	TargetWPM   float64                `json:"target_wpm"`
+	WPM         float64                `json:"wpm"`
+	DurationMs  int64                  `json:"duration_ms"`
+	Phrases     []phraseTimingResponse `json:"phrases"`
+}
+
+// pacedDictation is a text dictation to be read at a target WPM. Request
+// holds the text as written, which sets the phrases and the words counted
+// towards the WPM; Verbalize gives what is read aloud for each phrase.
+type pacedDictation struct {
+	DictationID int64
+	Request     tts.Request
+	Verbalize   func(string) string
+	TargetWPM   float64
+}
+
+// key names the stored timing of the paced audio. It hashes everything the
+// audio depends on, the written text for the phrases and the spoken text for
+// the lexicons read with it, so equal requests share it whichever dictation
+// they come from.
+func (p pacedDictation) key(provider tts.Provider) string {
+	spoken := p.Request
+	spoken.Text = p.Verbalize(p.Request.Text)
+	sum := sha256.Sum256([]byte(tts.Key(provider, p.Request) + tts.Key(provider, spoken)))
+	return fmt.Sprintf("paced/%s-%s.json", hex.EncodeToString(sum[:]), strconv.FormatFloat(p.TargetWPM, 'f', -1, 64))
+}
+
+// pacedRecord is the stored timing of paced audio. It names the WAV it
+// describes, so the timing always matches the audio served with it even
+// when the provider reads the same text differently a second time.
+type pacedRecord struct {
+	AudioKey   string                 `json:"audio_key"`
+	WPM        float64                `json:"wpm"`
+	DurationMs int64                  `json:"duration_ms"`
+	Phrases    []phraseTimingResponse `json:"phrases"`
+}
+
+func newPacedRecord(paced tts.Paced) pacedRecord {
+	record := pacedRecord{
+		WPM:        paced.WPM,
+		DurationMs: paced.Duration.Milliseconds(),
+		Phrases:    make([]phraseTimingResponse, len(paced.Phrases)),
+	}
+	for i, p := range paced.Phrases {
+		record.Phrases[i] = phraseTimingResponse{
+			Text:    p.Text,
+			Words:   p.Words,
+			StartMs: p.Start.Milliseconds(),
+			EndMs:   p.End.Milliseconds(),
+		}
+	}
+	return record
+}
+
+// paceDictation reads the authenticated user's dictation in the URI at the
+// WPM in the query string. Paced audio is only made to be stored, so it
+// needs storage configured. On failure it writes the error response and
+// returns false.
+func (server *Server) paceDictation(ctx *gin.Context) (pacedDictation, bool) {
+	if server.blobs == nil {
+		ctx.JSON(http.StatusServiceUnavailable, gin.H{"error": "audio storage is disabled"})
+		return pacedDictation{}, false
+	}
+	var query pacedDictationQuery
+	if err := ctx.ShouldBindQuery(&query); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return pacedDictation{}, false
+	}
+	if query.WPM < tts.MinWPM || query.WPM > tts.MaxWPM {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("wpm must be between %d and %d", tts.MinWPM, tts.MaxWPM)))
+		return pacedDictation{}, false
+	}
+	ttsReq := tts.Request{Voice: query.Voice, Speed: query.Speed}
+	if err := tts.Validate(server.tts, ttsReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return pacedDictation{}, false
+	}
+
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return pacedDictation{}, false
+	}
+	if dictation.Type.String != "text" || dictation.Content.String == "" {
+		ctx.JSON(http.StatusBadRequest, errorResponse(fmt.Errorf("only text dictations can be paced")))
+		return pacedDictation{}, false
+	}
+
+	ttsReq.Text = dictation.Content.String
+	ttsReq, verbalize, err := server.userReading(ctx, dictation.UserID.Int64, ttsReq, dictation.Language.String, dictation.Lexicon)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pacedDictation{}, false
+	}
+	return pacedDictation{DictationID: dictation.ID, Request: ttsReq, Verbalize: verbalize, TargetWPM: query.WPM}, true
+}
+
+// pace reads p aloud. On failure it writes the error response and returns
+// false.
+func (server *Server) pace(ctx *gin.Context, p pacedDictation) (tts.Paced, bool) {
+	paced, err := tts.Pace(ctx, server.tts, p.Request, p.TargetWPM, server.config.TTSConcurrency, p.Verbalize)
+	if err != nil {
+		var apiErr *tts.APIError
+		switch {
+		case errors.Is(err, tts.ErrPaceTooFast):
+			ctx.JSON(http.StatusUnprocessableEntity, errorResponse(err))
+		case errors.As(err, &apiErr):
+			ctx.JSON(http.StatusBadGateway, gin.H{
+				"error":       "TTS provider failed",
+				"details":     apiErr.Body,
+				"status_code": apiErr.StatusCode,
+			})
+		default:
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		}
+		return tts.Paced{}, false
+	}
+	return paced, true
+}
+
+// loadPaced returns the stored timing of p's paced audio, pacing and
+// storing it the first time it is asked for. The audio is stored before the
+// timing that names it. On failure it writes the error response and returns
+// false.
+func (server *Server) loadPaced(ctx *gin.Context, p pacedDictation) (pacedRecord, bool) {
+	key := p.key(server.tts)
+	object, err := server.blobs.Open(ctx, key)
+	if err == nil {
+		defer object.Close()
+		var record pacedRecord
+		if err := json.NewDecoder(object).Decode(&record); err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return pacedRecord{}, false
+		}
+		return record, true
+	}
+	if !errors.Is(err, storage.ErrNotFound) {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pacedRecord{}, false
+	}
+
+	paced, ok := server.pace(ctx, p)
+	if !ok {
+		return pacedRecord{}, false
+	}
+	record := newPacedRecord(paced)
+	sum := sha256.Sum256(paced.Audio.Data)
+	record.AudioKey = storage.VariantKey(key, hex.EncodeToString(sum[:8])+".wav")
+	data := paced.Audio.Data
+	if err := server.blobs.Put(ctx, record.AudioKey, bytes.NewReader(data), int64(len(data)), paced.Audio.Format.ContentType()); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pacedRecord{}, false
+	}
+	body, err := json.Marshal(record)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pacedRecord{}, false
+	}
+	if err := server.blobs.Put(ctx, key, bytes.NewReader(body), int64(len(body)), "application/json"); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return pacedRecord{}, false
+	}
+	return record, true
+}
+
+// getPacedAudio serves a text dictation read at a target WPM, with silence
+// after each phrase for writing it down. The audio is made once per
+// dictation text, voice, speed and WPM and then served from storage with an
+// ETag.
+func (server *Server) getPacedAudio(ctx *gin.Context) {
+	p, ok := server.paceDictation(ctx)
+	if !ok {
+		return
+	}
+	record, ok := server.loadPaced(ctx, p)
+	if !ok {
+		return
+	}
+	object, err := server.blobs.Open(ctx, record.AudioKey)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer object.Close()
+
+	ctx.Header("X-TTS-Voice", p.Request.Voice)
+	serveContent(ctx, object, object.ContentType, object.ETag, object.ModTime)
+}
+
+// getPacedTiming returns where each phrase of the paced audio starts and
+// ends. It is read from the same stored result as the audio, so fetching
+// both costs one synthesis.
+func (server *Server) getPacedTiming(ctx *gin.Context) {
+	p, ok := server.paceDictation(ctx)
+	if !ok {
+		return
+	}
+	record, ok := server.loadPaced(ctx, p)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, pacedTimingResponse{
+		DictationID: p.DictationID,
+		Voice:       p.Request.Voice,
+		Speed:       p.Request.Speed,
+		TargetWPM:   p.TargetWPM,
+		WPM:         record.WPM,
+		DurationMs:  record.DurationMs,
+		Phrases:     record.Phrases,
+	})
+}
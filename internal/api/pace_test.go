@@ -0,0 +1,346 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetPacedTiming(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := db.Dictation{
+		ID:      7,
+		UserID:  sql.NullInt64{Int64: user.ID, Valid: true},
+		Type:    sql.NullString{String: "text", Valid: true},
+		Content: sql.NullString{String: "Dear Sir, we thank you for your letter. We shall reply soon.", Valid: true},
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "OK",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp pacedTimingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, dictation.ID, rsp.DictationID)
+				require.Equal(t, "sine", rsp.Voice)
+				require.Equal(t, float64(60), rsp.TargetWPM)
+				require.InDelta(t, 12000, rsp.DurationMs, 1)
+				require.Len(t, rsp.Phrases, 3)
+				require.Equal(t, phraseTimingResponse{Text: "Dear Sir,", Words: 2, StartMs: 0, EndMs: 750}, rsp.Phrases[0])
+				require.InDelta(t, 2000, rsp.Phrases[1].StartMs, 1)
+			},
+		},
+		{
+			name:  "TooFastForVoice",
+			query: "wpm=200",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnprocessableEntity, recorder.Code)
+			},
+		},
+		{
+			name:  "WPMOutOfRange",
+			query: "wpm=1000",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "MissingWPM",
+			query: "",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "WrittenWordsSetThePace",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(db.Dictation{
+						ID:      dictation.ID,
+						UserID:  dictation.UserID,
+						Type:    sql.NullString{String: "text", Valid: true},
+						Content: sql.NullString{String: "I paid 1,000 dollars on 3 May 2019.", Valid: true},
+					}, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// Eight words are written, though ten are read aloud
+				var rsp pacedTimingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.InDelta(t, 8000, rsp.DurationMs, 1)
+				require.Len(t, rsp.Phrases, 1)
+				require.Equal(t, "I paid 1,000 dollars on 3 May 2019.", rsp.Phrases[0].Text)
+				require.Equal(t, 8, rsp.Phrases[0].Words)
+			},
+		},
+		{
+			name:  "AudioDictation",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(db.Dictation{
+						ID:       dictation.ID,
+						UserID:   dictation.UserID,
+						Type:     sql.NullString{String: "audio", Valid: true},
+						AudioUrl: sql.NullString{String: "https://example.com/a.mp3", Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "NotFound",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(db.Dictation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:  "UnauthorizedUser",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID+1, "other", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			query: "wpm=60",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/dictations/%d/paced-timing?%s", dictation.ID, tc.query)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetPacedAudio(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.Dictation{
+			ID:      7,
+			UserID:  sql.NullInt64{Int64: user.ID, Valid: true},
+			Type:    sql.NullString{String: "text", Valid: true},
+			Content: sql.NullString{String: "one two three four", Valid: true},
+		}, nil)
+	store.EXPECT().
+		GetSettingByUserID(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(db.Setting{}, sql.ErrNoRows)
+
+	server := newTestServerWithStorage(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/dictations/7/paced-audio?wpm=80&voice=low&speed=1", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+	require.Equal(t, "low", recorder.Header().Get("X-TTS-Voice"))
+	// Four words at 80 WPM take three seconds, 48000 samples at 16 kHz
+	require.Len(t, recorder.Body.Bytes(), 44+96000)
+}
+
+func TestPacedAudioStored(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(3).
+		Return(db.Dictation{
+			ID:      7,
+			UserID:  sql.NullInt64{Int64: user.ID, Valid: true},
+			Type:    sql.NullString{String: "text", Valid: true},
+			Content: sql.NullString{String: "one two three four", Valid: true},
+		}, nil)
+	store.EXPECT().
+		GetSettingByUserID(gomock.Any(), gomock.Any()).
+		Times(3).
+		Return(db.Setting{}, sql.ErrNoRows)
+
+	server := newTestServerWithStorage(t, store)
+	get := func(path string, header http.Header) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		for name, values := range header {
+			request.Header[name] = values
+		}
+		addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	// The timing is paced once and stored with the audio it describes
+	recorder := get("/dictations/7/paced-timing?wpm=80", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var timing pacedTimingResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &timing))
+	require.Len(t, timing.Phrases, 1)
+
+	recorder = get("/dictations/7/paced-audio?wpm=80", nil)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+	require.Len(t, recorder.Body.Bytes(), 44+96000)
+	etag := recorder.Header().Get("ETag")
+	require.NotEmpty(t, etag)
+
+	recorder = get("/dictations/7/paced-audio?wpm=80", http.Header{"If-None-Match": {etag}})
+	require.Equal(t, http.StatusNotModified, recorder.Code)
+}
+
+func TestPacedAudioNeedsStorage(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Any()).
+		Times(0)
+
+	server := newTestServer(t, store)
+	for _, path := range []string{"/dictations/7/paced-audio?wpm=80", "/dictations/7/paced-timing?wpm=80"} {
+		recorder := httptest.NewRecorder()
+		request, err := http.NewRequest(http.MethodGet, path, nil)
+		require.NoError(t, err)
+		addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+		require.Equal(t, http.StatusServiceUnavailable, recorder.Code)
+	}
+}
@@ -12,26 +12,38 @@ import (
 )
 
 type performanceResponse struct {
-	ID              int64     `json:"id"`
-	UserID          int64     `json:"user_id"`
-	DictationID     int64     `json:"dictation_id"`
-	TotalAttempts   int32     `json:"total_attempts"`
-	BestAccuracy    float64   `json:"best_accuracy"`
-	AverageAccuracy float64   `json:"average_accuracy"`
-	AverageTime     float64   `json:"average_time"`
-	LastAttemptAt   time.Time `json:"last_attempt_at"`
+	ID                      int64     `json:"id"`
+	UserID                  int64     `json:"user_id"`
+	DictationID             int64     `json:"dictation_id"`
+	TotalAttempts           int32     `json:"total_attempts"`
+	BestAccuracy            float64   `json:"best_accuracy"`
+	AverageAccuracy         float64   `json:"average_accuracy"`
+	AverageTime             float64   `json:"average_time"`
+	LastAttemptAt           time.Time `json:"last_attempt_at"`
+	RollingGrossWPM         float64   `json:"rolling_gross_wpm"`
+	RollingNetWPM           float64   `json:"rolling_net_wpm"`
+	RollingCPM              float64   `json:"rolling_cpm"`
+	RollingCharAccuracy     float64   `json:"rolling_char_accuracy"`
+	BestWeightedAccuracy    float64   `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy float64   `json:"average_weighted_accuracy"`
 }
 
 func newPerformanceResponse(item db.PerformanceSummary) performanceResponse {
 	return performanceResponse{
-		ID:              item.ID,
-		UserID:          item.UserID.Int64,
-		DictationID:     item.DictationID.Int64,
-		TotalAttempts:   item.TotalAttempts.Int32,
-		BestAccuracy:    item.BestAccuracy.Float64,
-		AverageAccuracy: item.AverageAccuracy.Float64,
-		AverageTime:     item.AverageTime.Float64,
-		LastAttemptAt:   item.LastAttemptAt.Time,
+		ID:                      item.ID,
+		UserID:                  item.UserID.Int64,
+		DictationID:             item.DictationID.Int64,
+		TotalAttempts:           item.TotalAttempts.Int32,
+		BestAccuracy:            item.BestAccuracy.Float64,
+		AverageAccuracy:         item.AverageAccuracy.Float64,
+		AverageTime:             item.AverageTime.Float64,
+		LastAttemptAt:           item.LastAttemptAt.Time,
+		RollingGrossWPM:         item.RollingGrossWpm.Float64,
+		RollingNetWPM:           item.RollingNetWpm.Float64,
+		RollingCPM:              item.RollingCpm.Float64,
+		RollingCharAccuracy:     item.RollingCharAccuracy.Float64,
+		BestWeightedAccuracy:    item.BestWeightedAccuracy.Float64,
+		AverageWeightedAccuracy: item.AverageWeightedAccuracy.Float64,
 	}
 }
 
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
+)
+
+type segmentResponse struct {
+	// Index counts from 1 and is what the segment routes and
+	// POST /attempts/start take
+	Index   int    `json:"index"`
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Text    string `json:"text"`
+	// Span locates Text in the dictation's content, in characters
+	Span transcript.Span `json:"span"`
+}
+
+// listDictationSegments returns the timed segments of an audio dictation,
+// for replaying or practising one at a time.
+func (server *Server) listDictationSegments(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	segments, err := transcript.Parse(dictation.TranscriptSegments.RawMessage)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	spans := transcript.Spans(dictation.Content.String, segments)
+	rsp := make([]segmentResponse, len(segments))
+	for i, seg := range segments {
+		rsp[i] = segmentResponse{
+			Index:   i + 1,
+			StartMs: seg.StartMs,
+			EndMs:   seg.EndMs,
+			Text:    seg.Text,
+			Span:    spans[i],
+		}
+	}
+	ctx.JSON(http.StatusOK, rsp)
+}
+
+type segmentAudioRequest struct {
+	Segment int `uri:"segment" binding:"required,min=1"`
+}
+
+// getSegmentAudio serves the audio of one segment of an uploaded dictation,
+// cut from the stored recording.
+func (server *Server) getSegmentAudio(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	var req segmentAudioRequest
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	segment, err := transcript.Lookup(dictation.TranscriptSegments.RawMessage, req.Segment)
+	if err != nil {
+		if errors.Is(err, transcript.ErrNoSegment) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	if server.blobs == nil || !dictation.AudioKey.Valid {
+		ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("dictation has no stored audio")))
+		return
+	}
+
+	object, err := server.blobs.Open(ctx, dictation.AudioKey.String)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer object.Close()
+	data, err := io.ReadAll(object)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	clip, err := audio.Cut(data, time.Duration(segment.StartMs)*time.Millisecond, time.Duration(segment.EndMs)*time.Millisecond)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	// The clip changes with the recording or the segment's times
+	etag := ""
+	if object.ETag != "" {
+		etag = fmt.Sprintf(`%s-%d-%d"`, strings.TrimSuffix(object.ETag, `"`), segment.StartMs, segment.EndMs)
+	}
+	serveContent(ctx, bytes.NewReader(clip), object.ContentType, etag, object.ModTime)
+}
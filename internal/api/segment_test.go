@@ -0,0 +1,175 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func segmentedDictation(userID int64) db.Dictation {
+	return db.Dictation{
+		ID:                 9,
+		UserID:             sql.NullInt64{Int64: userID, Valid: true},
+		Type:               sql.NullString{String: "audio", Valid: true},
+		Content:            sql.NullString{String: "Dear Sir,\nthank you for your letter.", Valid: true},
+		TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":250,"text":"Dear Sir,"},{"start_ms":500,"end_ms":1000,"text":"thank you for your letter."}]`), Valid: true},
+		AudioKey:           sql.NullString{String: "uploads/1/passage.wav", Valid: true},
+		AudioDurationMs:    sql.NullInt64{Int64: 1000, Valid: true},
+	}
+}
+
+func TestListDictationSegments(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := segmentedDictation(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+		Times(1).
+		Return(dictation, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/dictations/%d/segments", dictation.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var rsp []segmentResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Len(t, rsp, 2)
+	require.Equal(t, 2, rsp[1].Index)
+	require.Equal(t, int64(500), rsp[1].StartMs)
+	require.Equal(t, "thank you for your letter.", rsp[1].Text)
+	require.Equal(t, 10, rsp[1].Span.Start)
+	require.Equal(t, 36, rsp[1].Span.End)
+}
+
+func TestGetSegmentAudio(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := segmentedDictation(user.ID)
+
+	// One second of 8 kHz mono audio
+	recording := audio.PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 16000)}
+	for i := range recording.Data {
+		recording.Data[i] = byte(i)
+	}
+
+	testCases := []struct {
+		name          string
+		segment       int
+		setupStore    func(t *testing.T, server *Server)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			segment: 2,
+			setupStore: func(t *testing.T, server *Server) {
+				data := audio.EncodeWAV(recording)
+				err := server.blobs.Put(context.Background(), dictation.AudioKey.String, bytes.NewReader(data), int64(len(data)), "audio/wav")
+				require.NoError(t, err)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "bytes", recorder.Header().Get("Accept-Ranges"))
+				require.Regexp(t, `^".+-500-1000"$`, recorder.Header().Get("ETag"))
+
+				clip, err := audio.DecodeWAV(recorder.Body.Bytes())
+				require.NoError(t, err)
+				require.Equal(t, 500*time.Millisecond, clip.Duration())
+				require.Equal(t, recording.Data[8000:], clip.Data)
+			},
+		},
+		{
+			name:    "NoSuchSegment",
+			segment: 3,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "NoStoredAudio",
+			segment: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				linked := dictation
+				linked.AudioKey = sql.NullString{}
+				linked.AudioUrl = sql.NullString{String: "https://example.com/passage.mp3", Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(linked, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidSegment",
+			segment: 0,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServerWithStorage(t, store)
+			if tc.setupStore != nil {
+				tc.setupStore(t, server)
+			}
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/dictations/%d/segments/%d/audio", dictation.ID, tc.segment)
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -1,11 +1,17 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"log"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
 	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
 	"github.com/nilesh0729/PixelScribe/internal/util"
 )
 
@@ -13,6 +19,13 @@ type Server struct {
 	config     util.Config
 	store      db.Store
 	TokenMaker token.Maker
+	sessions   *token.SessionMaker
+	scorer     scoring.Scorer
+	tts        tts.Provider
+	ttsCache   *tts.FileCache
+	// blobs and jobs are nil when no storage is configured.
+	blobs      storage.Store
+	jobs       *jobs.Runner
 	router     *gin.Engine
 }
 
@@ -22,10 +35,78 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
+	sessionMaker, err := token.NewSessionMaker(config.TokenSymmetricKey)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create session maker: %w", err)
+	}
+	if config.AttemptSessionDuration == 0 {
+		config.AttemptSessionDuration = defaultAttemptSessionDuration
+	}
+
+	scorer, ok := scoring.LookupScorer(config.Scorer)
+	if !ok {
+		return nil, fmt.Errorf("unknown scorer %q, available: %v", config.Scorer, scoring.Scorers())
+	}
+
+	ttsProvider, err := tts.NewProvider(config.TTSProvider, tts.Options{
+		APIKey:  config.OpenAIKey,
+		BaseURL: config.TTSBaseURL,
+		Model:   config.TTSModel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ttsCache *tts.FileCache
+	if config.TTSCacheDir != "" {
+		ttsCache, err = tts.NewFileCache(config.TTSCacheDir, config.TTSCacheMaxBytes, config.TTSCacheMaxAge)
+		if err != nil {
+			return nil, err
+		}
+		ttsProvider = tts.NewCachedProvider(ttsProvider, ttsCache)
+	}
+	// Chunk outside the cache so each chunk is cached on its own
+	ttsProvider = tts.NewChunkedProvider(ttsProvider, config.TTSMaxChunkChars, config.TTSConcurrency)
+
 	server := &Server{
 		config:     config,
 		store:      store,
 		TokenMaker: tokenMaker,
+		sessions:   sessionMaker,
+		scorer:     scorer,
+		tts:        ttsProvider,
+		ttsCache:   ttsCache,
+	}
+	if config.UploadMaxBytes <= 0 {
+		server.config.UploadMaxBytes = defaultUploadMaxBytes
+	}
+	if config.TTSMaxChunkChars <= 0 {
+		server.config.TTSMaxChunkChars = tts.DefaultMaxChunkChars
+	}
+	if config.TTSMaxChunks <= 0 {
+		server.config.TTSMaxChunks = defaultTTSMaxChunks
+	}
+	if config.StorageBackend != "" || config.StorageDir != "" {
+		blobs, err := storage.New(storage.Options{
+			Backend: config.StorageBackend,
+			Dir:     config.StorageDir,
+			S3: storage.S3Options{
+				Endpoint:        config.S3Endpoint,
+				Region:          config.S3Region,
+				Bucket:          config.S3Bucket,
+				AccessKeyID:     config.S3AccessKeyID,
+				SecretAccessKey: config.S3SecretAccessKey,
+				PathStyle:       config.S3PathStyle,
+			},
+		})
+		if err != nil {
+			return nil, err
+		}
+		server.blobs = blobs
+		server.jobs = jobs.NewRunner(store, ttsProvider, blobs, jobs.Options{
+			Workers:     config.AudioJobWorkers,
+			MaxAttempts: config.AudioJobMaxAttempts,
+			AudioURL:    dictationAudioURL,
+		})
 	}
 	router := gin.Default()
 	router.Use(corsMiddleware())
@@ -38,10 +119,23 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	authRoutes.GET("/users/:username", server.getUser)
 
 	authRoutes.POST("/tts/generate", server.generateTTS)
+	authRoutes.GET("/tts/voices", server.listTTSVoices)
+	authRoutes.POST("/convert", server.convertText)
 	authRoutes.POST("/dictations", server.createDictation)
+	authRoutes.POST("/dictations/upload", server.uploadDictation)
 	authRoutes.GET("/dictations", server.listDictations)
 	authRoutes.DELETE("/dictations/:id", server.deleteDictation)
-
+	authRoutes.GET("/dictations/:id/audio", server.getDictationAudio)
+	authRoutes.POST("/dictations/:id/audio/retry", server.retryDictationAudio)
+	authRoutes.GET("/dictations/:id/waveform", server.getDictationWaveform)
+	authRoutes.PUT("/dictations/:id/transcript", server.setDictationTranscript)
+	authRoutes.PUT("/dictations/:id/lexicon", server.setDictationLexicon)
+	authRoutes.GET("/dictations/:id/segments", server.listDictationSegments)
+	authRoutes.GET("/dictations/:id/segments/:segment/audio", server.getSegmentAudio)
+	authRoutes.GET("/dictations/:id/paced-audio", server.getPacedAudio)
+	authRoutes.GET("/dictations/:id/paced-timing", server.getPacedTiming)
+
+	authRoutes.POST("/attempts/start", server.startAttempt)
 	authRoutes.POST("/attempts", server.submitAttempt)
 	authRoutes.GET("/attempts", server.listAttempts)
     authRoutes.GET("/attempts/:id", server.getAttempt)
@@ -52,11 +146,22 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	authRoutes.GET("/performance", server.listPerformance)
 	authRoutes.GET("/performance/recent", server.getOverallPerformance)
 
+	adminRoutes := router.Group("/").Use(authMiddleware(server.TokenMaker), adminMiddleware(config.AdminUsernames))
+
+	adminRoutes.GET("/tts/cache", server.getTTSCacheStats)
+
 	server.router = router
 	return server, nil
 }
 
 func (server *Server) Start(address string) error {
+	if server.jobs != nil {
+		go func() {
+			if err := server.jobs.Run(context.Background()); err != nil {
+				log.Print("background jobs stopped: ", err)
+			}
+		}()
+	}
 	return server.router.Run(address)
 }
 
@@ -71,7 +176,8 @@ func corsMiddleware() gin.HandlerFunc {
 			c.Writer.Header().Set("Access-Control-Allow-Origin", origin)
 		}
 		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, If-None-Match")
+		c.Writer.Header().Set("Access-Control-Expose-Headers", "ETag, X-TTS-Voice")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
 
 		if c.Request.Method == "OPTIONS" {
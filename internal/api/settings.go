@@ -2,11 +2,16 @@ package api
 
 import (
 	"database/sql"
+	"encoding/json"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+	"github.com/sqlc-dev/pqtype"
 )
 
 type updateSettingsRequest struct {
@@ -16,6 +21,12 @@ type updateSettingsRequest struct {
 	HighlightColorGrammar  string  `json:"highlight_color_grammar"`
 	HighlightColorSpelling string  `json:"highlight_color_spelling"`
 	HighlightColorCase     string  `json:"highlight_color_case"`
+	// Tolerance is the user's own tolerance profile, used for dictations that
+	// do not set one.
+	Tolerance *scoring.Tolerance `json:"tolerance"`
+	// Lexicon says how words are read aloud in all the user's dictations.
+	// Omitted keeps the current one; empty removes it.
+	Lexicon []lexicon.Entry `json:"lexicon"`
 }
 
 type settingResponse struct {
@@ -26,6 +37,8 @@ type settingResponse struct {
 	HighlightColorGrammar  string  `json:"highlight_color_grammar"`
 	HighlightColorSpelling string  `json:"highlight_color_spelling"`
 	HighlightColorCase     string  `json:"highlight_color_case"`
+	Tolerance              json.RawMessage `json:"tolerance,omitempty"`
+	Lexicon                json.RawMessage `json:"lexicon,omitempty"`
 }
 
 func newSettingResponse(s db.Setting) settingResponse {
@@ -37,6 +50,8 @@ func newSettingResponse(s db.Setting) settingResponse {
 		HighlightColorGrammar:  s.HighlightColorGrammar.String,
 		HighlightColorSpelling: s.HighlightColorSpelling.String,
 		HighlightColorCase:     s.HighlightColorCase.String,
+		Tolerance:              s.Tolerance.RawMessage,
+		Lexicon:                s.Lexicon.RawMessage,
 	}
 }
 
@@ -74,6 +89,31 @@ func (server *Server) updateSettings(ctx *gin.Context) {
 		return
 	}
 
+	if err := tts.Validate(server.tts, tts.Request{Voice: req.DefaultVoice, Speed: req.DefaultSpeed}); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	var tolerance pqtype.NullRawMessage
+	if req.Tolerance != nil {
+		if err := req.Tolerance.Validate(); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err))
+			return
+		}
+		data, err := json.Marshal(req.Tolerance)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		tolerance = pqtype.NullRawMessage{RawMessage: data, Valid: true}
+	}
+
+	userLexicon, err := lexiconParam(req.Lexicon)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
 	// First get existing settings to find ID being updated (or we could assume 1:1 user:settings mapping logic)
 	// Query GetSettingByUserID is easiest.
 	existing, err := server.store.GetSettingByUserID(ctx, sql.NullInt64{Int64: req.UserID, Valid: true})
@@ -93,6 +133,8 @@ func (server *Server) updateSettings(ctx *gin.Context) {
 		HighlightColorGrammar:  sql.NullString{String: req.HighlightColorGrammar, Valid: req.HighlightColorGrammar != ""},
 		HighlightColorSpelling: sql.NullString{String: req.HighlightColorSpelling, Valid: req.HighlightColorSpelling != ""},
 		HighlightColorCase:     sql.NullString{String: req.HighlightColorCase, Valid: req.HighlightColorCase != ""},
+		Tolerance:              tolerance,
+		Lexicon:                userLexicon,
 	}
 	
 	// If a field is not provided in update request (e.g. empty string), UpdateSetting (as generated) updates it to NULL or value?
@@ -111,6 +153,8 @@ func (server *Server) updateSettings(ctx *gin.Context) {
 	if req.HighlightColorGrammar == "" { arg.HighlightColorGrammar = existing.HighlightColorGrammar }
 	if req.HighlightColorSpelling == "" { arg.HighlightColorSpelling = existing.HighlightColorSpelling }
 	if req.HighlightColorCase == "" { arg.HighlightColorCase = existing.HighlightColorCase }
+	if req.Tolerance == nil { arg.Tolerance = existing.Tolerance }
+	if req.Lexicon == nil { arg.Lexicon = existing.Lexicon }
 
 	updated, err := server.store.UpdateSetting(ctx, arg)
 	if err != nil {
@@ -120,3 +164,24 @@ func (server *Server) updateSettings(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, newSettingResponse(updated))
 }
+
+// userScoring returns the tolerance profile and pronunciation lexicon in a
+// user's settings; both are nil if the user has none.
+func (server *Server) userScoring(ctx *gin.Context, userID int64) (*scoring.Tolerance, []lexicon.Entry, error) {
+	setting, err := server.store.GetSettingByUserID(ctx, sql.NullInt64{Int64: userID, Valid: true})
+	if err == sql.ErrNoRows {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	tolerance, err := scoring.ParseTolerance(setting.Tolerance.RawMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := lexicon.Parse(setting.Lexicon.RawMessage)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tolerance, entries, nil
+}
@@ -14,6 +14,7 @@ import (
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
 	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
 	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 )
@@ -104,7 +105,7 @@ func TestUpdateSettings(t *testing.T) {
 	updatedSetting := db.Setting{
 		ID:           1,
 		UserID:       sql.NullInt64{Int64: 1, Valid: true},
-		DefaultVoice: sql.NullString{String: "new-voice", Valid: true},
+		DefaultVoice: sql.NullString{String: "low", Valid: true},
 	}
 
 	
@@ -122,7 +123,7 @@ func TestUpdateSettings(t *testing.T) {
 			name: "OK",
 			body: gin.H{
 				"user_id":       1,
-				"default_voice": "new-voice",
+				"default_voice": "low",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
@@ -137,7 +138,7 @@ func TestUpdateSettings(t *testing.T) {
 				// 2. Update (expects merge of old+new)
 				arg := db.UpdateSettingParams{
 					ID:                     1,
-					DefaultVoice:           sql.NullString{String: "new-voice", Valid: true},
+					DefaultVoice:           sql.NullString{String: "low", Valid: true},
 					DefaultSpeed:           setting.DefaultSpeed,
 					HighlightColorGrammar:  setting.HighlightColorGrammar,
 					HighlightColorSpelling: setting.HighlightColorSpelling,
@@ -152,6 +153,159 @@ func TestUpdateSettings(t *testing.T) {
 				require.Equal(t, http.StatusOK, recorder.Code)
 			},
 		},
+		{
+			name: "UpdateTolerance",
+			body: gin.H{
+				"user_id":   1,
+				"tolerance": gin.H{"profile": "standard", "alternatives": [][]string{{"okay", "OK"}}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: 1, Valid: true})).
+					Times(1).
+					Return(setting, nil)
+
+				tolerance := pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"profile":"standard","alternatives":[["okay","OK"]]}`), Valid: true}
+				arg := db.UpdateSettingParams{
+					ID:                     1,
+					DefaultVoice:           setting.DefaultVoice,
+					DefaultSpeed:           setting.DefaultSpeed,
+					HighlightColorGrammar:  setting.HighlightColorGrammar,
+					HighlightColorSpelling: setting.HighlightColorSpelling,
+					HighlightColorCase:     setting.HighlightColorCase,
+					Tolerance:              tolerance,
+				}
+				withTolerance := updatedSetting
+				withTolerance.Tolerance = tolerance
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(withTolerance, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got settingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.JSONEq(t, `{"profile":"standard","alternatives":[["okay","OK"]]}`, string(got.Tolerance))
+			},
+		},
+		{
+			name: "UpdateLexicon",
+			body: gin.H{
+				"user_id": 1,
+				"lexicon": []gin.H{{"written": "Nguyen", "spoken": "Win"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: 1, Valid: true})).
+					Times(1).
+					Return(setting, nil)
+
+				lexicon := pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"written":"Nguyen","spoken":"Win"}]`), Valid: true}
+				arg := db.UpdateSettingParams{
+					ID:                     1,
+					DefaultVoice:           setting.DefaultVoice,
+					DefaultSpeed:           setting.DefaultSpeed,
+					HighlightColorGrammar:  setting.HighlightColorGrammar,
+					HighlightColorSpelling: setting.HighlightColorSpelling,
+					HighlightColorCase:     setting.HighlightColorCase,
+					Tolerance:              setting.Tolerance,
+					Lexicon:                lexicon,
+				}
+				withLexicon := updatedSetting
+				withLexicon.Lexicon = lexicon
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(withLexicon, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var got settingResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+				require.JSONEq(t, `[{"written":"Nguyen","spoken":"Win"}]`, string(got.Lexicon))
+			},
+		},
+		{
+			name: "InvalidLexicon",
+			body: gin.H{
+				"user_id": 1,
+				"lexicon": []gin.H{{"written": "Nguyen"}},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownVoice",
+			body: gin.H{
+				"user_id":       1,
+				"default_voice": "en-US-Neural2-F",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SpeedOutOfRange",
+			body: gin.H{
+				"user_id":       1,
+				"default_speed": 10,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownToleranceProfile",
+			body: gin.H{
+				"user_id":   1,
+				"tolerance": gin.H{"profile": "anything"},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateSetting(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// errNoTranscript is returned when an attempt is made on an audio dictation
+// that has nothing to score against yet.
+var errNoTranscript = errors.New("dictation has no reference transcript; add one with PUT /dictations/:id/transcript")
+
+// scorable reports whether attempts on a dictation can be scored. Audio
+// dictations are only scorable once they have a transcript.
+func scorable(d db.Dictation) bool {
+	return strings.TrimSpace(d.Content.String) != ""
+}
+
+// transcriptParams validates an audio dictation's transcript and segments
+// against a recording of durationMs milliseconds (0 if unknown) and encodes
+// them for storage. With neither, both are NULL and the dictation is not
+// scorable.
+func transcriptParams(text string, segments []transcript.Segment, durationMs int64) (sql.NullString, pqtype.NullRawMessage, error) {
+	if strings.TrimSpace(text) == "" && len(segments) == 0 {
+		return sql.NullString{}, pqtype.NullRawMessage{}, nil
+	}
+	text, err := transcript.Resolve(text, segments, durationMs)
+	if err != nil {
+		return sql.NullString{}, pqtype.NullRawMessage{}, err
+	}
+	content := sql.NullString{String: text, Valid: true}
+	if len(segments) == 0 {
+		return content, pqtype.NullRawMessage{}, nil
+	}
+	data, err := json.Marshal(segments)
+	if err != nil {
+		return sql.NullString{}, pqtype.NullRawMessage{}, err
+	}
+	return content, pqtype.NullRawMessage{RawMessage: data, Valid: true}, nil
+}
+
+type setTranscriptRequest struct {
+	// Transcript may be omitted when segments are given; it is then their
+	// texts joined
+	Transcript string               `json:"transcript"`
+	Segments   []transcript.Segment `json:"segments"`
+}
+
+// setDictationTranscript adds or replaces the reference transcript of an
+// audio dictation, making it scorable.
+func (server *Server) setDictationTranscript(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	if dictation.Type.String != "audio" {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "only audio dictations have a transcript; edit a text dictation's content instead"})
+		return
+	}
+
+	var req setTranscriptRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+	if strings.TrimSpace(req.Transcript) == "" && len(req.Segments) == 0 {
+		ctx.JSON(http.StatusBadRequest, gin.H{"error": "transcript or segments are required"})
+		return
+	}
+	content, segments, err := transcriptParams(req.Transcript, req.Segments, dictation.AudioDurationMs.Int64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
+	}
+
+	dictation, err = server.store.SetDictationTranscript(ctx, db.SetDictationTranscriptParams{
+		ID:                 dictation.ID,
+		UserID:             dictation.UserID,
+		Content:            content,
+		TranscriptSegments: segments,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, newDictationResponse(dictation))
+}
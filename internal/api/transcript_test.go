@@ -0,0 +1,233 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetDictationTranscript(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	userID := sql.NullInt64{Int64: user.ID, Valid: true}
+	dictation := db.Dictation{
+		ID:              5,
+		UserID:          userID,
+		Type:            sql.NullString{String: "audio", Valid: true},
+		AudioUrl:        sql.NullString{String: "/dictations/5/audio", Valid: true},
+		AudioDurationMs: sql.NullInt64{Int64: 4000, Valid: true},
+	}
+	segments := []gin.H{
+		{"start_ms": 0, "end_ms": 1500, "text": "Dear Sir,"},
+		{"start_ms": 1800, "end_ms": 3900, "text": "thank you for your letter."},
+	}
+
+	testCases := []struct {
+		name          string
+		dictationID   int64
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:        "OK",
+			dictationID: dictation.ID,
+			body:        gin.H{"segments": segments},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetDictationTranscriptParams) (db.Dictation, error) {
+						require.Equal(t, dictation.ID, arg.ID)
+						require.Equal(t, userID, arg.UserID)
+						require.Equal(t, "Dear Sir, thank you for your letter.", arg.Content.String)
+						require.JSONEq(t, `[{"start_ms":0,"end_ms":1500,"text":"Dear Sir,"},{"start_ms":1800,"end_ms":3900,"text":"thank you for your letter."}]`, string(arg.TranscriptSegments.RawMessage))
+
+						updated := dictation
+						updated.Content = arg.Content
+						updated.TranscriptSegments = arg.TranscriptSegments
+						return updated, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rsp dictationResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.True(t, rsp.Scorable)
+				require.Equal(t, "Dear Sir, thank you for your letter.", rsp.Content)
+				require.NotEmpty(t, rsp.TranscriptSegments)
+			},
+		},
+		{
+			name:        "TranscriptOnly",
+			dictationID: dictation.ID,
+			body:        gin.H{"transcript": "Dear Sir,\nthank you for your letter."},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.SetDictationTranscriptParams) (db.Dictation, error) {
+						require.Equal(t, "Dear Sir,\nthank you for your letter.", arg.Content.String)
+						require.False(t, arg.TranscriptSegments.Valid)
+						return dictation, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:        "SegmentPastEndOfAudio",
+			dictationID: dictation.ID,
+			body: gin.H{"segments": []gin.H{
+				{"start_ms": 0, "end_ms": 4500, "text": "Dear Sir,"},
+			}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "Empty",
+			dictationID: dictation.ID,
+			body:        gin.H{"transcript": "  "},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "TextDictation",
+			dictationID: dictation.ID,
+			body:        gin.H{"transcript": "Dear Sir,"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				text := dictation
+				text.Type = sql.NullString{String: "text", Valid: true}
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(text, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "NotFound",
+			dictationID: dictation.ID,
+			body:        gin.H{"transcript": "Dear Sir,"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(db.Dictation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:        "UnauthorizedUser",
+			dictationID: dictation.ID,
+			body:        gin.H{"transcript": "Dear Sir,"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID+1, "other", time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+					Times(1).
+					Return(dictation, nil)
+				store.EXPECT().
+					SetDictationTranscript(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/dictations/%d/transcript", tc.dictationID)
+			request, err := http.NewRequest(http.MethodPut, url, bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -2,15 +2,38 @@ package api
 
 import (
 	"bytes"
-	"encoding/json"
-	"io"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+	"github.com/sqlc-dev/pqtype"
 )
 
+// defaultTTSMaxChunks caps /tts/generate text at 10 chunks when
+// TTS_MAX_CHUNKS is unset, 40,000 characters with the default chunk size.
+const defaultTTSMaxChunks = 10
+
 type generateTTSRequest struct {
 	Text string `json:"text" binding:"required"`
+	// Voice and Speed override the user's default_voice and default_speed.
+	Voice string  `json:"voice"`
+	Speed float64 `json:"speed"`
+	// DictationID, when the text is from a dictation, reads it with the
+	// dictation's language and lexicon.
+	DictationID int64 `json:"dictation_id"`
+	// Language picks how numbers and abbreviations are read, overriding
+	// the dictation's
+	Language string `json:"language"`
 }
 
 func (server *Server) generateTTS(ctx *gin.Context) {
@@ -19,57 +42,141 @@ func (server *Server) generateTTS(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err))
 		return
 	}
+	// Every chunk is a request to the provider, so the text is capped at a
+	// number of them
+	if maxChars := server.config.TTSMaxChunkChars * server.config.TTSMaxChunks; utf8.RuneCountInString(req.Text) > maxChars {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(fmt.Errorf("text is longer than %d characters", maxChars)))
+		return
+	}
 
-	// Prepare OpenAI Request
-	openAIUrl := "https://api.openai.com/v1/audio/speech"
-	payload := map[string]interface{}{
-		"model":           "tts-1",
-		"input":           req.Text,
-		"voice":           "alloy",
-		"response_format": "mp3",
+	ttsReq := tts.Request{Text: req.Text, Voice: req.Voice, Speed: req.Speed}
+	if err := tts.Validate(server.tts, ttsReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err))
+		return
 	}
 
-	jsonData, err := json.Marshal(payload)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	var dictation db.Dictation
+	if req.DictationID != 0 {
+		var err error
+		dictation, err = server.store.GetDictation(ctx, req.DictationID)
+		if err != nil {
+			if err == sql.ErrNoRows {
+				ctx.JSON(http.StatusNotFound, errorResponse(fmt.Errorf("dictation not found")))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+			return
+		}
+		if dictation.UserID.Int64 != authPayload.UserID {
+			ctx.JSON(http.StatusUnauthorized, errorResponse(fmt.Errorf("dictation doesn't belong to the authenticated user")))
+			return
+		}
+	}
+	language := req.Language
+	if language == "" {
+		language = dictation.Language.String
+	}
+	ttsReq, err := server.withUserSettings(ctx, authPayload.UserID, ttsReq, language, dictation.Lexicon)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	request, err := http.NewRequest("POST", openAIUrl, bytes.NewBuffer(jsonData))
+	// The key addresses the audio's content, so a client holding it already
+	// has the right audio
+	etag := `"` + tts.Key(server.tts, ttsReq) + `"`
+	ctx.Header("ETag", etag)
+	if etagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	audio, err := server.tts.Synthesize(ctx, ttsReq)
 	if err != nil {
+		var apiErr *tts.APIError
+		if errors.As(err, &apiErr) {
+			ctx.JSON(http.StatusBadGateway, gin.H{
+				"error":       "TTS provider failed",
+				"details":     apiErr.Body,
+				"status_code": apiErr.StatusCode,
+			})
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
 		return
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	request.Header.Set("Authorization", "Bearer "+server.config.OpenAIKey)
+	ctx.Header("X-TTS-Voice", ttsReq.Voice)
+	serveContent(ctx, bytes.NewReader(audio.Data), audio.Format.ContentType(), etag, time.Time{})
+}
 
-	client := &http.Client{}
-	response, err := client.Do(request)
+// withUserSettings prepares req to be read to a user. The voice and speed
+// req leaves empty are filled from the user's settings, falling back to the
+// provider's default voice at normal speed; stored values the provider does
+// not support are skipped, so a change of provider doesn't break playback.
+// The text is verbalized in language with the dictation's lexicon, if any,
+// then the user's.
+func (server *Server) withUserSettings(ctx context.Context, userID int64, req tts.Request, language string, dictationLexicon pqtype.NullRawMessage) (tts.Request, error) {
+	req, verbalize, err := server.userReading(ctx, userID, req, language, dictationLexicon)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
-		return
+		return req, err
 	}
-	defer response.Body.Close()
-
-	if response.StatusCode != http.StatusOK {
-		bodyBytes, _ := io.ReadAll(response.Body)
-		ctx.JSON(http.StatusBadGateway, gin.H{
-			"error":       "OpenAI API failed",
-			"details":     string(bodyBytes),
-			"status_code": response.StatusCode,
-		})
-		return
+	req.Text = verbalize(req.Text)
+	return req, nil
+}
+
+// userReading is withUserSettings without verbalizing req.Text: it returns
+// the function that does, for callers that read the text in parts.
+func (server *Server) userReading(ctx context.Context, userID int64, req tts.Request, language string, dictationLexicon pqtype.NullRawMessage) (tts.Request, func(string) string, error) {
+	setting, err := server.store.GetSettingByUserID(ctx, sql.NullInt64{Int64: userID, Valid: true})
+	if err != nil && err != sql.ErrNoRows {
+		return req, nil, err
+	}
+	lexicons, err := parseLexicons(dictationLexicon, setting.Lexicon)
+	if err != nil {
+		return req, nil, err
 	}
+	verbalize := func(text string) string {
+		spoken, _ := lexicon.Verbalize(text, language, lexicons...)
+		return spoken
+	}
+	return tts.WithDefaults(server.tts, req, setting.DefaultVoice.String, setting.DefaultSpeed.Float64), verbalize, nil
+}
 
-	// Stream response back to client
-	ctx.Header("Content-Type", "audio/mpeg")
-	ctx.Header("Transfer-Encoding", "chunked")
+type ttsVoicesResponse struct {
+	Provider string      `json:"provider"`
+	Model    string      `json:"model"`
+	MinSpeed float64     `json:"min_speed"`
+	MaxSpeed float64     `json:"max_speed"`
+	Voices   []tts.Voice `json:"voices"`
+}
 
-	// Copy the stream
-	_, err = io.Copy(ctx.Writer, response.Body)
-	if err != nil {
-		// Cannot write JSON error here as headers likely sent
+func (server *Server) listTTSVoices(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ttsVoicesResponse{
+		Provider: server.tts.Name(),
+		Model:    server.tts.Model(),
+		MinSpeed: tts.MinSpeed,
+		MaxSpeed: tts.MaxSpeed,
+		Voices:   server.tts.Voices(),
+	})
+}
+
+// etagMatches reports whether an If-None-Match header lists etag.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) getTTSCacheStats(ctx *gin.Context) {
+	if server.ttsCache == nil {
+		ctx.JSON(http.StatusNotFound, gin.H{"error": "TTS cache is disabled"})
 		return
 	}
+	ctx.JSON(http.StatusOK, server.ttsCache.Stats())
 }
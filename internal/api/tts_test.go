@@ -0,0 +1,404 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/token"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+	"github.com/nilesh0729/PixelScribe/internal/util"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGenerateTTS(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	userID := sql.NullInt64{Int64: user.ID, Valid: true}
+	etag := `"` + tts.Key(tts.NewFakeProvider(), tts.Request{Text: "the quick brown fox", Voice: "sine", Speed: 1}) + `"`
+
+	testCases := []struct {
+		name          string
+		body          gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "audio/wav", recorder.Header().Get("Content-Type"))
+				require.Equal(t, "sine", recorder.Header().Get("X-TTS-Voice"))
+				require.Equal(t, etag, recorder.Header().Get("ETag"))
+				require.Equal(t, "bytes", recorder.Header().Get("Accept-Ranges"))
+				require.Equal(t, strconv.Itoa(recorder.Body.Len()), recorder.Header().Get("Content-Length"))
+				require.Equal(t, "RIFF", recorder.Body.String()[:4])
+			},
+		},
+		{
+			name: "Range",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				request.Header.Set("Range", "bytes=0-3")
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPartialContent, recorder.Code)
+				require.True(t, strings.HasPrefix(recorder.Header().Get("Content-Range"), "bytes 0-3/"))
+				require.Equal(t, "RIFF", recorder.Body.String())
+			},
+		},
+		{
+			name: "NotModified",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+				request.Header.Set("If-None-Match", `"stale", `+etag)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotModified, recorder.Code)
+				require.Zero(t, recorder.Body.Len())
+			},
+		},
+		{
+			name: "UserDefaultVoice",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{
+						DefaultVoice: sql.NullString{String: "low", Valid: true},
+						DefaultSpeed: sql.NullFloat64{Float64: 2, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "low", recorder.Header().Get("X-TTS-Voice"))
+				// Four words take 1.5 seconds at 16 kHz, halved at double speed
+				require.Len(t, recorder.Body.Bytes(), 44+24000)
+			},
+		},
+		{
+			name: "UnsupportedDefaultVoiceFallsBack",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{
+						DefaultVoice: sql.NullString{String: "en-US-Neural2-F", Valid: true},
+						DefaultSpeed: sql.NullFloat64{Float64: 1, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "sine", recorder.Header().Get("X-TTS-Voice"))
+			},
+		},
+		{
+			name: "RequestOverridesSettings",
+			body: gin.H{"text": "the quick brown fox", "voice": "high", "speed": 1},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{
+						DefaultVoice: sql.NullString{String: "low", Valid: true},
+						DefaultSpeed: sql.NullFloat64{Float64: 1.5, Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "high", recorder.Header().Get("X-TTS-Voice"))
+			},
+		},
+		{
+			name: "Verbalized",
+			body: gin.H{"text": "Dr. Nguyen paid $5", "dictation_id": 3},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(3))).
+					Times(1).
+					Return(db.Dictation{
+						ID:       3,
+						UserID:   userID,
+						Language: sql.NullString{String: "en-GB", Valid: true},
+						Lexicon:  pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"written":"Nguyen","spoken":"Win"}]`), Valid: true},
+					}, nil)
+				// The dictation's lexicon wins over the user's
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+					Times(1).
+					Return(db.Setting{
+						Lexicon: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"written":"Nguyen","spoken":"Nwin"},{"written":"paid","spoken":"sent"}]`), Valid: true},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				key := tts.Key(tts.NewFakeProvider(), tts.Request{Text: "Doctor Win sent five dollars", Voice: "sine", Speed: 1})
+				require.Equal(t, `"`+key+`"`, recorder.Header().Get("ETag"))
+			},
+		},
+		{
+			name: "OtherUsersDictation",
+			body: gin.H{"text": "Dr. Nguyen", "dictation_id": 3},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetDictation(gomock.Any(), gomock.Eq(int64(3))).
+					Times(1).
+					Return(db.Dictation{ID: 3, UserID: sql.NullInt64{Int64: user.ID + 1, Valid: true}}, nil)
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownVoice",
+			body: gin.H{"text": "the quick brown fox", "voice": "alloy"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "SpeedOutOfRange",
+			body: gin.H{"text": "the quick brown fox", "speed": 0.1},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			// Ten chunks of 4000 characters by default
+			name: "TextTooLong",
+			body: gin.H{"text": strings.Repeat("a", 40001)},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSettingByUserID(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusRequestEntityTooLarge, recorder.Code)
+			},
+		},
+		{
+			name: "MissingText",
+			body: gin.H{},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "NoAuthorization",
+			body: gin.H{"text": "the quick brown fox"},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tts/generate", bytes.NewReader(data))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestListTTSVoices(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	server := newTestServer(t, mockdb.NewMockStore(ctrl))
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/tts/voices", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var rsp ttsVoicesResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+	require.Equal(t, "fake", rsp.Provider)
+	require.Len(t, rsp.Voices, 3)
+	require.Equal(t, "sine", rsp.Voices[0].ID)
+	require.True(t, rsp.Voices[0].Default)
+}
+
+func TestGetTTSCacheStats(t *testing.T) {
+	admin, _ := randomUserForLogin(t)
+	user, _ := randomUserForLogin(t)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		TTSProvider:       "fake",
+		TTSCacheDir:       t.TempDir(),
+		AdminUsernames:    []string{admin.Username},
+	}
+	server, err := NewServer(config, mockdb.NewMockStore(ctrl))
+	require.NoError(t, err)
+	_, err = server.tts.Synthesize(context.Background(), tts.Request{Text: "hello"})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		name          string
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Admin",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", admin.ID, admin.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var stats tts.CacheStats
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &stats))
+				require.Equal(t, int64(1), stats.Misses)
+				require.Equal(t, 1, stats.Entries)
+			},
+		},
+		{
+			name: "NotAdmin",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:      "NoAuthorization",
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, "/tts/cache", nil)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.TokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+
+	// Without a cache there are no stats, even for admins
+	config.TTSCacheDir = ""
+	server, err = NewServer(config, mockdb.NewMockStore(ctrl))
+	require.NoError(t, err)
+	recorder := httptest.NewRecorder()
+	request, err := http.NewRequest(http.MethodGet, "/tts/cache", nil)
+	require.NoError(t, err)
+	addAuthorization(t, request, server.TokenMaker, "bearer", admin.ID, admin.Username, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusNotFound, recorder.Code)
+}
@@ -0,0 +1,45 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nilesh0729/PixelScribe/internal/jobs"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+)
+
+// getDictationWaveform serves the waveform of a dictation's stored audio in
+// the audiowaveform JSON format. Waveforms are computed in the background
+// when audio is generated or uploaded; one that is missing is queued and
+// reported as pending.
+func (server *Server) getDictationWaveform(ctx *gin.Context) {
+	dictation, ok := server.ownDictation(ctx)
+	if !ok {
+		return
+	}
+	if server.blobs == nil || !dictation.AudioKey.Valid {
+		ctx.JSON(http.StatusNotFound, gin.H{
+			"error":        "dictation has no stored audio",
+			"audio_status": dictation.AudioStatus.String,
+		})
+		return
+	}
+
+	object, err := server.blobs.Open(ctx, storage.VariantKey(dictation.AudioKey.String, jobs.WaveformVariant))
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			server.jobs.EnqueueWaveform(dictation.ID)
+			ctx.JSON(http.StatusNotFound, gin.H{
+				"error":           "waveform is not ready yet",
+				"waveform_status": jobs.AudioPending,
+			})
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err))
+		return
+	}
+	defer object.Close()
+
+	serveContent(ctx, object, object.ContentType, object.ETag, object.ModTime)
+}
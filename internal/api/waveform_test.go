@@ -0,0 +1,103 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetDictationWaveform(t *testing.T) {
+	user, _ := randomUserForLogin(t)
+	dictation := db.Dictation{
+		ID:       7,
+		UserID:   sql.NullInt64{Int64: user.ID, Valid: true},
+		Type:     sql.NullString{String: "audio", Valid: true},
+		AudioKey: sql.NullString{String: "uploads/1/passage.wav", Valid: true},
+	}
+	waveform := audio.PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 1600)}.Waveform(80)
+
+	testCases := []struct {
+		name          string
+		dictation     db.Dictation
+		setupStore    func(t *testing.T, server *Server)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "OK",
+			dictation: dictation,
+			setupStore: func(t *testing.T, server *Server) {
+				data, err := json.Marshal(waveform)
+				require.NoError(t, err)
+				err = server.blobs.Put(context.Background(), "uploads/1/passage.waveform.json", bytes.NewReader(data), int64(len(data)), "application/json")
+				require.NoError(t, err)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+				require.NotEmpty(t, recorder.Header().Get("ETag"))
+
+				var rsp audio.Waveform
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rsp))
+				require.Equal(t, waveform, rsp)
+			},
+		},
+		{
+			name:      "Pending",
+			dictation: dictation,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), `"waveform_status":"pending"`)
+			},
+		},
+		{
+			name: "NoStoredAudio",
+			dictation: db.Dictation{
+				ID:       dictation.ID,
+				UserID:   dictation.UserID,
+				Type:     dictation.Type,
+				AudioUrl: sql.NullString{String: "https://example.com/passage.mp3", Valid: true},
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "no stored audio")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetDictation(gomock.Any(), gomock.Eq(dictation.ID)).
+				Times(1).
+				Return(tc.dictation, nil)
+
+			server := newTestServerWithStorage(t, store)
+			if tc.setupStore != nil {
+				tc.setupStore(t, server)
+			}
+
+			recorder := httptest.NewRecorder()
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/dictations/%d/waveform", dictation.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, request, server.TokenMaker, "bearer", user.ID, user.Username, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func randomPCM(t *testing.T, frames int) PCM {
+	p := PCM{SampleRate: 8000, Channels: 2, BitsPerSample: 16}
+	for i := range frames * 2 {
+		p.Data = binary.LittleEndian.AppendUint16(p.Data, uint16(i*37))
+	}
+	return p
+}
+
+func TestWAVRoundTrip(t *testing.T) {
+	p := randomPCM(t, 4000)
+	data := EncodeWAV(p)
+	require.Len(t, data, 44+len(p.Data))
+
+	got, err := DecodeWAV(data)
+	require.NoError(t, err)
+	require.Equal(t, p, got)
+	require.Equal(t, 4000, got.Frames())
+	require.Equal(t, 500*time.Millisecond, got.Duration())
+
+	d, err := WAVDuration(data)
+	require.NoError(t, err)
+	require.Equal(t, 500*time.Millisecond, d)
+}
+
+func TestDecodeWAVSkipsOtherChunks(t *testing.T) {
+	p := randomPCM(t, 10)
+	data := EncodeWAV(p)
+
+	// Insert an odd-sized LIST chunk, padded to even length, before data
+	var b bytes.Buffer
+	b.Write(data[:36])
+	b.WriteString("LIST")
+	binary.Write(&b, binary.LittleEndian, uint32(3))
+	b.Write([]byte{1, 2, 3, 0})
+	b.Write(data[36:])
+
+	got, err := DecodeWAV(b.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, p.Data, got.Data)
+}
+
+func TestDecodeWAVRejects(t *testing.T) {
+	_, err := DecodeWAV([]byte("not audio at all"))
+	require.ErrorIs(t, err, ErrNotWAV)
+
+	// IEEE float
+	data := EncodeWAV(randomPCM(t, 10))
+	binary.LittleEndian.PutUint16(data[20:22], 3)
+	_, err = DecodeWAV(data)
+	require.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestConcatWAV(t *testing.T) {
+	a, b := randomPCM(t, 100), randomPCM(t, 50)
+	silence := Silence(a, 10*time.Millisecond)
+	require.Equal(t, 80, silence.Frames())
+
+	joined, err := ConcatWAV(EncodeWAV(a), EncodeWAV(silence), EncodeWAV(b))
+	require.NoError(t, err)
+	got, err := DecodeWAV(joined)
+	require.NoError(t, err)
+	require.Equal(t, 230, got.Frames())
+	require.Equal(t, a.Data, got.Data[:len(a.Data)])
+	require.Equal(t, b.Data, got.Data[len(got.Data)-len(b.Data):])
+
+	mono := PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 20)}
+	_, err = ConcatWAV(EncodeWAV(a), EncodeWAV(mono))
+	require.Error(t, err)
+}
+
+// mp3Stream builds frames of MPEG-1 Layer III at 128 kbit/s and 44.1 kHz,
+// 417 bytes each, with the frame number in the first payload byte.
+func mp3Stream(frames int, xing bool) []byte {
+	var b bytes.Buffer
+	// ID3v2 tag with a 20-byte body
+	b.WriteString("ID3\x04\x00\x00\x00\x00\x00\x14")
+	b.Write(make([]byte, 20))
+	for i := range frames + 1 {
+		if i == 0 && !xing {
+			continue
+		}
+		frame := make([]byte, 417)
+		copy(frame, []byte{0xFF, 0xFB, 0x90, 0x00})
+		frame[4] = byte(i)
+		if i == 0 {
+			copy(frame[36:], "Xing")
+		}
+		b.Write(frame)
+	}
+	return b.Bytes()
+}
+
+func TestMP3Duration(t *testing.T) {
+	d, err := MP3Duration(mp3Stream(100, true))
+	require.NoError(t, err)
+	require.InDelta(t, float64(100*1152)/44100, d.Seconds(), 0.0001)
+
+	_, err = MP3Duration([]byte("definitely not an mp3 file"))
+	require.ErrorIs(t, err, ErrNotMP3)
+}
+
+func TestConcatMP3(t *testing.T) {
+	joined, err := ConcatMP3(mp3Stream(3, true), mp3Stream(2, false))
+	require.NoError(t, err)
+
+	// Tags and the Xing frame are dropped, leaving five audio frames
+	require.Len(t, joined, 5*417)
+	for i, want := range []byte{1, 2, 3, 1, 2} {
+		frame := joined[i*417:]
+		require.Equal(t, []byte{0xFF, 0xFB, 0x90, 0x00}, frame[:4])
+		require.Equal(t, want, frame[4])
+	}
+}
+
+func TestProbe(t *testing.T) {
+	info, err := Probe(EncodeWAV(randomPCM(t, 4000)))
+	require.NoError(t, err)
+	require.Equal(t, Info{Format: "wav", ContentType: "audio/wav", Duration: 500 * time.Millisecond}, info)
+
+	info, err = Probe(mp3Stream(10, true))
+	require.NoError(t, err)
+	require.Equal(t, "mp3", info.Format)
+	require.Equal(t, "audio/mpeg", info.ContentType)
+
+	// Untagged MP3
+	info, err = Probe(mp3Stream(10, false)[30:])
+	require.NoError(t, err)
+	require.Equal(t, "mp3", info.Format)
+
+	for _, data := range [][]byte{
+		[]byte("%PDF-1.7 pretending to be audio"),
+		[]byte("RIFF\x00\x00\x00\x00WAVEjunk"),
+		{0xFF, 0xFB, 0x90, 0x00, 1, 2, 3},
+		nil,
+	} {
+		_, err := Probe(data)
+		require.ErrorIs(t, err, ErrUnsupported)
+	}
+}
+
+func TestSliceWAV(t *testing.T) {
+	p := randomPCM(t, 8000) // one second at 8 kHz
+
+	data, err := SliceWAV(EncodeWAV(p), 250*time.Millisecond, 500*time.Millisecond)
+	require.NoError(t, err)
+	got, err := DecodeWAV(data)
+	require.NoError(t, err)
+	require.Equal(t, 2000, got.Frames())
+	require.Equal(t, p.Data[2000*4:4000*4], got.Data)
+
+	// Bounds past the end are clamped
+	got = p.Slice(900*time.Millisecond, 2*time.Second)
+	require.Equal(t, 800, got.Frames())
+	require.Zero(t, p.Slice(2*time.Second, 3*time.Second).Frames())
+}
+
+func TestSliceMP3(t *testing.T) {
+	// Each frame of the test stream plays for 1152/44100 s, about 26.1 ms
+	frame := time.Duration(1152) * time.Second / 44100
+	data := mp3Stream(10, true)
+
+	cut, err := SliceMP3(data, 2*frame+frame/2, 5*frame)
+	require.NoError(t, err)
+	require.Len(t, cut, 3*417)
+	require.Equal(t, byte(3), cut[4]) // the third audio frame comes first
+
+	d, err := MP3Duration(cut)
+	require.NoError(t, err)
+	require.Equal(t, 3*frame, d)
+
+	_, err = SliceMP3([]byte("not an mp3"), 0, time.Second)
+	require.ErrorIs(t, err, ErrNotMP3)
+}
+
+func TestCut(t *testing.T) {
+	wav := EncodeWAV(randomPCM(t, 8000))
+	data, err := Cut(wav, 0, 100*time.Millisecond)
+	require.NoError(t, err)
+	d, err := WAVDuration(data)
+	require.NoError(t, err)
+	require.Equal(t, 100*time.Millisecond, d)
+
+	data, err = Cut(mp3Stream(10, false), 0, time.Second)
+	require.NoError(t, err)
+	require.Len(t, data, 10*417)
+
+	_, err = Cut([]byte("plain text"), 0, time.Second)
+	require.ErrorIs(t, err, ErrUnsupported)
+}
+
+// sine returns seconds of a 16 kHz mono 16-bit tone at freq Hz.
+func sine(freq, seconds float64) PCM {
+	p := PCM{SampleRate: 16000, Channels: 1, BitsPerSample: 16}
+	for i := range int(seconds * 16000) {
+		v := 0.5 * math.Sin(2*math.Pi*freq*float64(i)/16000)
+		p.Data = binary.LittleEndian.AppendUint16(p.Data, uint16(int16(v*32767)))
+	}
+	return p
+}
+
+// frequency estimates the pitch of a tone from its zero crossings, ignoring
+// the first and last 100 ms.
+func frequency(p PCM) float64 {
+	s := p.samples()[0]
+	edge := p.SampleRate / 10
+	s = s[edge : len(s)-edge]
+	crossings := 0
+	for i := 1; i < len(s); i++ {
+		if (s[i-1] < 0) != (s[i] < 0) {
+			crossings++
+		}
+	}
+	return float64(crossings) / 2 / (float64(len(s)) / float64(p.SampleRate))
+}
+
+func TestStretch(t *testing.T) {
+	tone := sine(440, 2)
+
+	for _, speed := range []float64{0.8, 1.2, 0.5, 2} {
+		stretched, err := Stretch(tone, speed)
+		require.NoError(t, err)
+		require.InDelta(t, 2/speed, stretched.Duration().Seconds(), 0.001, "speed %g", speed)
+		require.InDelta(t, 440, frequency(stretched), 5, "speed %g", speed)
+
+		// The tone keeps its level rather than beating against itself
+		var sum float64
+		samples := stretched.samples()[0]
+		for _, v := range samples {
+			sum += v * v
+		}
+		require.InDelta(t, 0.5/math.Sqrt2, math.Sqrt(sum/float64(len(samples))), 0.03, "speed %g", speed)
+	}
+
+	same, err := Stretch(tone, 1)
+	require.NoError(t, err)
+	require.Equal(t, tone, same)
+
+	_, err = Stretch(tone, 3)
+	require.Error(t, err)
+}
+
+func TestStretchWAV(t *testing.T) {
+	data, err := StretchWAV(EncodeWAV(randomPCM(t, 8000)), 1.25)
+	require.NoError(t, err)
+	d, err := WAVDuration(data)
+	require.NoError(t, err)
+	require.Equal(t, 800*time.Millisecond, d)
+
+	_, err = StretchWAV(mp3Stream(10, false), 1.25)
+	require.ErrorIs(t, err, ErrNotWAV)
+}
+
+func TestSamplesRoundTrip(t *testing.T) {
+	for _, bits := range []int{8, 16, 24, 32} {
+		p := PCM{SampleRate: 8000, Channels: 2, BitsPerSample: bits, Data: make([]byte, 64*bits/8*2)}
+		for i := range p.Data {
+			p.Data[i] = byte(i * 31)
+		}
+		require.Equal(t, p, p.fromSamples(p.samples()), "%d-bit", bits)
+	}
+}
+
+func TestSpeeds(t *testing.T) {
+	speeds := Speeds()
+	require.Len(t, speeds, 30)
+	require.InDelta(t, 0.5, speeds[0], 1e-9)
+	require.InDelta(t, 2.0, speeds[len(speeds)-1], 1e-9)
+	require.NotContains(t, speeds, 1.0)
+
+	require.InDelta(t, 0.8, RoundSpeed(0.81), 1e-9)
+	require.InDelta(t, 1.25, RoundSpeed(1.24), 1e-9)
+}
+
+func TestPCMWaveform(t *testing.T) {
+	// Half a second of tone, then half a second of silence
+	tone := sine(440, 1)
+	silent := len(tone.Data) / 2
+	clear(tone.Data[silent:])
+
+	w, err := ComputeWaveform(EncodeWAV(tone))
+	require.NoError(t, err)
+	require.Equal(t, 2, w.Version)
+	require.Equal(t, 1, w.Channels)
+	require.Equal(t, 16000, w.SampleRate)
+	require.Equal(t, 160, w.SamplesPerPixel)
+	require.Equal(t, 8, w.Bits)
+	require.Equal(t, 100, w.Length)
+	require.Len(t, w.Data, 200)
+	require.Len(t, w.RMS, 100)
+
+	for i := range 50 {
+		require.InDelta(t, -64, w.Data[2*i], 1, "pixel %d", i)
+		require.InDelta(t, 64, w.Data[2*i+1], 1, "pixel %d", i)
+		require.InDelta(t, 45, w.RMS[i], 1, "pixel %d", i)
+	}
+	for i := 50; i < 100; i++ {
+		require.Zero(t, w.Data[2*i+1], "pixel %d", i)
+		require.Zero(t, w.RMS[i], "pixel %d", i)
+	}
+
+	// Channels are mixed
+	stereo := PCM{SampleRate: 8000, Channels: 2, BitsPerSample: 16}
+	for range 8 {
+		stereo.Data = binary.LittleEndian.AppendUint16(stereo.Data, uint16(int16(16384)))
+		stereo.Data = binary.LittleEndian.AppendUint16(stereo.Data, 0)
+	}
+	w = stereo.Waveform(4)
+	require.Equal(t, []int8{32, 32, 32, 32}, w.Data)
+}
+
+func TestMP3Waveform(t *testing.T) {
+	// Half a second of a 440 Hz tone at half scale, then half a second of
+	// silence, encoded as 24 kHz mono MPEG-2 layer III
+	data, err := os.ReadFile("testdata/tone.mp3")
+	require.NoError(t, err)
+
+	w, err := ComputeWaveform(data)
+	require.NoError(t, err)
+	require.Equal(t, 1, w.Channels)
+	require.Equal(t, 24000, w.SampleRate)
+	require.Equal(t, 240, w.SamplesPerPixel)
+	require.Equal(t, 101, w.Length)
+	require.Len(t, w.Data, 2*w.Length)
+	require.Len(t, w.RMS, w.Length)
+
+	// The encoder and decoder delay the audio by a few pixels
+	for i := 10; i < 50; i++ {
+		require.InDelta(t, -64, w.Data[2*i], 12, "pixel %d", i)
+		require.InDelta(t, 64, w.Data[2*i+1], 12, "pixel %d", i)
+		// A sine wave's RMS is its peak over √2
+		require.InDelta(t, float64(w.Data[2*i+1])/math.Sqrt2, w.RMS[i], 2, "pixel %d", i)
+	}
+	for i := 60; i < w.Length; i++ {
+		require.InDelta(t, 0, w.Data[2*i+1], 1, "pixel %d", i)
+		require.InDelta(t, 0, w.RMS[i], 1, "pixel %d", i)
+	}
+
+	_, err = ComputeWaveform([]byte("not audio"))
+	require.ErrorIs(t, err, ErrUnsupported)
+}
@@ -0,0 +1,173 @@
+package audio
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrNotMP3 is returned for input without any MPEG audio frames.
+var ErrNotMP3 = errors.New("not an MP3 file")
+
+// Bitrates in kbit/s by [version is MPEG-1][layer-1][index]. Index 0 is
+// "free format" and 15 is invalid; neither is supported.
+var mp3Bitrates = [2][3][16]int{
+	{ // MPEG-2 and 2.5
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 144, 160, 176, 192, 224, 256, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+		{0, 8, 16, 24, 32, 40, 48, 56, 64, 80, 96, 112, 128, 144, 160, 0},
+	},
+	{ // MPEG-1
+		{0, 32, 64, 96, 128, 160, 192, 224, 256, 288, 320, 352, 384, 416, 448, 0},
+		{0, 32, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 384, 0},
+		{0, 32, 40, 48, 56, 64, 80, 96, 112, 128, 160, 192, 224, 256, 320, 0},
+	},
+}
+
+// Sample rates by version bits (0 is MPEG-2.5, 2 MPEG-2, 3 MPEG-1).
+var mp3SampleRates = [4][3]int{
+	{11025, 12000, 8000},
+	{},
+	{22050, 24000, 16000},
+	{44100, 48000, 32000},
+}
+
+// mp3Frame describes one MPEG audio frame.
+type mp3Frame struct {
+	size       int
+	samples    int
+	sampleRate int
+	layer      int
+	mpeg1      bool
+	mono       bool
+	crc        bool
+}
+
+// parseMP3Frame decodes the 4-byte frame header at the start of b.
+func parseMP3Frame(b []byte) (mp3Frame, bool) {
+	if len(b) < 4 || b[0] != 0xFF || b[1]&0xE0 != 0xE0 {
+		return mp3Frame{}, false
+	}
+	version := int(b[1]>>3) & 3
+	layer := 4 - int(b[1]>>1)&3 // 1, 2 or 3; 4 is reserved
+	bitrateIndex := int(b[2] >> 4)
+	rateIndex := int(b[2]>>2) & 3
+	padding := int(b[2]>>1) & 1
+	if version == 1 || layer == 4 || rateIndex == 3 {
+		return mp3Frame{}, false
+	}
+
+	f := mp3Frame{
+		sampleRate: mp3SampleRates[version][rateIndex],
+		layer:      layer,
+		mpeg1:      version == 3,
+		mono:       b[3]>>6 == 3,
+		crc:        b[1]&1 == 0,
+	}
+	v := 0
+	if f.mpeg1 {
+		v = 1
+	}
+	bitrate := mp3Bitrates[v][layer-1][bitrateIndex] * 1000
+	if bitrate == 0 {
+		return mp3Frame{}, false
+	}
+
+	switch {
+	case layer == 1:
+		f.samples = 384
+		f.size = (12*bitrate/f.sampleRate + padding) * 4
+	case layer == 3 && !f.mpeg1:
+		f.samples = 576
+		f.size = 72*bitrate/f.sampleRate + padding
+	default:
+		f.samples = 1152
+		f.size = 144*bitrate/f.sampleRate + padding
+	}
+	return f, true
+}
+
+// isVBRHeader reports whether frame is a Xing, Info or VBRI header frame,
+// which carries stream metadata instead of audio.
+func isVBRHeader(frame []byte, f mp3Frame) bool {
+	sideInfo := 17
+	switch {
+	case f.mpeg1 && !f.mono:
+		sideInfo = 32
+	case !f.mpeg1 && f.mono:
+		sideInfo = 9
+	}
+	if tag := frame[min(4+sideInfo, len(frame)):]; len(tag) >= 4 {
+		if s := string(tag[:4]); s == "Xing" || s == "Info" {
+			return true
+		}
+	}
+	return len(frame) >= 40 && string(frame[36:40]) == "VBRI"
+}
+
+// mp3Frames returns the audio frames of an MP3 file, skipping ID3 tags,
+// VBR header frames and anything else between frames.
+func mp3Frames(data []byte) (frames [][]byte, info []mp3Frame) {
+	pos := 0
+	if len(data) >= 10 && string(data[:3]) == "ID3" {
+		// The tag size is a 28-bit "syncsafe" integer
+		size := int(data[6])<<21 | int(data[7])<<14 | int(data[8])<<7 | int(data[9])
+		pos = 10 + size
+		if data[5]&0x10 != 0 {
+			pos += 10 // footer
+		}
+	}
+
+	for pos+4 <= len(data) {
+		f, ok := parseMP3Frame(data[pos:])
+		if !ok || pos+f.size > len(data) {
+			pos++
+			continue
+		}
+		// A real frame is followed by another frame or the end of the
+		// audio; this rejects sync patterns inside tag data
+		if next := pos + f.size; next+4 <= len(data) {
+			if _, ok := parseMP3Frame(data[next:]); !ok && len(frames) == 0 {
+				pos++
+				continue
+			}
+		}
+		frame := data[pos : pos+f.size]
+		if len(frames) > 0 || !isVBRHeader(frame, f) {
+			frames = append(frames, frame)
+			info = append(info, f)
+		}
+		pos += f.size
+	}
+	return frames, info
+}
+
+// MP3Duration returns the playing time of an MP3 file by adding up its
+// frames.
+func MP3Duration(data []byte) (time.Duration, error) {
+	_, info := mp3Frames(data)
+	if len(info) == 0 {
+		return 0, ErrNotMP3
+	}
+	var d time.Duration
+	for _, f := range info {
+		d += time.Duration(f.samples) * time.Second / time.Duration(f.sampleRate)
+	}
+	return d, nil
+}
+
+// ConcatMP3 joins MP3 files into one stream of their audio frames. Tags
+// and VBR header frames are dropped, since their lengths and frame counts
+// would describe only one of the parts.
+func ConcatMP3(files ...[]byte) ([]byte, error) {
+	var out []byte
+	for _, file := range files {
+		frames, _ := mp3Frames(file)
+		if len(frames) == 0 {
+			return nil, ErrNotMP3
+		}
+		for _, f := range frames {
+			out = append(out, f...)
+		}
+	}
+	return out, nil
+}
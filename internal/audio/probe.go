@@ -0,0 +1,49 @@
+package audio
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrUnsupported is returned by Probe for anything but WAV and MP3.
+var ErrUnsupported = errors.New("unsupported audio format, expected WAV or MP3")
+
+// Info describes an audio file.
+type Info struct {
+	// Format is "wav" or "mp3".
+	Format      string
+	ContentType string
+	Duration    time.Duration
+}
+
+// Probe identifies a WAV or MP3 file from its content, whatever it claims
+// to be, and measures how long it plays.
+func Probe(data []byte) (Info, error) {
+	switch {
+	case IsWAV(data):
+		d, err := WAVDuration(data)
+		if err != nil {
+			return Info{}, fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		return Info{Format: "wav", ContentType: "audio/wav", Duration: d}, nil
+	case len(data) >= 3 && string(data[:3]) == "ID3", isMP3Start(data):
+		d, err := MP3Duration(data)
+		if err != nil {
+			return Info{}, fmt.Errorf("%w: %v", ErrUnsupported, err)
+		}
+		return Info{Format: "mp3", ContentType: "audio/mpeg", Duration: d}, nil
+	}
+	return Info{}, ErrUnsupported
+}
+
+// isMP3Start reports whether data begins with two consecutive MPEG audio
+// frames, as an untagged MP3 does.
+func isMP3Start(data []byte) bool {
+	f, ok := parseMP3Frame(data)
+	if !ok || f.size >= len(data) {
+		return ok && f.size == len(data)
+	}
+	_, ok = parseMP3Frame(data[f.size:])
+	return ok
+}
@@ -0,0 +1,64 @@
+package audio
+
+import (
+	"time"
+)
+
+// Slice returns the part of p from start to end, clamped to its length.
+func (p PCM) Slice(start, end time.Duration) PCM {
+	from := p.frameAt(start)
+	to := p.frameAt(end)
+	if to < from {
+		to = from
+	}
+	p.Data = p.Data[from*p.frameSize() : to*p.frameSize()]
+	return p
+}
+
+// frameAt returns the index of the frame playing at d, clamped to p.
+func (p PCM) frameAt(d time.Duration) int {
+	frame := int(d * time.Duration(p.SampleRate) / time.Second)
+	return min(max(frame, 0), p.Frames())
+}
+
+// SliceWAV cuts the audio from start to end out of a WAV file.
+func SliceWAV(data []byte, start, end time.Duration) ([]byte, error) {
+	p, err := DecodeWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeWAV(p.Slice(start, end)), nil
+}
+
+// SliceMP3 cuts the frames playing between start and end out of an MP3
+// file. The cut is rounded out to whole frames, about 26 ms each, and tags
+// and VBR headers are dropped.
+func SliceMP3(data []byte, start, end time.Duration) ([]byte, error) {
+	frames, info := mp3Frames(data)
+	if len(frames) == 0 {
+		return nil, ErrNotMP3
+	}
+	var out []byte
+	var pos time.Duration
+	for i, frame := range frames {
+		next := pos + time.Duration(info[i].samples)*time.Second/time.Duration(info[i].sampleRate)
+		if next > start && pos < end {
+			out = append(out, frame...)
+		}
+		pos = next
+	}
+	return out, nil
+}
+
+// Cut returns the audio from start to end of a WAV or MP3 file, in the same
+// format.
+func Cut(data []byte, start, end time.Duration) ([]byte, error) {
+	info, err := Probe(data)
+	if err != nil {
+		return nil, err
+	}
+	if info.Format == "wav" {
+		return SliceWAV(data, start, end)
+	}
+	return SliceMP3(data, start, end)
+}
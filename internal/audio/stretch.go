@@ -0,0 +1,214 @@
+package audio
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Playback speeds Stretch accepts. Speeds are used in steps of SpeedStep, so
+// each recording has a bounded set of variants to store.
+const (
+	MinSpeed  = 0.5
+	MaxSpeed  = 2.0
+	SpeedStep = 0.05
+)
+
+// RoundSpeed rounds speed to the nearest SpeedStep.
+func RoundSpeed(speed float64) float64 {
+	return math.Round(speed/SpeedStep) * SpeedStep
+}
+
+// Speeds lists every speed from MinSpeed to MaxSpeed except 1.
+func Speeds() []float64 {
+	var speeds []float64
+	for i := int(math.Round(MinSpeed / SpeedStep)); i <= int(math.Round(MaxSpeed/SpeedStep)); i++ {
+		if speed := float64(i) * SpeedStep; math.Abs(speed-1) > SpeedStep/2 {
+			speeds = append(speeds, speed)
+		}
+	}
+	return speeds
+}
+
+// Stretch changes how fast p plays without changing its pitch, using
+// waveform-similarity overlap-add (WSOLA): overlapping windows of the input
+// are laid out at the new rate, each shifted slightly to where it best
+// continues the previous one so the waveform stays in phase. Speeds above 1
+// play faster.
+func Stretch(p PCM, speed float64) (PCM, error) {
+	if speed < MinSpeed || speed > MaxSpeed {
+		return PCM{}, fmt.Errorf("speed must be between %g and %g", MinSpeed, MaxSpeed)
+	}
+	if err := p.validate(); err != nil {
+		return PCM{}, err
+	}
+	if speed == 1 || p.Frames() == 0 {
+		return p, nil
+	}
+
+	in := p.samples()
+	frames := p.Frames()
+	outFrames := int(math.Round(float64(frames) / speed))
+
+	// 40 ms windows overlapping by half, each free to move by up to 10 ms,
+	// which spans a pitch period of even a low voice
+	size := max(p.SampleRate/25, 4) &^ 1
+	hop := size / 2
+	tolerance := size / 4
+	window := hann(size)
+
+	// Windows are placed by the similarity of the channels' mix
+	mix := make([]float64, frames)
+	for _, ch := range in {
+		for i, v := range ch {
+			mix[i] += v
+		}
+	}
+
+	out := make([][]float64, len(in))
+	for c := range out {
+		out[c] = make([]float64, outFrames+size)
+	}
+	weight := make([]float64, outFrames+size)
+	prev := 0
+	for k := 0; k*hop < outFrames; k++ {
+		pos := int(math.Round(float64(k*hop) * speed))
+		if k > 0 {
+			pos = bestOverlap(mix, prev+hop, pos, tolerance, hop, max(p.SampleRate/8000, 1))
+		}
+		start := k * hop
+		for i, w := range window {
+			weight[start+i] += w
+			if j := pos + i; j >= 0 && j < frames {
+				for c := range in {
+					out[c][start+i] += w * in[c][j]
+				}
+			}
+		}
+		prev = pos
+	}
+	for c := range out {
+		for i := range out[c] {
+			if weight[i] > 1e-3 {
+				out[c][i] /= weight[i]
+			}
+		}
+		out[c] = out[c][:outFrames]
+	}
+	return p.fromSamples(out), nil
+}
+
+// StretchWAV changes the speed of a WAV file, as Stretch does.
+func StretchWAV(data []byte, speed float64) ([]byte, error) {
+	p, err := DecodeWAV(data)
+	if err != nil {
+		return nil, err
+	}
+	stretched, err := Stretch(p, speed)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeWAV(stretched), nil
+}
+
+// bestOverlap returns the start near nominal, within tolerance, whose first
+// length samples of x best match those at natural, where the previous
+// window would have continued. A coarse search every step samples is
+// refined around its best match.
+func bestOverlap(x []float64, natural, nominal, tolerance, length, step int) int {
+	if natural+length > len(x) {
+		return nominal
+	}
+	template := x[natural : natural+length]
+	similarity := func(pos int) float64 {
+		var sum float64
+		for i := 0; i < length; i += step {
+			if j := pos + i; j >= 0 && j < len(x) {
+				sum += x[j] * template[i]
+			}
+		}
+		return sum
+	}
+
+	best, bestScore := nominal, math.Inf(-1)
+	for pos := nominal - tolerance; pos <= nominal+tolerance; pos += step {
+		if score := similarity(pos); score > bestScore {
+			best, bestScore = pos, score
+		}
+	}
+	coarse := best
+	for pos := coarse - step + 1; pos < coarse+step; pos++ {
+		if pos == coarse || pos < nominal-tolerance || pos > nominal+tolerance {
+			continue
+		}
+		if score := similarity(pos); score > bestScore {
+			best, bestScore = pos, score
+		}
+	}
+	return best
+}
+
+// hann returns a periodic Hann window, whose copies at half-window spacing
+// add up to exactly 1.
+func hann(n int) []float64 {
+	w := make([]float64, n)
+	for i := range w {
+		w[i] = 0.5 - 0.5*math.Cos(2*math.Pi*float64(i)/float64(n))
+	}
+	return w
+}
+
+// samples returns p's channels as samples between -1 and 1.
+func (p PCM) samples() [][]float64 {
+	channels := make([][]float64, p.Channels)
+	frames := p.Frames()
+	for c := range channels {
+		channels[c] = make([]float64, frames)
+	}
+	width := p.BitsPerSample / 8
+	for i := range frames {
+		for c := range channels {
+			b := p.Data[(i*p.Channels+c)*width:]
+			var v float64
+			switch p.BitsPerSample {
+			case 8:
+				v = (float64(b[0]) - 128) / 128
+			case 16:
+				v = float64(int16(binary.LittleEndian.Uint16(b))) / (1 << 15)
+			case 24:
+				v = float64(int32(uint32(b[0])<<8|uint32(b[1])<<16|uint32(b[2])<<24)>>8) / (1 << 23)
+			case 32:
+				v = float64(int32(binary.LittleEndian.Uint32(b))) / (1 << 31)
+			}
+			channels[c][i] = v
+		}
+	}
+	return channels
+}
+
+// fromSamples encodes channels of samples between -1 and 1 in p's format.
+func (p PCM) fromSamples(channels [][]float64) PCM {
+	frames := len(channels[0])
+	width := p.BitsPerSample / 8
+	p.Data = make([]byte, frames*p.Channels*width)
+	scale := float64(int64(1) << (p.BitsPerSample - 1))
+	for i := range frames {
+		for c, ch := range channels {
+			v := math.Round(ch[i] * scale)
+			v = math.Max(-scale, math.Min(scale-1, v))
+			b := p.Data[(i*p.Channels+c)*width:]
+			switch p.BitsPerSample {
+			case 8:
+				b[0] = byte(int(v) + 128)
+			case 16:
+				binary.LittleEndian.PutUint16(b, uint16(int16(v)))
+			case 24:
+				u := uint32(int32(v))
+				b[0], b[1], b[2] = byte(u), byte(u>>8), byte(u>>16)
+			case 32:
+				binary.LittleEndian.PutUint32(b, uint32(int32(v)))
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,198 @@
+// Package audio decodes, edits and encodes the audio files PixelScribe
+// serves, in pure Go.
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// PCM is uncompressed little-endian integer audio with interleaved
+// channels, as stored in a WAV file.
+type PCM struct {
+	SampleRate    int
+	Channels      int
+	BitsPerSample int
+	Data          []byte
+}
+
+// ErrNotWAV is returned by DecodeWAV for input that is not a PCM WAV file.
+var ErrNotWAV = errors.New("not a PCM WAV file")
+
+// IsWAV reports whether data starts with a RIFF WAVE header, for which its
+// first 12 bytes are enough.
+func IsWAV(data []byte) bool {
+	return len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WAVE"
+}
+
+// DecodeWAV parses a RIFF WAVE file holding integer PCM.
+func DecodeWAV(data []byte) (PCM, error) {
+	if !IsWAV(data) {
+		return PCM{}, ErrNotWAV
+	}
+
+	var p PCM
+	haveFormat := false
+	for pos := 12; pos+8 <= len(data); {
+		id := string(data[pos : pos+4])
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		body := data[pos+8:]
+		// Streamed files may leave the data size unset or too large
+		if size > len(body) {
+			size = len(body)
+		}
+		body = body[:size]
+
+		switch id {
+		case "fmt ":
+			if size < 16 {
+				return PCM{}, fmt.Errorf("%w: short fmt chunk", ErrNotWAV)
+			}
+			format := binary.LittleEndian.Uint16(body[0:2])
+			// 0xFFFE is WAVE_FORMAT_EXTENSIBLE, used for PCM with more
+			// than two channels or 16 bits
+			if format != 1 && format != 0xFFFE {
+				return PCM{}, fmt.Errorf("%w: unsupported encoding %d", ErrNotWAV, format)
+			}
+			p.Channels = int(binary.LittleEndian.Uint16(body[2:4]))
+			p.SampleRate = int(binary.LittleEndian.Uint32(body[4:8]))
+			p.BitsPerSample = int(binary.LittleEndian.Uint16(body[14:16]))
+			haveFormat = true
+		case "data":
+			if !haveFormat {
+				return PCM{}, fmt.Errorf("%w: data before fmt chunk", ErrNotWAV)
+			}
+			p.Data = body
+			if err := p.validate(); err != nil {
+				return PCM{}, err
+			}
+			// Drop a trailing partial frame
+			p.Data = p.Data[:len(p.Data)-len(p.Data)%p.frameSize()]
+			return p, nil
+		}
+		// Chunks are padded to an even size
+		pos += 8 + size + size%2
+	}
+	return PCM{}, fmt.Errorf("%w: no data chunk", ErrNotWAV)
+}
+
+func (p PCM) validate() error {
+	switch {
+	case p.Channels < 1:
+		return fmt.Errorf("%w: no channels", ErrNotWAV)
+	case p.SampleRate < 1:
+		return fmt.Errorf("%w: invalid sample rate", ErrNotWAV)
+	case p.BitsPerSample != 8 && p.BitsPerSample != 16 && p.BitsPerSample != 24 && p.BitsPerSample != 32:
+		return fmt.Errorf("%w: unsupported sample size %d", ErrNotWAV, p.BitsPerSample)
+	}
+	return nil
+}
+
+// EncodeWAV writes p as a canonical 44-byte-header WAV file.
+func EncodeWAV(p PCM) []byte {
+	var b bytes.Buffer
+	b.Grow(44 + len(p.Data))
+	b.WriteString("RIFF")
+	binary.Write(&b, binary.LittleEndian, uint32(36+len(p.Data)))
+	b.WriteString("WAVEfmt ")
+	binary.Write(&b, binary.LittleEndian, uint32(16))
+	binary.Write(&b, binary.LittleEndian, uint16(1)) // PCM
+	binary.Write(&b, binary.LittleEndian, uint16(p.Channels))
+	binary.Write(&b, binary.LittleEndian, uint32(p.SampleRate))
+	binary.Write(&b, binary.LittleEndian, uint32(p.SampleRate*p.frameSize()))
+	binary.Write(&b, binary.LittleEndian, uint16(p.frameSize()))
+	binary.Write(&b, binary.LittleEndian, uint16(p.BitsPerSample))
+	b.WriteString("data")
+	binary.Write(&b, binary.LittleEndian, uint32(len(p.Data)))
+	b.Write(p.Data)
+	return b.Bytes()
+}
+
+// frameSize is the number of bytes per sample across all channels.
+func (p PCM) frameSize() int {
+	return p.Channels * p.BitsPerSample / 8
+}
+
+// Frames returns the number of samples per channel.
+func (p PCM) Frames() int {
+	if p.frameSize() == 0 {
+		return 0
+	}
+	return len(p.Data) / p.frameSize()
+}
+
+// Duration returns how long p plays for.
+func (p PCM) Duration() time.Duration {
+	if p.SampleRate == 0 {
+		return 0
+	}
+	return time.Duration(p.Frames()) * time.Second / time.Duration(p.SampleRate)
+}
+
+// WAVDuration returns the playing time of a WAV file.
+func WAVDuration(data []byte) (time.Duration, error) {
+	p, err := DecodeWAV(data)
+	if err != nil {
+		return 0, err
+	}
+	return p.Duration(), nil
+}
+
+// sameFormat reports whether p and q can be joined without conversion.
+func (p PCM) sameFormat(q PCM) bool {
+	return p.SampleRate == q.SampleRate && p.Channels == q.Channels && p.BitsPerSample == q.BitsPerSample
+}
+
+// Silence returns d of silence in format f; f.Data is ignored.
+func Silence(f PCM, d time.Duration) PCM {
+	frames := int(d * time.Duration(f.SampleRate) / time.Second)
+	f.Data = make([]byte, frames*f.frameSize())
+	if f.BitsPerSample == 8 {
+		// 8-bit WAV is unsigned, centred on 128
+		for i := range f.Data {
+			f.Data[i] = 0x80
+		}
+	}
+	return f
+}
+
+// ConcatPCM joins clips of the same format end to end.
+func ConcatPCM(clips ...PCM) (PCM, error) {
+	if len(clips) == 0 {
+		return PCM{}, errors.New("nothing to concatenate")
+	}
+	out := clips[0]
+	size := 0
+	for _, c := range clips {
+		if !c.sameFormat(out) {
+			return PCM{}, fmt.Errorf("cannot join %d Hz %d-channel %d-bit audio to %d Hz %d-channel %d-bit audio",
+				c.SampleRate, c.Channels, c.BitsPerSample, out.SampleRate, out.Channels, out.BitsPerSample)
+		}
+		size += len(c.Data)
+	}
+	out.Data = make([]byte, 0, size)
+	for _, c := range clips {
+		out.Data = append(out.Data, c.Data...)
+	}
+	return out, nil
+}
+
+// ConcatWAV joins WAV files of the same format into one.
+func ConcatWAV(files ...[]byte) ([]byte, error) {
+	clips := make([]PCM, len(files))
+	for i, f := range files {
+		clip, err := DecodeWAV(f)
+		if err != nil {
+			return nil, err
+		}
+		clips[i] = clip
+	}
+	joined, err := ConcatPCM(clips...)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeWAV(joined), nil
+}
@@ -0,0 +1,167 @@
+package audio
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/hajimehoshi/go-mp3"
+)
+
+// WaveformPixelsPerSecond is the resolution of computed waveforms.
+const WaveformPixelsPerSecond = 100
+
+// Waveform is a downsampled outline of audio for drawing and scrubbing, in
+// the audiowaveform JSON format (version 2, channels mixed into one) that
+// viewers such as peaks.js read. Data holds the minimum and maximum sample
+// of each pixel as 8-bit values. RMS, an addition to the format, holds each
+// pixel's root mean square level on the same scale.
+type Waveform struct {
+	Version         int    `json:"version"`
+	Channels        int    `json:"channels"`
+	SampleRate      int    `json:"sample_rate"`
+	SamplesPerPixel int    `json:"samples_per_pixel"`
+	Bits            int    `json:"bits"`
+	Length          int    `json:"length"`
+	Data            []int8 `json:"data"`
+	RMS             []int8 `json:"rms"`
+}
+
+// outliner builds a Waveform from a stream of samples, already mixed to one
+// channel and scaled to between -1 and 1.
+type outliner struct {
+	w           Waveform
+	n           int
+	lo, hi, sum float64
+}
+
+func newOutliner(sampleRate, samplesPerPixel, frames int) *outliner {
+	length := (frames + samplesPerPixel - 1) / samplesPerPixel
+	return &outliner{
+		w: Waveform{
+			Version:         2,
+			Channels:        1,
+			SampleRate:      sampleRate,
+			SamplesPerPixel: samplesPerPixel,
+			Bits:            8,
+			Data:            make([]int8, 0, 2*length),
+			RMS:             make([]int8, 0, length),
+		},
+		lo: 1,
+		hi: -1,
+	}
+}
+
+// add takes the next sample.
+func (o *outliner) add(v float64) {
+	o.lo, o.hi = math.Min(o.lo, v), math.Max(o.hi, v)
+	o.sum += v * v
+	if o.n++; o.n == o.w.SamplesPerPixel {
+		o.flush()
+	}
+}
+
+// flush ends the current pixel, if it has any samples.
+func (o *outliner) flush() {
+	if o.n == 0 {
+		return
+	}
+	o.w.Data = append(o.w.Data, toInt8(o.lo), toInt8(o.hi))
+	o.w.RMS = append(o.w.RMS, toInt8(math.Sqrt(o.sum/float64(o.n))))
+	o.w.Length++
+	o.n, o.lo, o.hi, o.sum = 0, 1, -1, 0
+}
+
+// waveform ends the last pixel and returns the outline.
+func (o *outliner) waveform() Waveform {
+	o.flush()
+	return o.w
+}
+
+func toInt8(v float64) int8 {
+	return int8(math.Max(-128, math.Min(127, math.Round(v*128))))
+}
+
+// Waveform outlines p with samplesPerPixel frames to a pixel, averaging its
+// channels.
+func (p PCM) Waveform(samplesPerPixel int) Waveform {
+	samplesPerPixel = max(samplesPerPixel, 1)
+	channels := p.samples()
+	frames := p.Frames()
+	o := newOutliner(p.SampleRate, samplesPerPixel, frames)
+	for i := range frames {
+		var v float64
+		for _, ch := range channels {
+			v += ch[i]
+		}
+		o.add(v / float64(len(channels)))
+	}
+	return o.waveform()
+}
+
+// mp3Waveform decodes an MP3 file and outlines it with samplesPerPixel
+// frames to a pixel. The audio is decoded a block at a time, so only the
+// waveform is held in memory.
+func mp3Waveform(data []byte) (Waveform, error) {
+	frames, info := mp3Frames(data)
+	if len(frames) == 0 {
+		return Waveform{}, ErrNotMP3
+	}
+	samples := 0
+	for _, f := range info {
+		if f.layer != 3 {
+			return Waveform{}, errors.New("waveforms need MPEG layer III audio")
+		}
+		samples += f.samples
+	}
+
+	// Tags and VBR header frames are left out, so they are not decoded as
+	// a frame of silence
+	var stream bytes.Buffer
+	for _, frame := range frames {
+		stream.Write(frame)
+	}
+	dec, err := mp3.NewDecoder(&stream)
+	if err != nil {
+		return Waveform{}, fmt.Errorf("cannot decode MP3: %w", err)
+	}
+
+	// The decoder always yields 16-bit stereo, with mono copied to both
+	// channels
+	o := newOutliner(dec.SampleRate(), max(dec.SampleRate()/WaveformPixelsPerSecond, 1), samples)
+	buf := make([]byte, 64<<10)
+	for {
+		n, err := io.ReadFull(dec, buf)
+		for i := 0; i+4 <= n; i += 4 {
+			left := int16(binary.LittleEndian.Uint16(buf[i:]))
+			right := int16(binary.LittleEndian.Uint16(buf[i+2:]))
+			o.add((float64(left) + float64(right)) / 2 / 32768)
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return Waveform{}, fmt.Errorf("cannot decode MP3: %w", err)
+		}
+	}
+	return o.waveform(), nil
+}
+
+// ComputeWaveform outlines a WAV or MP3 file at WaveformPixelsPerSecond.
+func ComputeWaveform(data []byte) (Waveform, error) {
+	info, err := Probe(data)
+	if err != nil {
+		return Waveform{}, err
+	}
+	if info.Format == "mp3" {
+		return mp3Waveform(data)
+	}
+	p, err := DecodeWAV(data)
+	if err != nil {
+		return Waveform{}, err
+	}
+	return p.Waveform(max(p.SampleRate/WaveformPixelsPerSecond, 1)), nil
+}
@@ -14,6 +14,7 @@ import (
 	sql "database/sql"
 	reflect "reflect"
 
+	uuid "github.com/google/uuid"
 	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -42,6 +43,21 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// CompleteDictationAudio mocks base method.
+func (m *MockStore) CompleteDictationAudio(ctx context.Context, arg db.CompleteDictationAudioParams) (db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteDictationAudio", ctx, arg)
+	ret0, _ := ret[0].(db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteDictationAudio indicates an expected call of CompleteDictationAudio.
+func (mr *MockStoreMockRecorder) CompleteDictationAudio(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteDictationAudio", reflect.TypeOf((*MockStore)(nil).CompleteDictationAudio), ctx, arg)
+}
+
 // CountAttemptsByDictation mocks base method.
 func (m *MockStore) CountAttemptsByDictation(ctx context.Context, arg db.CountAttemptsByDictationParams) (int64, error) {
 	m.ctrl.T.Helper()
@@ -57,6 +73,21 @@ func (mr *MockStoreMockRecorder) CountAttemptsByDictation(ctx, arg any) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountAttemptsByDictation", reflect.TypeOf((*MockStore)(nil).CountAttemptsByDictation), ctx, arg)
 }
 
+// CreateAttemptSession mocks base method.
+func (m *MockStore) CreateAttemptSession(ctx context.Context, arg db.CreateAttemptSessionParams) (db.AttemptSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAttemptSession", ctx, arg)
+	ret0, _ := ret[0].(db.AttemptSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAttemptSession indicates an expected call of CreateAttemptSession.
+func (mr *MockStoreMockRecorder) CreateAttemptSession(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAttemptSession", reflect.TypeOf((*MockStore)(nil).CreateAttemptSession), ctx, arg)
+}
+
 // CreateAttempts mocks base method.
 func (m *MockStore) CreateAttempts(ctx context.Context, arg db.CreateAttemptsParams) (db.Attempt, error) {
 	m.ctrl.T.Helper()
@@ -246,6 +277,20 @@ func (mr *MockStoreMockRecorder) DeleteSetting(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSetting", reflect.TypeOf((*MockStore)(nil).DeleteSetting), ctx, id)
 }
 
+// DeleteUserPerformanceSummaries mocks base method.
+func (m *MockStore) DeleteUserPerformanceSummaries(ctx context.Context, userID sql.NullInt64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserPerformanceSummaries", ctx, userID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserPerformanceSummaries indicates an expected call of DeleteUserPerformanceSummaries.
+func (mr *MockStoreMockRecorder) DeleteUserPerformanceSummaries(ctx, userID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserPerformanceSummaries", reflect.TypeOf((*MockStore)(nil).DeleteUserPerformanceSummaries), ctx, userID)
+}
+
 // DeleteUsers mocks base method.
 func (m *MockStore) DeleteUsers(ctx context.Context, username string) error {
 	m.ctrl.T.Helper()
@@ -260,6 +305,21 @@ func (mr *MockStoreMockRecorder) DeleteUsers(ctx, username any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUsers", reflect.TypeOf((*MockStore)(nil).DeleteUsers), ctx, username)
 }
 
+// FailDictationAudio mocks base method.
+func (m *MockStore) FailDictationAudio(ctx context.Context, arg db.FailDictationAudioParams) (db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FailDictationAudio", ctx, arg)
+	ret0, _ := ret[0].(db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FailDictationAudio indicates an expected call of FailDictationAudio.
+func (mr *MockStoreMockRecorder) FailDictationAudio(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FailDictationAudio", reflect.TypeOf((*MockStore)(nil).FailDictationAudio), ctx, arg)
+}
+
 // GetAttemptById mocks base method.
 func (m *MockStore) GetAttemptById(ctx context.Context, id int64) (db.Attempt, error) {
 	m.ctrl.T.Helper()
@@ -275,6 +335,21 @@ func (mr *MockStoreMockRecorder) GetAttemptById(ctx, id any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttemptById", reflect.TypeOf((*MockStore)(nil).GetAttemptById), ctx, id)
 }
 
+// GetAttemptSession mocks base method.
+func (m *MockStore) GetAttemptSession(ctx context.Context, id uuid.UUID) (db.AttemptSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAttemptSession", ctx, id)
+	ret0, _ := ret[0].(db.AttemptSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAttemptSession indicates an expected call of GetAttemptSession.
+func (mr *MockStoreMockRecorder) GetAttemptSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAttemptSession", reflect.TypeOf((*MockStore)(nil).GetAttemptSession), ctx, id)
+}
+
 // GetDictation mocks base method.
 func (m *MockStore) GetDictation(ctx context.Context, id int64) (db.Dictation, error) {
 	m.ctrl.T.Helper()
@@ -350,6 +425,21 @@ func (mr *MockStoreMockRecorder) GetPerformanceSummaryByUserAndDictation(ctx, ar
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPerformanceSummaryByUserAndDictation", reflect.TypeOf((*MockStore)(nil).GetPerformanceSummaryByUserAndDictation), ctx, arg)
 }
 
+// GetRollingSpeed mocks base method.
+func (m *MockStore) GetRollingSpeed(ctx context.Context, arg db.GetRollingSpeedParams) (db.GetRollingSpeedRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRollingSpeed", ctx, arg)
+	ret0, _ := ret[0].(db.GetRollingSpeedRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRollingSpeed indicates an expected call of GetRollingSpeed.
+func (mr *MockStoreMockRecorder) GetRollingSpeed(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRollingSpeed", reflect.TypeOf((*MockStore)(nil).GetRollingSpeed), ctx, arg)
+}
+
 // GetSettingByID mocks base method.
 func (m *MockStore) GetSettingByID(ctx context.Context, id int64) (db.Setting, error) {
 	m.ctrl.T.Helper()
@@ -380,6 +470,21 @@ func (mr *MockStoreMockRecorder) GetSettingByUserID(ctx, userID any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSettingByUserID", reflect.TypeOf((*MockStore)(nil).GetSettingByUserID), ctx, userID)
 }
 
+// GetUnusedAttemptSession mocks base method.
+func (m *MockStore) GetUnusedAttemptSession(ctx context.Context, id uuid.UUID) (db.GetUnusedAttemptSessionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnusedAttemptSession", ctx, id)
+	ret0, _ := ret[0].(db.GetUnusedAttemptSessionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnusedAttemptSession indicates an expected call of GetUnusedAttemptSession.
+func (mr *MockStoreMockRecorder) GetUnusedAttemptSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnusedAttemptSession", reflect.TypeOf((*MockStore)(nil).GetUnusedAttemptSession), ctx, id)
+}
+
 // GetUsers mocks base method.
 func (m *MockStore) GetUsers(ctx context.Context, username string) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -395,6 +500,21 @@ func (mr *MockStoreMockRecorder) GetUsers(ctx, username any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockStore)(nil).GetUsers), ctx, username)
 }
 
+// GetWeightedAccuracyStats mocks base method.
+func (m *MockStore) GetWeightedAccuracyStats(ctx context.Context, arg db.GetWeightedAccuracyStatsParams) (db.GetWeightedAccuracyStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWeightedAccuracyStats", ctx, arg)
+	ret0, _ := ret[0].(db.GetWeightedAccuracyStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWeightedAccuracyStats indicates an expected call of GetWeightedAccuracyStats.
+func (mr *MockStoreMockRecorder) GetWeightedAccuracyStats(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWeightedAccuracyStats", reflect.TypeOf((*MockStore)(nil).GetWeightedAccuracyStats), ctx, arg)
+}
+
 // ListAttemptsByDictation mocks base method.
 func (m *MockStore) ListAttemptsByDictation(ctx context.Context, dictationID sql.NullInt64) ([]db.Attempt, error) {
 	m.ctrl.T.Helper()
@@ -425,6 +545,21 @@ func (mr *MockStoreMockRecorder) ListAttemptsByUser(ctx, userID any) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttemptsByUser", reflect.TypeOf((*MockStore)(nil).ListAttemptsByUser), ctx, userID)
 }
 
+// ListAttemptsToRescore mocks base method.
+func (m *MockStore) ListAttemptsToRescore(ctx context.Context, arg db.ListAttemptsToRescoreParams) ([]db.Attempt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAttemptsToRescore", ctx, arg)
+	ret0, _ := ret[0].([]db.Attempt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAttemptsToRescore indicates an expected call of ListAttemptsToRescore.
+func (mr *MockStoreMockRecorder) ListAttemptsToRescore(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAttemptsToRescore", reflect.TypeOf((*MockStore)(nil).ListAttemptsToRescore), ctx, arg)
+}
+
 // ListAudioDictations mocks base method.
 func (m *MockStore) ListAudioDictations(ctx context.Context, userID sql.NullInt64) ([]db.Dictation, error) {
 	m.ctrl.T.Helper()
@@ -440,6 +575,21 @@ func (mr *MockStoreMockRecorder) ListAudioDictations(ctx, userID any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAudioDictations", reflect.TypeOf((*MockStore)(nil).ListAudioDictations), ctx, userID)
 }
 
+// ListDictationsByAudioStatus mocks base method.
+func (m *MockStore) ListDictationsByAudioStatus(ctx context.Context, audioStatus sql.NullString) ([]db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDictationsByAudioStatus", ctx, audioStatus)
+	ret0, _ := ret[0].([]db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDictationsByAudioStatus indicates an expected call of ListDictationsByAudioStatus.
+func (mr *MockStoreMockRecorder) ListDictationsByAudioStatus(ctx, audioStatus any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDictationsByAudioStatus", reflect.TypeOf((*MockStore)(nil).ListDictationsByAudioStatus), ctx, audioStatus)
+}
+
 // ListDictationsByUser mocks base method.
 func (m *MockStore) ListDictationsByUser(ctx context.Context, userID sql.NullInt64) ([]db.Dictation, error) {
 	m.ctrl.T.Helper()
@@ -500,6 +650,35 @@ func (mr *MockStoreMockRecorder) ListUsers(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockStore)(nil).ListUsers), ctx, arg)
 }
 
+// QueueDictationAudio mocks base method.
+func (m *MockStore) QueueDictationAudio(ctx context.Context, id int64) (db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QueueDictationAudio", ctx, id)
+	ret0, _ := ret[0].(db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QueueDictationAudio indicates an expected call of QueueDictationAudio.
+func (mr *MockStoreMockRecorder) QueueDictationAudio(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QueueDictationAudio", reflect.TypeOf((*MockStore)(nil).QueueDictationAudio), ctx, id)
+}
+
+// RebuildUserPerformanceSummaries mocks base method.
+func (m *MockStore) RebuildUserPerformanceSummaries(ctx context.Context, arg db.RebuildUserPerformanceSummariesParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RebuildUserPerformanceSummaries", ctx, arg)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RebuildUserPerformanceSummaries indicates an expected call of RebuildUserPerformanceSummaries.
+func (mr *MockStoreMockRecorder) RebuildUserPerformanceSummaries(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RebuildUserPerformanceSummaries", reflect.TypeOf((*MockStore)(nil).RebuildUserPerformanceSummaries), ctx, arg)
+}
+
 // RecentAttemptsByUser mocks base method.
 func (m *MockStore) RecentAttemptsByUser(ctx context.Context, arg db.RecentAttemptsByUserParams) ([]db.PerformanceSummary, error) {
 	m.ctrl.T.Helper()
@@ -515,6 +694,50 @@ func (mr *MockStoreMockRecorder) RecentAttemptsByUser(ctx, arg any) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecentAttemptsByUser", reflect.TypeOf((*MockStore)(nil).RecentAttemptsByUser), ctx, arg)
 }
 
+// RescoreAttemptsTx mocks base method.
+func (m *MockStore) RescoreAttemptsTx(ctx context.Context, updates []db.UpdateAttemptAccuracyParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RescoreAttemptsTx", ctx, updates)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RescoreAttemptsTx indicates an expected call of RescoreAttemptsTx.
+func (mr *MockStoreMockRecorder) RescoreAttemptsTx(ctx, updates any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RescoreAttemptsTx", reflect.TypeOf((*MockStore)(nil).RescoreAttemptsTx), ctx, updates)
+}
+
+// SetDictationLexicon mocks base method.
+func (m *MockStore) SetDictationLexicon(ctx context.Context, arg db.SetDictationLexiconParams) (db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDictationLexicon", ctx, arg)
+	ret0, _ := ret[0].(db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDictationLexicon indicates an expected call of SetDictationLexicon.
+func (mr *MockStoreMockRecorder) SetDictationLexicon(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDictationLexicon", reflect.TypeOf((*MockStore)(nil).SetDictationLexicon), ctx, arg)
+}
+
+// SetDictationTranscript mocks base method.
+func (m *MockStore) SetDictationTranscript(ctx context.Context, arg db.SetDictationTranscriptParams) (db.Dictation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDictationTranscript", ctx, arg)
+	ret0, _ := ret[0].(db.Dictation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDictationTranscript indicates an expected call of SetDictationTranscript.
+func (mr *MockStoreMockRecorder) SetDictationTranscript(ctx, arg any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDictationTranscript", reflect.TypeOf((*MockStore)(nil).SetDictationTranscript), ctx, arg)
+}
+
 // SubmitAttemptTx mocks base method.
 func (m *MockStore) SubmitAttemptTx(ctx context.Context, arg db.CreateAttemptsParams) (db.SubmitAttemptTxResult, error) {
 	m.ctrl.T.Helper()
@@ -605,6 +828,21 @@ func (mr *MockStoreMockRecorder) UpdateUsers(ctx, arg any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUsers", reflect.TypeOf((*MockStore)(nil).UpdateUsers), ctx, arg)
 }
 
+// UseAttemptSession mocks base method.
+func (m *MockStore) UseAttemptSession(ctx context.Context, id uuid.UUID) (db.UseAttemptSessionRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseAttemptSession", ctx, id)
+	ret0, _ := ret[0].(db.UseAttemptSessionRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseAttemptSession indicates an expected call of UseAttemptSession.
+func (mr *MockStoreMockRecorder) UseAttemptSession(ctx, id any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseAttemptSession", reflect.TypeOf((*MockStore)(nil).UseAttemptSession), ctx, id)
+}
+
 // UserAggregatePerformance mocks base method.
 func (m *MockStore) UserAggregatePerformance(ctx context.Context) ([]db.UserAggregatePerformanceRow, error) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,143 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: attempt_sessions.sql
+
+package db
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createAttemptSession = `-- name: CreateAttemptSession :one
+INSERT INTO attempt_sessions (
+  id, user_id, dictation_id, segment
+) VALUES (
+  $1, $2, $3, $4
+)
+RETURNING id, user_id, dictation_id, started_at, used_at, segment
+`
+
+type CreateAttemptSessionParams struct {
+	ID          uuid.UUID     `json:"id"`
+	UserID      int64         `json:"user_id"`
+	DictationID int64         `json:"dictation_id"`
+	Segment     sql.NullInt32 `json:"segment"`
+}
+
+func (q *Queries) CreateAttemptSession(ctx context.Context, arg CreateAttemptSessionParams) (AttemptSession, error) {
+	row := q.db.QueryRowContext(ctx, createAttemptSession,
+		arg.ID,
+		arg.UserID,
+		arg.DictationID,
+		arg.Segment,
+	)
+	var i AttemptSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DictationID,
+		&i.StartedAt,
+		&i.UsedAt,
+		&i.Segment,
+	)
+	return i, err
+}
+
+const getAttemptSession = `-- name: GetAttemptSession :one
+SELECT id, user_id, dictation_id, started_at, used_at, segment FROM attempt_sessions
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetAttemptSession(ctx context.Context, id uuid.UUID) (AttemptSession, error) {
+	row := q.db.QueryRowContext(ctx, getAttemptSession, id)
+	var i AttemptSession
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DictationID,
+		&i.StartedAt,
+		&i.UsedAt,
+		&i.Segment,
+	)
+	return i, err
+}
+
+const getUnusedAttemptSession = `-- name: GetUnusedAttemptSession :one
+SELECT
+  id,
+  user_id,
+  dictation_id,
+  started_at,
+  segment,
+  EXTRACT(EPOCH FROM (NOW() - started_at))::float AS elapsed_seconds
+FROM attempt_sessions
+WHERE id = $1 AND used_at IS NULL
+`
+
+type GetUnusedAttemptSessionRow struct {
+	ID             uuid.UUID     `json:"id"`
+	UserID         int64         `json:"user_id"`
+	DictationID    int64         `json:"dictation_id"`
+	StartedAt      time.Time     `json:"started_at"`
+	Segment        sql.NullInt32 `json:"segment"`
+	ElapsedSeconds float64       `json:"elapsed_seconds"`
+}
+
+// Returns the session if it has not been used yet, with the elapsed time
+// measured like UseAttemptSession does.
+func (q *Queries) GetUnusedAttemptSession(ctx context.Context, id uuid.UUID) (GetUnusedAttemptSessionRow, error) {
+	row := q.db.QueryRowContext(ctx, getUnusedAttemptSession, id)
+	var i GetUnusedAttemptSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DictationID,
+		&i.StartedAt,
+		&i.Segment,
+		&i.ElapsedSeconds,
+	)
+	return i, err
+}
+
+const useAttemptSession = `-- name: UseAttemptSession :one
+UPDATE attempt_sessions
+SET used_at = NOW()
+WHERE id = $1 AND used_at IS NULL
+RETURNING
+  id,
+  user_id,
+  dictation_id,
+  started_at,
+  segment,
+  EXTRACT(EPOCH FROM (NOW() - started_at))::float AS elapsed_seconds
+`
+
+type UseAttemptSessionRow struct {
+	ID             uuid.UUID     `json:"id"`
+	UserID         int64         `json:"user_id"`
+	DictationID    int64         `json:"dictation_id"`
+	StartedAt      time.Time     `json:"started_at"`
+	Segment        sql.NullInt32 `json:"segment"`
+	ElapsedSeconds float64       `json:"elapsed_seconds"`
+}
+
+// Marks the session used, returning no rows if it was already used, and
+// measures the elapsed time on the database clock that set started_at.
+func (q *Queries) UseAttemptSession(ctx context.Context, id uuid.UUID) (UseAttemptSessionRow, error) {
+	row := q.db.QueryRowContext(ctx, useAttemptSession, id)
+	var i UseAttemptSessionRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.DictationID,
+		&i.StartedAt,
+		&i.Segment,
+		&i.ElapsedSeconds,
+	)
+	return i, err
+}
@@ -0,0 +1,48 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUseAttemptSession(t *testing.T) {
+	user := RandomUser(t)
+	dict := RandomTextDictation(t, user)
+
+	session, err := testQueries.CreateAttemptSession(context.Background(), CreateAttemptSessionParams{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		DictationID: dict.ID,
+	})
+	require.NoError(t, err)
+	// started_at is an instant, whatever the database's time zone, so the
+	// session token expires on time
+	require.WithinDuration(t, time.Now(), session.StartedAt, time.Minute)
+	require.False(t, session.UsedAt.Valid)
+
+	open, err := testQueries.GetUnusedAttemptSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, open.ID)
+	require.GreaterOrEqual(t, open.ElapsedSeconds, 0.0)
+
+	used, err := testQueries.UseAttemptSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.Equal(t, session.ID, used.ID)
+	require.Equal(t, dict.ID, used.DictationID)
+	require.GreaterOrEqual(t, used.ElapsedSeconds, 0.0)
+
+	// A session can only be used once
+	_, err = testQueries.UseAttemptSession(context.Background(), session.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = testQueries.GetUnusedAttemptSession(context.Background(), session.ID)
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	got, err := testQueries.GetAttemptSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, got.UsedAt.Valid)
+}
@@ -9,6 +9,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
@@ -37,12 +38,30 @@ INSERT INTO attempts (
   attempt_no, 
   total_words, 
   correct_words, 
-  grammatical_errors, 
+  wrong_word_errors, 
   spelling_errors, 
   case_errors, 
+  punctuation_errors,
+  omission_errors,
+  insertion_errors,
   accuracy, 
   comparison_data, 
   time_spent,
+  mark_sheet,
+  scoring_version,
+  gross_wpm,
+  net_wpm,
+  cpm,
+  char_accuracy,
+  keystrokes,
+  kspc,
+  session_id,
+  client_time_spent,
+  time_flagged,
+  input_encoding,
+  weighted_accuracy,
+  paragraph_errors,
+  segment,
   created_at
 ) VALUES (
   $1, $2, $3, 
@@ -51,9 +70,11 @@ INSERT INTO attempts (
     FROM attempts
     WHERE user_id = $1 AND dictation_id = $2
   ), 1), 
-  $4, $5, $6, $7, $8, $9, $10, $11, NOW()
+  $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16,
+  $17, $18, $19, $20, $21, $22,
+  $23, $24, $25, $26, $27, $28, $29, NOW()
 )
-RETURNING id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at
+RETURNING id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment
 `
 
 type CreateAttemptsParams struct {
@@ -62,12 +83,30 @@ type CreateAttemptsParams struct {
 	TypedText         sql.NullString        `json:"typed_text"`
 	TotalWords        sql.NullInt32         `json:"total_words"`
 	CorrectWords      sql.NullInt32         `json:"correct_words"`
-	GrammaticalErrors sql.NullInt32         `json:"grammatical_errors"`
+	WrongWordErrors   sql.NullInt32         `json:"wrong_word_errors"`
 	SpellingErrors    sql.NullInt32         `json:"spelling_errors"`
 	CaseErrors        sql.NullInt32         `json:"case_errors"`
+	PunctuationErrors sql.NullInt32         `json:"punctuation_errors"`
+	OmissionErrors    sql.NullInt32         `json:"omission_errors"`
+	InsertionErrors   sql.NullInt32         `json:"insertion_errors"`
 	Accuracy          sql.NullFloat64       `json:"accuracy"`
 	ComparisonData    pqtype.NullRawMessage `json:"comparison_data"`
 	TimeSpent         sql.NullFloat64       `json:"time_spent"`
+	MarkSheet         pqtype.NullRawMessage `json:"mark_sheet"`
+	ScoringVersion    sql.NullString        `json:"scoring_version"`
+	GrossWpm          sql.NullFloat64       `json:"gross_wpm"`
+	NetWpm            sql.NullFloat64       `json:"net_wpm"`
+	Cpm               sql.NullFloat64       `json:"cpm"`
+	CharAccuracy      sql.NullFloat64       `json:"char_accuracy"`
+	Keystrokes        sql.NullInt32         `json:"keystrokes"`
+	Kspc              sql.NullFloat64       `json:"kspc"`
+	SessionID         uuid.NullUUID         `json:"session_id"`
+	ClientTimeSpent   sql.NullFloat64       `json:"client_time_spent"`
+	TimeFlagged       bool                  `json:"time_flagged"`
+	InputEncoding     string                `json:"input_encoding"`
+	WeightedAccuracy  sql.NullFloat64       `json:"weighted_accuracy"`
+	ParagraphErrors   sql.NullInt32         `json:"paragraph_errors"`
+	Segment           sql.NullInt32         `json:"segment"`
 }
 
 func (q *Queries) CreateAttempts(ctx context.Context, arg CreateAttemptsParams) (Attempt, error) {
@@ -77,12 +116,30 @@ func (q *Queries) CreateAttempts(ctx context.Context, arg CreateAttemptsParams)
 		arg.TypedText,
 		arg.TotalWords,
 		arg.CorrectWords,
-		arg.GrammaticalErrors,
+		arg.WrongWordErrors,
 		arg.SpellingErrors,
 		arg.CaseErrors,
+		arg.PunctuationErrors,
+		arg.OmissionErrors,
+		arg.InsertionErrors,
 		arg.Accuracy,
 		arg.ComparisonData,
 		arg.TimeSpent,
+		arg.MarkSheet,
+		arg.ScoringVersion,
+		arg.GrossWpm,
+		arg.NetWpm,
+		arg.Cpm,
+		arg.CharAccuracy,
+		arg.Keystrokes,
+		arg.Kspc,
+		arg.SessionID,
+		arg.ClientTimeSpent,
+		arg.TimeFlagged,
+		arg.InputEncoding,
+		arg.WeightedAccuracy,
+		arg.ParagraphErrors,
+		arg.Segment,
 	)
 	var i Attempt
 	err := row.Scan(
@@ -100,6 +157,25 @@ func (q *Queries) CreateAttempts(ctx context.Context, arg CreateAttemptsParams)
 		&i.ComparisonData,
 		&i.TimeSpent,
 		&i.CreatedAt,
+		&i.PunctuationErrors,
+		&i.OmissionErrors,
+		&i.InsertionErrors,
+		&i.WrongWordErrors,
+		&i.MarkSheet,
+		&i.ScoringVersion,
+		&i.GrossWpm,
+		&i.NetWpm,
+		&i.Cpm,
+		&i.CharAccuracy,
+		&i.Keystrokes,
+		&i.Kspc,
+		&i.SessionID,
+		&i.ClientTimeSpent,
+		&i.TimeFlagged,
+		&i.InputEncoding,
+		&i.WeightedAccuracy,
+		&i.ParagraphErrors,
+		&i.Segment,
 	)
 	return i, err
 }
@@ -130,7 +206,7 @@ func (q *Queries) DeleteAttemptsByDictation(ctx context.Context, arg DeleteAttem
 }
 
 const getAttemptById = `-- name: GetAttemptById :one
-SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at FROM attempts
+SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment FROM attempts
 WHERE id = $1 LIMIT 1
 `
 
@@ -152,12 +228,31 @@ func (q *Queries) GetAttemptById(ctx context.Context, id int64) (Attempt, error)
 		&i.ComparisonData,
 		&i.TimeSpent,
 		&i.CreatedAt,
+		&i.PunctuationErrors,
+		&i.OmissionErrors,
+		&i.InsertionErrors,
+		&i.WrongWordErrors,
+		&i.MarkSheet,
+		&i.ScoringVersion,
+		&i.GrossWpm,
+		&i.NetWpm,
+		&i.Cpm,
+		&i.CharAccuracy,
+		&i.Keystrokes,
+		&i.Kspc,
+		&i.SessionID,
+		&i.ClientTimeSpent,
+		&i.TimeFlagged,
+		&i.InputEncoding,
+		&i.WeightedAccuracy,
+		&i.ParagraphErrors,
+		&i.Segment,
 	)
 	return i, err
 }
 
 const getLatestAttempt = `-- name: GetLatestAttempt :one
-SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at FROM attempts
+SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment FROM attempts
 WHERE user_id = $1 AND dictation_id = $2
 ORDER BY created_at DESC
 `
@@ -185,12 +280,96 @@ func (q *Queries) GetLatestAttempt(ctx context.Context, arg GetLatestAttemptPara
 		&i.ComparisonData,
 		&i.TimeSpent,
 		&i.CreatedAt,
+		&i.PunctuationErrors,
+		&i.OmissionErrors,
+		&i.InsertionErrors,
+		&i.WrongWordErrors,
+		&i.MarkSheet,
+		&i.ScoringVersion,
+		&i.GrossWpm,
+		&i.NetWpm,
+		&i.Cpm,
+		&i.CharAccuracy,
+		&i.Keystrokes,
+		&i.Kspc,
+		&i.SessionID,
+		&i.ClientTimeSpent,
+		&i.TimeFlagged,
+		&i.InputEncoding,
+		&i.WeightedAccuracy,
+		&i.ParagraphErrors,
+		&i.Segment,
 	)
 	return i, err
 }
 
+const getRollingSpeed = `-- name: GetRollingSpeed :one
+SELECT
+    COALESCE(AVG(gross_wpm), 0)::float AS rolling_gross_wpm,
+    COALESCE(AVG(net_wpm), 0)::float AS rolling_net_wpm,
+    COALESCE(AVG(cpm), 0)::float AS rolling_cpm,
+    COALESCE(AVG(char_accuracy), 0)::float AS rolling_char_accuracy
+FROM (
+    SELECT gross_wpm, net_wpm, cpm, char_accuracy
+    FROM attempts
+    WHERE user_id = $1 AND dictation_id = $2 AND segment IS NULL
+    ORDER BY created_at DESC, id DESC
+    LIMIT $3
+) AS recent
+`
+
+type GetRollingSpeedParams struct {
+	UserID      sql.NullInt64 `json:"user_id"`
+	DictationID sql.NullInt64 `json:"dictation_id"`
+	Limit       int32         `json:"limit"`
+}
+
+type GetRollingSpeedRow struct {
+	RollingGrossWpm     float64 `json:"rolling_gross_wpm"`
+	RollingNetWpm       float64 `json:"rolling_net_wpm"`
+	RollingCpm          float64 `json:"rolling_cpm"`
+	RollingCharAccuracy float64 `json:"rolling_char_accuracy"`
+}
+
+func (q *Queries) GetRollingSpeed(ctx context.Context, arg GetRollingSpeedParams) (GetRollingSpeedRow, error) {
+	row := q.db.QueryRowContext(ctx, getRollingSpeed, arg.UserID, arg.DictationID, arg.Limit)
+	var i GetRollingSpeedRow
+	err := row.Scan(
+		&i.RollingGrossWpm,
+		&i.RollingNetWpm,
+		&i.RollingCpm,
+		&i.RollingCharAccuracy,
+	)
+	return i, err
+}
+
+const getWeightedAccuracyStats = `-- name: GetWeightedAccuracyStats :one
+SELECT
+    COALESCE(MAX(weighted_accuracy), 0)::float AS best_weighted_accuracy,
+    COALESCE(AVG(weighted_accuracy), 0)::float AS average_weighted_accuracy
+FROM attempts
+WHERE user_id = $1 AND dictation_id = $2 AND segment IS NULL
+`
+
+type GetWeightedAccuracyStatsParams struct {
+	UserID      sql.NullInt64 `json:"user_id"`
+	DictationID sql.NullInt64 `json:"dictation_id"`
+}
+
+type GetWeightedAccuracyStatsRow struct {
+	BestWeightedAccuracy    float64 `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy float64 `json:"average_weighted_accuracy"`
+}
+
+func (q *Queries) GetWeightedAccuracyStats(ctx context.Context, arg GetWeightedAccuracyStatsParams) (GetWeightedAccuracyStatsRow, error) {
+	row := q.db.QueryRowContext(ctx, getWeightedAccuracyStats, arg.UserID, arg.DictationID)
+	var i GetWeightedAccuracyStatsRow
+	err := row.Scan(&i.BestWeightedAccuracy, &i.AverageWeightedAccuracy)
+	return i, err
+}
+
 const listAttemptsByDictation = `-- name: ListAttemptsByDictation :many
-SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at FROM attempts
+SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment FROM attempts
 WHERE dictation_id = $1
 ORDER BY created_at DESC
 `
@@ -219,6 +398,25 @@ func (q *Queries) ListAttemptsByDictation(ctx context.Context, dictationID sql.N
 			&i.ComparisonData,
 			&i.TimeSpent,
 			&i.CreatedAt,
+			&i.PunctuationErrors,
+			&i.OmissionErrors,
+			&i.InsertionErrors,
+			&i.WrongWordErrors,
+			&i.MarkSheet,
+			&i.ScoringVersion,
+			&i.GrossWpm,
+			&i.NetWpm,
+			&i.Cpm,
+			&i.CharAccuracy,
+			&i.Keystrokes,
+			&i.Kspc,
+			&i.SessionID,
+			&i.ClientTimeSpent,
+			&i.TimeFlagged,
+			&i.InputEncoding,
+			&i.WeightedAccuracy,
+			&i.ParagraphErrors,
+			&i.Segment,
 		); err != nil {
 			return nil, err
 		}
@@ -234,7 +432,7 @@ func (q *Queries) ListAttemptsByDictation(ctx context.Context, dictationID sql.N
 }
 
 const listAttemptsByUser = `-- name: ListAttemptsByUser :many
-SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at FROM attempts
+SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment FROM attempts
 WHERE user_id = $1
 ORDER by created_at DESC
 `
@@ -263,6 +461,103 @@ func (q *Queries) ListAttemptsByUser(ctx context.Context, userID sql.NullInt64)
 			&i.ComparisonData,
 			&i.TimeSpent,
 			&i.CreatedAt,
+			&i.PunctuationErrors,
+			&i.OmissionErrors,
+			&i.InsertionErrors,
+			&i.WrongWordErrors,
+			&i.MarkSheet,
+			&i.ScoringVersion,
+			&i.GrossWpm,
+			&i.NetWpm,
+			&i.Cpm,
+			&i.CharAccuracy,
+			&i.Keystrokes,
+			&i.Kspc,
+			&i.SessionID,
+			&i.ClientTimeSpent,
+			&i.TimeFlagged,
+			&i.InputEncoding,
+			&i.WeightedAccuracy,
+			&i.ParagraphErrors,
+			&i.Segment,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAttemptsToRescore = `-- name: ListAttemptsToRescore :many
+SELECT id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment FROM attempts
+WHERE ($1::boolean
+   OR scoring_version IS DISTINCT FROM $2::varchar)
+  AND id > $3::bigint
+ORDER BY id
+LIMIT $4::int
+`
+
+type ListAttemptsToRescoreParams struct {
+	Force          bool   `json:"force"`
+	ScoringVersion string `json:"scoring_version"`
+	AfterID        int64  `json:"after_id"`
+	BatchSize      int32  `json:"batch_size"`
+}
+
+func (q *Queries) ListAttemptsToRescore(ctx context.Context, arg ListAttemptsToRescoreParams) ([]Attempt, error) {
+	rows, err := q.db.QueryContext(ctx, listAttemptsToRescore,
+		arg.Force,
+		arg.ScoringVersion,
+		arg.AfterID,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Attempt
+	for rows.Next() {
+		var i Attempt
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.DictationID,
+			&i.TypedText,
+			&i.AttemptNo,
+			&i.TotalWords,
+			&i.CorrectWords,
+			&i.GrammaticalErrors,
+			&i.SpellingErrors,
+			&i.CaseErrors,
+			&i.Accuracy,
+			&i.ComparisonData,
+			&i.TimeSpent,
+			&i.CreatedAt,
+			&i.PunctuationErrors,
+			&i.OmissionErrors,
+			&i.InsertionErrors,
+			&i.WrongWordErrors,
+			&i.MarkSheet,
+			&i.ScoringVersion,
+			&i.GrossWpm,
+			&i.NetWpm,
+			&i.Cpm,
+			&i.CharAccuracy,
+			&i.Keystrokes,
+			&i.Kspc,
+			&i.SessionID,
+			&i.ClientTimeSpent,
+			&i.TimeFlagged,
+			&i.InputEncoding,
+			&i.WeightedAccuracy,
+			&i.ParagraphErrors,
+			&i.Segment,
 		); err != nil {
 			return nil, err
 		}
@@ -282,24 +577,50 @@ UPDATE attempts
 SET
   accuracy = $2,
   correct_words = $3,
-  grammatical_errors = $4,
+  wrong_word_errors = $4,
   spelling_errors = $5,
   case_errors = $6,
-  comparison_data = $7,
-  time_spent = $8
+  punctuation_errors = $7,
+  omission_errors = $8,
+  insertion_errors = $9,
+  comparison_data = $10,
+  time_spent = $11,
+  total_words = $12,
+  mark_sheet = $13,
+  scoring_version = $14,
+  gross_wpm = $15,
+  net_wpm = $16,
+  cpm = $17,
+  char_accuracy = $18,
+  kspc = $19,
+  weighted_accuracy = $20,
+  paragraph_errors = $21
 WHERE id = $1
-RETURNING id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at
+RETURNING id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment
 `
 
 type UpdateAttemptAccuracyParams struct {
 	ID                int64                 `json:"id"`
 	Accuracy          sql.NullFloat64       `json:"accuracy"`
 	CorrectWords      sql.NullInt32         `json:"correct_words"`
-	GrammaticalErrors sql.NullInt32         `json:"grammatical_errors"`
+	WrongWordErrors   sql.NullInt32         `json:"wrong_word_errors"`
 	SpellingErrors    sql.NullInt32         `json:"spelling_errors"`
 	CaseErrors        sql.NullInt32         `json:"case_errors"`
+	PunctuationErrors sql.NullInt32         `json:"punctuation_errors"`
+	OmissionErrors    sql.NullInt32         `json:"omission_errors"`
+	InsertionErrors   sql.NullInt32         `json:"insertion_errors"`
 	ComparisonData    pqtype.NullRawMessage `json:"comparison_data"`
 	TimeSpent         sql.NullFloat64       `json:"time_spent"`
+	TotalWords        sql.NullInt32         `json:"total_words"`
+	MarkSheet         pqtype.NullRawMessage `json:"mark_sheet"`
+	ScoringVersion    sql.NullString        `json:"scoring_version"`
+	GrossWpm          sql.NullFloat64       `json:"gross_wpm"`
+	NetWpm            sql.NullFloat64       `json:"net_wpm"`
+	Cpm               sql.NullFloat64       `json:"cpm"`
+	CharAccuracy      sql.NullFloat64       `json:"char_accuracy"`
+	Kspc              sql.NullFloat64       `json:"kspc"`
+	WeightedAccuracy  sql.NullFloat64       `json:"weighted_accuracy"`
+	ParagraphErrors   sql.NullInt32         `json:"paragraph_errors"`
 }
 
 func (q *Queries) UpdateAttemptAccuracy(ctx context.Context, arg UpdateAttemptAccuracyParams) (Attempt, error) {
@@ -307,11 +628,24 @@ func (q *Queries) UpdateAttemptAccuracy(ctx context.Context, arg UpdateAttemptAc
 		arg.ID,
 		arg.Accuracy,
 		arg.CorrectWords,
-		arg.GrammaticalErrors,
+		arg.WrongWordErrors,
 		arg.SpellingErrors,
 		arg.CaseErrors,
+		arg.PunctuationErrors,
+		arg.OmissionErrors,
+		arg.InsertionErrors,
 		arg.ComparisonData,
 		arg.TimeSpent,
+		arg.TotalWords,
+		arg.MarkSheet,
+		arg.ScoringVersion,
+		arg.GrossWpm,
+		arg.NetWpm,
+		arg.Cpm,
+		arg.CharAccuracy,
+		arg.Kspc,
+		arg.WeightedAccuracy,
+		arg.ParagraphErrors,
 	)
 	var i Attempt
 	err := row.Scan(
@@ -329,6 +663,25 @@ func (q *Queries) UpdateAttemptAccuracy(ctx context.Context, arg UpdateAttemptAc
 		&i.ComparisonData,
 		&i.TimeSpent,
 		&i.CreatedAt,
+		&i.PunctuationErrors,
+		&i.OmissionErrors,
+		&i.InsertionErrors,
+		&i.WrongWordErrors,
+		&i.MarkSheet,
+		&i.ScoringVersion,
+		&i.GrossWpm,
+		&i.NetWpm,
+		&i.Cpm,
+		&i.CharAccuracy,
+		&i.Keystrokes,
+		&i.Kspc,
+		&i.SessionID,
+		&i.ClientTimeSpent,
+		&i.TimeFlagged,
+		&i.InputEncoding,
+		&i.WeightedAccuracy,
+		&i.ParagraphErrors,
+		&i.Segment,
 	)
 	return i, err
 }
@@ -19,9 +19,12 @@ func createRandomAttempt(t *testing.T, userID int64, dictationID int64) Attempt
 			Int32: 2,
 			Valid: true,
 		},
-		GrammaticalErrors: sql.NullInt32{Int32: 0, Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: 0, Valid: true},
 		SpellingErrors:    sql.NullInt32{Int32: 0, Valid: true},
 		CaseErrors:        sql.NullInt32{Int32: 0, Valid: true},
+		PunctuationErrors: sql.NullInt32{Int32: 0, Valid: true},
+		OmissionErrors:    sql.NullInt32{Int32: 0, Valid: true},
+		InsertionErrors:   sql.NullInt32{Int32: 0, Valid: true},
 		Accuracy:          sql.NullFloat64{Float64: 100, Valid: true},
 		ComparisonData:    pqtype.NullRawMessage{RawMessage: []byte(`{}`), Valid: true},
 		TimeSpent:         sql.NullFloat64{Float64: 1.5, Valid: true},
@@ -134,9 +137,13 @@ func TestUpdateAttemptAccuracy(t *testing.T) {
 		ID:                attempt.ID,
 		Accuracy:          sql.NullFloat64{Float64: 50, Valid: true},
 		CorrectWords:      sql.NullInt32{Int32: 1, Valid: true},
-		GrammaticalErrors: sql.NullInt32{Int32: 1, Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: 1, Valid: true},
 		SpellingErrors:    sql.NullInt32{Int32: 1, Valid: true},
 		CaseErrors:        sql.NullInt32{Int32: 1, Valid: true},
+		PunctuationErrors: sql.NullInt32{Int32: 1, Valid: true},
+		OmissionErrors:    sql.NullInt32{Int32: 0, Valid: true},
+		InsertionErrors:   sql.NullInt32{Int32: 0, Valid: true},
+		ParagraphErrors:   sql.NullInt32{Int32: 2, Valid: true},
 		ComparisonData:    pqtype.NullRawMessage{RawMessage: []byte(`{"x":1}`), Valid: true},
 		TimeSpent:         sql.NullFloat64{Float64: 3.0, Valid: true},
 	}
@@ -145,6 +152,43 @@ func TestUpdateAttemptAccuracy(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, updated.ID, attempt.ID)
 	require.Equal(t, float64(50), updated.Accuracy.Float64)
+	require.Equal(t, int32(1), updated.PunctuationErrors.Int32)
+	require.Equal(t, int32(2), updated.ParagraphErrors.Int32)
+}
+
+func TestListAttemptsToRescore(t *testing.T) {
+	user := RandomUser(t)
+	dict := RandomTextDictation(t, user)
+	attempt := createRandomAttempt(t, user.ID, dict.ID)
+
+	_, err := testQueries.UpdateAttemptAccuracy(context.Background(), UpdateAttemptAccuracyParams{
+		ID:             attempt.ID,
+		Accuracy:       attempt.Accuracy,
+		TotalWords:     attempt.TotalWords,
+		ScoringVersion: sql.NullString{String: "current/v1", Valid: true},
+	})
+	require.NoError(t, err)
+
+	contains := func(attempts []Attempt) bool {
+		for _, a := range attempts {
+			if a.ID == attempt.ID {
+				return true
+			}
+		}
+		return false
+	}
+
+	stale, err := testQueries.ListAttemptsToRescore(context.Background(), ListAttemptsToRescoreParams{ScoringVersion: "current/v1", AfterID: attempt.ID - 1, BatchSize: 10})
+	require.NoError(t, err)
+	require.False(t, contains(stale))
+
+	stale, err = testQueries.ListAttemptsToRescore(context.Background(), ListAttemptsToRescoreParams{ScoringVersion: "current/v2", AfterID: attempt.ID - 1, BatchSize: 10})
+	require.NoError(t, err)
+	require.True(t, contains(stale))
+
+	all, err := testQueries.ListAttemptsToRescore(context.Background(), ListAttemptsToRescoreParams{Force: true, ScoringVersion: "current/v1", AfterID: attempt.ID - 1, BatchSize: 10})
+	require.NoError(t, err)
+	require.True(t, contains(all))
 }
 
 func TestDeleteAttempt(t *testing.T) {
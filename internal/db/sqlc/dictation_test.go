@@ -3,10 +3,12 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/nilesh0729/PixelScribe/internal/util"
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -169,3 +171,109 @@ func TestListDictationsByUser(t *testing.T) {
 }
 
 
+
+func TestDictationAudioLifecycle(t *testing.T) {
+	user := RandomUser(t)
+	d := RandomTextDictation(t, user)
+	require.False(t, d.AudioStatus.Valid)
+
+	queued, err := testQueries.QueueDictationAudio(context.Background(), d.ID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", queued.AudioStatus.String)
+	require.Zero(t, queued.AudioAttempts)
+
+	pending, err := testQueries.ListDictationsByAudioStatus(context.Background(), sql.NullString{String: "pending", Valid: true})
+	require.NoError(t, err)
+	require.Contains(t, pending, queued)
+
+	failed, err := testQueries.FailDictationAudio(context.Background(), FailDictationAudioParams{
+		ID:          d.ID,
+		AudioStatus: sql.NullString{String: "pending", Valid: true},
+		AudioError:  sql.NullString{String: "provider unavailable", Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "provider unavailable", failed.AudioError.String)
+	require.Equal(t, int32(1), failed.AudioAttempts)
+
+	// Audio for text that has since changed is not recorded
+	_, err = testQueries.CompleteDictationAudio(context.Background(), CompleteDictationAudioParams{
+		ID:      d.ID,
+		Content: sql.NullString{String: "older text", Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	ready, err := testQueries.CompleteDictationAudio(context.Background(), CompleteDictationAudioParams{
+		ID:              d.ID,
+		Content:         d.Content,
+		AudioUrl:        sql.NullString{String: "/dictations/1/audio", Valid: true},
+		AudioKey:        sql.NullString{String: "dictations/1/speech.mp3", Valid: true},
+		AudioDurationMs: sql.NullInt64{Int64: 1500, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "ready", ready.AudioStatus.String)
+	require.False(t, ready.AudioError.Valid)
+	require.Equal(t, int64(1500), ready.AudioDurationMs.Int64)
+	require.Equal(t, int32(2), ready.AudioAttempts)
+}
+
+func TestSetDictationTranscript(t *testing.T) {
+	user := RandomUser(t)
+	d := RandomAudioDictation(t, user)
+	require.False(t, d.Content.Valid)
+
+	segments := json.RawMessage(`[{"start_ms": 0, "end_ms": 1200, "text": "Dear Sir,"}]`)
+	updated, err := testQueries.SetDictationTranscript(context.Background(), SetDictationTranscriptParams{
+		ID:                 d.ID,
+		UserID:             d.UserID,
+		Content:            sql.NullString{String: "Dear Sir,", Valid: true},
+		TranscriptSegments: pqtype.NullRawMessage{RawMessage: segments, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Dear Sir,", updated.Content.String)
+	require.JSONEq(t, string(segments), string(updated.TranscriptSegments.RawMessage))
+
+	// Text dictations keep their content
+	text := RandomTextDictation(t, user)
+	_, err = testQueries.SetDictationTranscript(context.Background(), SetDictationTranscriptParams{
+		ID:      text.ID,
+		UserID:  text.UserID,
+		Content: sql.NullString{String: "Dear Sir,", Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+func TestSetDictationLexicon(t *testing.T) {
+	user := RandomUser(t)
+	d := RandomTextDictation(t, user)
+
+	lexicon := json.RawMessage(`[{"written": "Nguyen", "spoken": "Win"}]`)
+	updated, err := testQueries.SetDictationLexicon(context.Background(), SetDictationLexiconParams{
+		ID:      d.ID,
+		UserID:  d.UserID,
+		Lexicon: pqtype.NullRawMessage{RawMessage: lexicon, Valid: true},
+	})
+	require.NoError(t, err)
+	require.JSONEq(t, string(lexicon), string(updated.Lexicon.RawMessage))
+
+	// Audio made with the old lexicon is stale
+	_, err = testQueries.CompleteDictationAudio(context.Background(), CompleteDictationAudioParams{
+		ID:      d.ID,
+		Content: d.Content,
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+
+	_, err = testQueries.CompleteDictationAudio(context.Background(), CompleteDictationAudioParams{
+		ID:       d.ID,
+		Content:  d.Content,
+		Lexicon:  updated.Lexicon,
+		AudioKey: sql.NullString{String: "dictations/1/speech.mp3", Valid: true},
+	})
+	require.NoError(t, err)
+
+	// Only the owner can change it
+	_, err = testQueries.SetDictationLexicon(context.Background(), SetDictationLexiconParams{
+		ID:     d.ID,
+		UserID: sql.NullInt64{Int64: d.UserID.Int64 + 1, Valid: true},
+	})
+	require.ErrorIs(t, err, sql.ErrNoRows)
+}
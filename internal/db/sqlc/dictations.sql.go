@@ -9,8 +9,66 @@ import (
 	"context"
 	"database/sql"
 	"time"
+
+	"github.com/sqlc-dev/pqtype"
 )
 
+const completeDictationAudio = `-- name: CompleteDictationAudio :one
+UPDATE dictations
+SET
+    audio_url = $1,
+    audio_key = $2,
+    audio_duration_ms = $3,
+    audio_status = 'ready',
+    audio_error = NULL,
+    audio_attempts = audio_attempts + 1
+WHERE id = $4
+  AND content = $5
+  AND lexicon IS NOT DISTINCT FROM $6
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
+`
+
+type CompleteDictationAudioParams struct {
+	AudioUrl        sql.NullString        `json:"audio_url"`
+	AudioKey        sql.NullString        `json:"audio_key"`
+	AudioDurationMs sql.NullInt64         `json:"audio_duration_ms"`
+	ID              int64                 `json:"id"`
+	Content         sql.NullString        `json:"content"`
+	Lexicon         pqtype.NullRawMessage `json:"lexicon"`
+}
+
+func (q *Queries) CompleteDictationAudio(ctx context.Context, arg CompleteDictationAudioParams) (Dictation, error) {
+	row := q.db.QueryRowContext(ctx, completeDictationAudio,
+		arg.AudioUrl,
+		arg.AudioKey,
+		arg.AudioDurationMs,
+		arg.ID,
+		arg.Content,
+		arg.Lexicon,
+	)
+	var i Dictation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Type,
+		&i.Content,
+		&i.AudioUrl,
+		&i.Language,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
+	)
+	return i, err
+}
+
 const createAudioDictations = `-- name: CreateAudioDictations :one
 INSERT INTO dictations (
   user_id,
@@ -19,20 +77,32 @@ INSERT INTO dictations (
   audio_url,
   language,
   created_at,
-  updated_at
+  updated_at,
+  scoring_options,
+  audio_key,
+  audio_duration_ms,
+  content,
+  transcript_segments,
+  lexicon
 ) VALUES (
-  $1, $2, 'audio', $3, $4, $5, $6
+  $1, $2, 'audio', $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
 )
-RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
 `
 
 type CreateAudioDictationsParams struct {
-	UserID    sql.NullInt64  `json:"user_id"`
-	Title     sql.NullString `json:"title"`
-	AudioUrl  sql.NullString `json:"audio_url"`
-	Language  sql.NullString `json:"language"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	UserID             sql.NullInt64         `json:"user_id"`
+	Title              sql.NullString        `json:"title"`
+	AudioUrl           sql.NullString        `json:"audio_url"`
+	Language           sql.NullString        `json:"language"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+	ScoringOptions     pqtype.NullRawMessage `json:"scoring_options"`
+	AudioKey           sql.NullString        `json:"audio_key"`
+	AudioDurationMs    sql.NullInt64         `json:"audio_duration_ms"`
+	Content            sql.NullString        `json:"content"`
+	TranscriptSegments pqtype.NullRawMessage `json:"transcript_segments"`
+	Lexicon            pqtype.NullRawMessage `json:"lexicon"`
 }
 
 func (q *Queries) CreateAudioDictations(ctx context.Context, arg CreateAudioDictationsParams) (Dictation, error) {
@@ -43,6 +113,12 @@ func (q *Queries) CreateAudioDictations(ctx context.Context, arg CreateAudioDict
 		arg.Language,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.ScoringOptions,
+		arg.AudioKey,
+		arg.AudioDurationMs,
+		arg.Content,
+		arg.TranscriptSegments,
+		arg.Lexicon,
 	)
 	var i Dictation
 	err := row.Scan(
@@ -55,6 +131,14 @@ func (q *Queries) CreateAudioDictations(ctx context.Context, arg CreateAudioDict
 		&i.Language,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
 	)
 	return i, err
 }
@@ -67,21 +151,26 @@ INSERT INTO dictations (
   content, 
   language,
   created_at, 
-  updated_at
-
+  updated_at,
+  scoring_options,
+  audio_status,
+  lexicon
 ) VALUES (
-  $1, $2, 'text', $3, $4, $5, $6
+  $1, $2, 'text', $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
 `
 
 type CreateTextDictationsParams struct {
-	UserID    sql.NullInt64  `json:"user_id"`
-	Title     sql.NullString `json:"title"`
-	Content   sql.NullString `json:"content"`
-	Language  sql.NullString `json:"language"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	UserID         sql.NullInt64         `json:"user_id"`
+	Title          sql.NullString        `json:"title"`
+	Content        sql.NullString        `json:"content"`
+	Language       sql.NullString        `json:"language"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+	ScoringOptions pqtype.NullRawMessage `json:"scoring_options"`
+	AudioStatus    sql.NullString        `json:"audio_status"`
+	Lexicon        pqtype.NullRawMessage `json:"lexicon"`
 }
 
 func (q *Queries) CreateTextDictations(ctx context.Context, arg CreateTextDictationsParams) (Dictation, error) {
@@ -92,6 +181,9 @@ func (q *Queries) CreateTextDictations(ctx context.Context, arg CreateTextDictat
 		arg.Language,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.ScoringOptions,
+		arg.AudioStatus,
+		arg.Lexicon,
 	)
 	var i Dictation
 	err := row.Scan(
@@ -104,6 +196,14 @@ func (q *Queries) CreateTextDictations(ctx context.Context, arg CreateTextDictat
 		&i.Language,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
 	)
 	return i, err
 }
@@ -118,8 +218,49 @@ func (q *Queries) DeleteDictations(ctx context.Context, title sql.NullString) er
 	return err
 }
 
+const failDictationAudio = `-- name: FailDictationAudio :one
+UPDATE dictations
+SET
+    audio_status = $1,
+    audio_error = $2,
+    audio_attempts = audio_attempts + 1
+WHERE id = $3
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
+`
+
+type FailDictationAudioParams struct {
+	AudioStatus sql.NullString `json:"audio_status"`
+	AudioError  sql.NullString `json:"audio_error"`
+	ID          int64          `json:"id"`
+}
+
+func (q *Queries) FailDictationAudio(ctx context.Context, arg FailDictationAudioParams) (Dictation, error) {
+	row := q.db.QueryRowContext(ctx, failDictationAudio, arg.AudioStatus, arg.AudioError, arg.ID)
+	var i Dictation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Type,
+		&i.Content,
+		&i.AudioUrl,
+		&i.Language,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
+	)
+	return i, err
+}
+
 const getDictation = `-- name: GetDictation :one
-SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at FROM dictations
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
 WHERE id = $1 LIMIT 1
 `
 
@@ -136,12 +277,20 @@ func (q *Queries) GetDictation(ctx context.Context, id int64) (Dictation, error)
 		&i.Language,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
 	)
 	return i, err
 }
 
 const getDictationsByTitle = `-- name: GetDictationsByTitle :one
-SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at FROM dictations
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
 WHERE title = $1 LIMIT 1
 `
 
@@ -158,12 +307,20 @@ func (q *Queries) GetDictationsByTitle(ctx context.Context, title sql.NullString
 		&i.Language,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
 	)
 	return i, err
 }
 
 const listAudioDictations = `-- name: ListAudioDictations :many
-SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at FROM dictations
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
 WHERE user_id = $1
     AND type = 'audio'
 ORDER BY created_at DESC
@@ -188,6 +345,61 @@ func (q *Queries) ListAudioDictations(ctx context.Context, userID sql.NullInt64)
 			&i.Language,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScoringOptions,
+			&i.AudioKey,
+			&i.AudioDurationMs,
+			&i.AudioStatus,
+			&i.AudioError,
+			&i.AudioAttempts,
+			&i.TranscriptSegments,
+			&i.Lexicon,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDictationsByAudioStatus = `-- name: ListDictationsByAudioStatus :many
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
+WHERE audio_status = $1
+ORDER BY id
+`
+
+func (q *Queries) ListDictationsByAudioStatus(ctx context.Context, audioStatus sql.NullString) ([]Dictation, error) {
+	rows, err := q.db.QueryContext(ctx, listDictationsByAudioStatus, audioStatus)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Dictation
+	for rows.Next() {
+		var i Dictation
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Title,
+			&i.Type,
+			&i.Content,
+			&i.AudioUrl,
+			&i.Language,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.ScoringOptions,
+			&i.AudioKey,
+			&i.AudioDurationMs,
+			&i.AudioStatus,
+			&i.AudioError,
+			&i.AudioAttempts,
+			&i.TranscriptSegments,
+			&i.Lexicon,
 		); err != nil {
 			return nil, err
 		}
@@ -203,7 +415,7 @@ func (q *Queries) ListAudioDictations(ctx context.Context, userID sql.NullInt64)
 }
 
 const listDictationsByUser = `-- name: ListDictationsByUser :many
-SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at FROM dictations
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
 WHERE user_id = $1
 ORDER BY created_at DESC
 `
@@ -227,6 +439,14 @@ func (q *Queries) ListDictationsByUser(ctx context.Context, userID sql.NullInt64
 			&i.Language,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScoringOptions,
+			&i.AudioKey,
+			&i.AudioDurationMs,
+			&i.AudioStatus,
+			&i.AudioError,
+			&i.AudioAttempts,
+			&i.TranscriptSegments,
+			&i.Lexicon,
 		); err != nil {
 			return nil, err
 		}
@@ -242,7 +462,7 @@ func (q *Queries) ListDictationsByUser(ctx context.Context, userID sql.NullInt64
 }
 
 const listTextDictations = `-- name: ListTextDictations :many
-SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at FROM dictations
+SELECT id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon FROM dictations
 WHERE user_id = $1
     AND type = 'text'
 ORDER BY created_at DESC
@@ -267,6 +487,14 @@ func (q *Queries) ListTextDictations(ctx context.Context, userID sql.NullInt64)
 			&i.Language,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.ScoringOptions,
+			&i.AudioKey,
+			&i.AudioDurationMs,
+			&i.AudioStatus,
+			&i.AudioError,
+			&i.AudioAttempts,
+			&i.TranscriptSegments,
+			&i.Lexicon,
 		); err != nil {
 			return nil, err
 		}
@@ -281,6 +509,131 @@ func (q *Queries) ListTextDictations(ctx context.Context, userID sql.NullInt64)
 	return items, nil
 }
 
+const queueDictationAudio = `-- name: QueueDictationAudio :one
+UPDATE dictations
+SET
+    audio_status = 'pending',
+    audio_error = NULL,
+    audio_attempts = 0
+WHERE id = $1
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
+`
+
+func (q *Queries) QueueDictationAudio(ctx context.Context, id int64) (Dictation, error) {
+	row := q.db.QueryRowContext(ctx, queueDictationAudio, id)
+	var i Dictation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Type,
+		&i.Content,
+		&i.AudioUrl,
+		&i.Language,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
+	)
+	return i, err
+}
+
+const setDictationLexicon = `-- name: SetDictationLexicon :one
+UPDATE dictations
+SET
+    lexicon = $1,
+    updated_at = NOW()
+WHERE id = $2
+  AND user_id = $3
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
+`
+
+type SetDictationLexiconParams struct {
+	Lexicon pqtype.NullRawMessage `json:"lexicon"`
+	ID      int64                 `json:"id"`
+	UserID  sql.NullInt64         `json:"user_id"`
+}
+
+func (q *Queries) SetDictationLexicon(ctx context.Context, arg SetDictationLexiconParams) (Dictation, error) {
+	row := q.db.QueryRowContext(ctx, setDictationLexicon, arg.Lexicon, arg.ID, arg.UserID)
+	var i Dictation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Type,
+		&i.Content,
+		&i.AudioUrl,
+		&i.Language,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
+	)
+	return i, err
+}
+
+const setDictationTranscript = `-- name: SetDictationTranscript :one
+UPDATE dictations
+SET
+    content = $1,
+    transcript_segments = $2,
+    updated_at = NOW()
+WHERE id = $3
+  AND user_id = $4
+  AND type = 'audio'
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
+`
+
+type SetDictationTranscriptParams struct {
+	Content            sql.NullString        `json:"content"`
+	TranscriptSegments pqtype.NullRawMessage `json:"transcript_segments"`
+	ID                 int64                 `json:"id"`
+	UserID             sql.NullInt64         `json:"user_id"`
+}
+
+func (q *Queries) SetDictationTranscript(ctx context.Context, arg SetDictationTranscriptParams) (Dictation, error) {
+	row := q.db.QueryRowContext(ctx, setDictationTranscript,
+		arg.Content,
+		arg.TranscriptSegments,
+		arg.ID,
+		arg.UserID,
+	)
+	var i Dictation
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Title,
+		&i.Type,
+		&i.Content,
+		&i.AudioUrl,
+		&i.Language,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
+	)
+	return i, err
+}
+
 const updateDictation = `-- name: UpdateDictation :one
 UPDATE dictations
 SET
@@ -290,7 +643,7 @@ SET
     updated_at = NOW()
 WHERE id = $4
   AND user_id = $5
-RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at
+RETURNING id, user_id, title, type, content, audio_url, language, created_at, updated_at, scoring_options, audio_key, audio_duration_ms, audio_status, audio_error, audio_attempts, transcript_segments, lexicon
 `
 
 type UpdateDictationParams struct {
@@ -320,6 +673,14 @@ func (q *Queries) UpdateDictation(ctx context.Context, arg UpdateDictationParams
 		&i.Language,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ScoringOptions,
+		&i.AudioKey,
+		&i.AudioDurationMs,
+		&i.AudioStatus,
+		&i.AudioError,
+		&i.AudioAttempts,
+		&i.TranscriptSegments,
+		&i.Lexicon,
 	)
 	return i, err
 }
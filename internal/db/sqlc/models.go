@@ -8,6 +8,7 @@ import (
 	"database/sql"
 	"time"
 
+	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
@@ -26,41 +27,85 @@ type Attempt struct {
 	ComparisonData    pqtype.NullRawMessage `json:"comparison_data"`
 	TimeSpent         sql.NullFloat64       `json:"time_spent"`
 	CreatedAt         sql.NullTime          `json:"created_at"`
+	PunctuationErrors sql.NullInt32         `json:"punctuation_errors"`
+	OmissionErrors    sql.NullInt32         `json:"omission_errors"`
+	InsertionErrors   sql.NullInt32         `json:"insertion_errors"`
+	WrongWordErrors   sql.NullInt32         `json:"wrong_word_errors"`
+	MarkSheet         pqtype.NullRawMessage `json:"mark_sheet"`
+	ScoringVersion    sql.NullString        `json:"scoring_version"`
+	GrossWpm          sql.NullFloat64       `json:"gross_wpm"`
+	NetWpm            sql.NullFloat64       `json:"net_wpm"`
+	Cpm               sql.NullFloat64       `json:"cpm"`
+	CharAccuracy      sql.NullFloat64       `json:"char_accuracy"`
+	Keystrokes        sql.NullInt32         `json:"keystrokes"`
+	Kspc              sql.NullFloat64       `json:"kspc"`
+	SessionID         uuid.NullUUID         `json:"session_id"`
+	ClientTimeSpent   sql.NullFloat64       `json:"client_time_spent"`
+	TimeFlagged       bool                  `json:"time_flagged"`
+	InputEncoding     string                `json:"input_encoding"`
+	WeightedAccuracy  sql.NullFloat64       `json:"weighted_accuracy"`
+	ParagraphErrors   sql.NullInt32         `json:"paragraph_errors"`
+	Segment           sql.NullInt32         `json:"segment"`
+}
+
+type AttemptSession struct {
+	ID          uuid.UUID     `json:"id"`
+	UserID      int64         `json:"user_id"`
+	DictationID int64         `json:"dictation_id"`
+	StartedAt   time.Time     `json:"started_at"`
+	UsedAt      sql.NullTime  `json:"used_at"`
+	Segment     sql.NullInt32 `json:"segment"`
 }
 
 type Dictation struct {
-	ID        int64          `json:"id"`
-	UserID    sql.NullInt64  `json:"user_id"`
-	Title     sql.NullString `json:"title"`
-	Type      sql.NullString `json:"type"`
-	Content   sql.NullString `json:"content"`
-	AudioUrl  sql.NullString `json:"audio_url"`
-	Language  sql.NullString `json:"language"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
+	ID                 int64                 `json:"id"`
+	UserID             sql.NullInt64         `json:"user_id"`
+	Title              sql.NullString        `json:"title"`
+	Type               sql.NullString        `json:"type"`
+	Content            sql.NullString        `json:"content"`
+	AudioUrl           sql.NullString        `json:"audio_url"`
+	Language           sql.NullString        `json:"language"`
+	CreatedAt          time.Time             `json:"created_at"`
+	UpdatedAt          time.Time             `json:"updated_at"`
+	ScoringOptions     pqtype.NullRawMessage `json:"scoring_options"`
+	AudioKey           sql.NullString        `json:"audio_key"`
+	AudioDurationMs    sql.NullInt64         `json:"audio_duration_ms"`
+	AudioStatus        sql.NullString        `json:"audio_status"`
+	AudioError         sql.NullString        `json:"audio_error"`
+	AudioAttempts      int32                 `json:"audio_attempts"`
+	TranscriptSegments pqtype.NullRawMessage `json:"transcript_segments"`
+	Lexicon            pqtype.NullRawMessage `json:"lexicon"`
 }
 
 type PerformanceSummary struct {
-	ID              int64           `json:"id"`
-	UserID          sql.NullInt64   `json:"user_id"`
-	DictationID     sql.NullInt64   `json:"dictation_id"`
-	TotalAttempts   sql.NullInt32   `json:"total_attempts"`
-	BestAccuracy    sql.NullFloat64 `json:"best_accuracy"`
-	AverageAccuracy sql.NullFloat64 `json:"average_accuracy"`
-	AverageTime     sql.NullFloat64 `json:"average_time"`
-	LastAttemptAt   sql.NullTime    `json:"last_attempt_at"`
+	ID                      int64           `json:"id"`
+	UserID                  sql.NullInt64   `json:"user_id"`
+	DictationID             sql.NullInt64   `json:"dictation_id"`
+	TotalAttempts           sql.NullInt32   `json:"total_attempts"`
+	BestAccuracy            sql.NullFloat64 `json:"best_accuracy"`
+	AverageAccuracy         sql.NullFloat64 `json:"average_accuracy"`
+	AverageTime             sql.NullFloat64 `json:"average_time"`
+	LastAttemptAt           sql.NullTime    `json:"last_attempt_at"`
+	RollingGrossWpm         sql.NullFloat64 `json:"rolling_gross_wpm"`
+	RollingNetWpm           sql.NullFloat64 `json:"rolling_net_wpm"`
+	RollingCpm              sql.NullFloat64 `json:"rolling_cpm"`
+	RollingCharAccuracy     sql.NullFloat64 `json:"rolling_char_accuracy"`
+	BestWeightedAccuracy    sql.NullFloat64 `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy sql.NullFloat64 `json:"average_weighted_accuracy"`
 }
 
 type Setting struct {
-	ID                     int64           `json:"id"`
-	UserID                 sql.NullInt64   `json:"user_id"`
-	DefaultVoice           sql.NullString  `json:"default_voice"`
-	DefaultSpeed           sql.NullFloat64 `json:"default_speed"`
-	HighlightColorGrammar  sql.NullString  `json:"highlight_color_grammar"`
-	HighlightColorSpelling sql.NullString  `json:"highlight_color_spelling"`
-	HighlightColorCase     sql.NullString  `json:"highlight_color_case"`
-	CreatedAt              sql.NullTime    `json:"created_at"`
-	UpdatedAt              sql.NullTime    `json:"updated_at"`
+	ID                     int64                 `json:"id"`
+	UserID                 sql.NullInt64         `json:"user_id"`
+	DefaultVoice           sql.NullString        `json:"default_voice"`
+	DefaultSpeed           sql.NullFloat64       `json:"default_speed"`
+	HighlightColorGrammar  sql.NullString        `json:"highlight_color_grammar"`
+	HighlightColorSpelling sql.NullString        `json:"highlight_color_spelling"`
+	HighlightColorCase     sql.NullString        `json:"highlight_color_case"`
+	CreatedAt              sql.NullTime          `json:"created_at"`
+	UpdatedAt              sql.NullTime          `json:"updated_at"`
+	Tolerance              pqtype.NullRawMessage `json:"tolerance"`
+	Lexicon                pqtype.NullRawMessage `json:"lexicon"`
 }
 
 type User struct {
@@ -12,21 +12,29 @@ import (
 
 const createPerformanceSummary = `-- name: CreatePerformanceSummary :one
 INSERT INTO performance_summary (
-    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at,
+    rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy,
+    best_weighted_accuracy, average_weighted_accuracy
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, $7
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13
 )
-RETURNING id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+RETURNING id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 `
 
 type CreatePerformanceSummaryParams struct {
-	UserID          sql.NullInt64   `json:"user_id"`
-	DictationID     sql.NullInt64   `json:"dictation_id"`
-	TotalAttempts   sql.NullInt32   `json:"total_attempts"`
-	BestAccuracy    sql.NullFloat64 `json:"best_accuracy"`
-	AverageAccuracy sql.NullFloat64 `json:"average_accuracy"`
-	AverageTime     sql.NullFloat64 `json:"average_time"`
-	LastAttemptAt   sql.NullTime    `json:"last_attempt_at"`
+	UserID                  sql.NullInt64   `json:"user_id"`
+	DictationID             sql.NullInt64   `json:"dictation_id"`
+	TotalAttempts           sql.NullInt32   `json:"total_attempts"`
+	BestAccuracy            sql.NullFloat64 `json:"best_accuracy"`
+	AverageAccuracy         sql.NullFloat64 `json:"average_accuracy"`
+	AverageTime             sql.NullFloat64 `json:"average_time"`
+	LastAttemptAt           sql.NullTime    `json:"last_attempt_at"`
+	RollingGrossWpm         sql.NullFloat64 `json:"rolling_gross_wpm"`
+	RollingNetWpm           sql.NullFloat64 `json:"rolling_net_wpm"`
+	RollingCpm              sql.NullFloat64 `json:"rolling_cpm"`
+	RollingCharAccuracy     sql.NullFloat64 `json:"rolling_char_accuracy"`
+	BestWeightedAccuracy    sql.NullFloat64 `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy sql.NullFloat64 `json:"average_weighted_accuracy"`
 }
 
 func (q *Queries) CreatePerformanceSummary(ctx context.Context, arg CreatePerformanceSummaryParams) (PerformanceSummary, error) {
@@ -38,6 +46,12 @@ func (q *Queries) CreatePerformanceSummary(ctx context.Context, arg CreatePerfor
 		arg.AverageAccuracy,
 		arg.AverageTime,
 		arg.LastAttemptAt,
+		arg.RollingGrossWpm,
+		arg.RollingNetWpm,
+		arg.RollingCpm,
+		arg.RollingCharAccuracy,
+		arg.BestWeightedAccuracy,
+		arg.AverageWeightedAccuracy,
 	)
 	var i PerformanceSummary
 	err := row.Scan(
@@ -49,6 +63,12 @@ func (q *Queries) CreatePerformanceSummary(ctx context.Context, arg CreatePerfor
 		&i.AverageAccuracy,
 		&i.AverageTime,
 		&i.LastAttemptAt,
+		&i.RollingGrossWpm,
+		&i.RollingNetWpm,
+		&i.RollingCpm,
+		&i.RollingCharAccuracy,
+		&i.BestWeightedAccuracy,
+		&i.AverageWeightedAccuracy,
 	)
 	return i, err
 }
@@ -63,8 +83,18 @@ func (q *Queries) DeletePerformanceSummary(ctx context.Context, id int64) error
 	return err
 }
 
+const deleteUserPerformanceSummaries = `-- name: DeleteUserPerformanceSummaries :exec
+DELETE FROM performance_summary
+WHERE user_id IS NOT DISTINCT FROM $1::bigint
+`
+
+func (q *Queries) DeleteUserPerformanceSummaries(ctx context.Context, userID sql.NullInt64) error {
+	_, err := q.db.ExecContext(ctx, deleteUserPerformanceSummaries, userID)
+	return err
+}
+
 const getPerformanceSummaryByID = `-- name: GetPerformanceSummaryByID :one
-SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 FROM performance_summary
 WHERE id = $1
 `
@@ -81,12 +111,18 @@ func (q *Queries) GetPerformanceSummaryByID(ctx context.Context, id int64) (Perf
 		&i.AverageAccuracy,
 		&i.AverageTime,
 		&i.LastAttemptAt,
+		&i.RollingGrossWpm,
+		&i.RollingNetWpm,
+		&i.RollingCpm,
+		&i.RollingCharAccuracy,
+		&i.BestWeightedAccuracy,
+		&i.AverageWeightedAccuracy,
 	)
 	return i, err
 }
 
 const getPerformanceSummaryByUserAndDictation = `-- name: GetPerformanceSummaryByUserAndDictation :one
-SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 FROM performance_summary
 WHERE user_id = $1 AND dictation_id = $2
 `
@@ -108,12 +144,18 @@ func (q *Queries) GetPerformanceSummaryByUserAndDictation(ctx context.Context, a
 		&i.AverageAccuracy,
 		&i.AverageTime,
 		&i.LastAttemptAt,
+		&i.RollingGrossWpm,
+		&i.RollingNetWpm,
+		&i.RollingCpm,
+		&i.RollingCharAccuracy,
+		&i.BestWeightedAccuracy,
+		&i.AverageWeightedAccuracy,
 	)
 	return i, err
 }
 
 const listPerformanceSummaryByUser = `-- name: ListPerformanceSummaryByUser :many
-SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 FROM performance_summary
 WHERE user_id = $1
 ORDER BY last_attempt_at DESC
@@ -137,6 +179,12 @@ func (q *Queries) ListPerformanceSummaryByUser(ctx context.Context, userID sql.N
 			&i.AverageAccuracy,
 			&i.AverageTime,
 			&i.LastAttemptAt,
+			&i.RollingGrossWpm,
+			&i.RollingNetWpm,
+			&i.RollingCpm,
+			&i.RollingCharAccuracy,
+			&i.BestWeightedAccuracy,
+			&i.AverageWeightedAccuracy,
 		); err != nil {
 			return nil, err
 		}
@@ -151,8 +199,50 @@ func (q *Queries) ListPerformanceSummaryByUser(ctx context.Context, userID sql.N
 	return items, nil
 }
 
+const rebuildUserPerformanceSummaries = `-- name: RebuildUserPerformanceSummaries :exec
+INSERT INTO performance_summary (
+    user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at,
+    rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy,
+    best_weighted_accuracy, average_weighted_accuracy
+)
+SELECT
+    user_id,
+    dictation_id,
+    COUNT(*),
+    MAX(accuracy),
+    AVG(accuracy),
+    AVG(time_spent),
+    MAX(created_at),
+    AVG(gross_wpm) FILTER (WHERE recency <= $1::int),
+    AVG(net_wpm) FILTER (WHERE recency <= $1::int),
+    AVG(cpm) FILTER (WHERE recency <= $1::int),
+    AVG(char_accuracy) FILTER (WHERE recency <= $1::int),
+    MAX(weighted_accuracy),
+    AVG(weighted_accuracy)
+FROM (
+    SELECT
+        id, user_id, dictation_id, typed_text, attempt_no, total_words, correct_words, grammatical_errors, spelling_errors, case_errors, accuracy, comparison_data, time_spent, created_at, punctuation_errors, omission_errors, insertion_errors, wrong_word_errors, mark_sheet, scoring_version, gross_wpm, net_wpm, cpm, char_accuracy, keystrokes, kspc, session_id, client_time_spent, time_flagged, input_encoding, weighted_accuracy, paragraph_errors, segment,
+        ROW_NUMBER() OVER (PARTITION BY user_id, dictation_id ORDER BY created_at DESC, id DESC) AS recency
+    FROM attempts
+    -- Segment practice is kept out of the dictation's summary
+    WHERE segment IS NULL
+      AND user_id IS NOT DISTINCT FROM $2::bigint
+) AS ranked
+GROUP BY user_id, dictation_id
+`
+
+type RebuildUserPerformanceSummariesParams struct {
+	RollingWindow int32         `json:"rolling_window"`
+	UserID        sql.NullInt64 `json:"user_id"`
+}
+
+func (q *Queries) RebuildUserPerformanceSummaries(ctx context.Context, arg RebuildUserPerformanceSummariesParams) error {
+	_, err := q.db.ExecContext(ctx, rebuildUserPerformanceSummaries, arg.RollingWindow, arg.UserID)
+	return err
+}
+
 const recentAttemptsByUser = `-- name: RecentAttemptsByUser :many
-SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+SELECT id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 FROM performance_summary
 WHERE user_id = $1
 ORDER BY last_attempt_at DESC
@@ -182,6 +272,12 @@ func (q *Queries) RecentAttemptsByUser(ctx context.Context, arg RecentAttemptsBy
 			&i.AverageAccuracy,
 			&i.AverageTime,
 			&i.LastAttemptAt,
+			&i.RollingGrossWpm,
+			&i.RollingNetWpm,
+			&i.RollingCpm,
+			&i.RollingCharAccuracy,
+			&i.BestWeightedAccuracy,
+			&i.AverageWeightedAccuracy,
 		); err != nil {
 			return nil, err
 		}
@@ -203,18 +299,30 @@ SET
     best_accuracy = $2,
     average_accuracy = $3,
     average_time = $4,
-    last_attempt_at = $5
+    last_attempt_at = $5,
+    rolling_gross_wpm = $7,
+    rolling_net_wpm = $8,
+    rolling_cpm = $9,
+    rolling_char_accuracy = $10,
+    best_weighted_accuracy = $11,
+    average_weighted_accuracy = $12
 WHERE id = $6
-RETURNING id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at
+RETURNING id, user_id, dictation_id, total_attempts, best_accuracy, average_accuracy, average_time, last_attempt_at, rolling_gross_wpm, rolling_net_wpm, rolling_cpm, rolling_char_accuracy, best_weighted_accuracy, average_weighted_accuracy
 `
 
 type UpdatePerformanceSummaryParams struct {
-	TotalAttempts   sql.NullInt32   `json:"total_attempts"`
-	BestAccuracy    sql.NullFloat64 `json:"best_accuracy"`
-	AverageAccuracy sql.NullFloat64 `json:"average_accuracy"`
-	AverageTime     sql.NullFloat64 `json:"average_time"`
-	LastAttemptAt   sql.NullTime    `json:"last_attempt_at"`
-	ID              int64           `json:"id"`
+	TotalAttempts           sql.NullInt32   `json:"total_attempts"`
+	BestAccuracy            sql.NullFloat64 `json:"best_accuracy"`
+	AverageAccuracy         sql.NullFloat64 `json:"average_accuracy"`
+	AverageTime             sql.NullFloat64 `json:"average_time"`
+	LastAttemptAt           sql.NullTime    `json:"last_attempt_at"`
+	ID                      int64           `json:"id"`
+	RollingGrossWpm         sql.NullFloat64 `json:"rolling_gross_wpm"`
+	RollingNetWpm           sql.NullFloat64 `json:"rolling_net_wpm"`
+	RollingCpm              sql.NullFloat64 `json:"rolling_cpm"`
+	RollingCharAccuracy     sql.NullFloat64 `json:"rolling_char_accuracy"`
+	BestWeightedAccuracy    sql.NullFloat64 `json:"best_weighted_accuracy"`
+	AverageWeightedAccuracy sql.NullFloat64 `json:"average_weighted_accuracy"`
 }
 
 func (q *Queries) UpdatePerformanceSummary(ctx context.Context, arg UpdatePerformanceSummaryParams) (PerformanceSummary, error) {
@@ -225,6 +333,12 @@ func (q *Queries) UpdatePerformanceSummary(ctx context.Context, arg UpdatePerfor
 		arg.AverageTime,
 		arg.LastAttemptAt,
 		arg.ID,
+		arg.RollingGrossWpm,
+		arg.RollingNetWpm,
+		arg.RollingCpm,
+		arg.RollingCharAccuracy,
+		arg.BestWeightedAccuracy,
+		arg.AverageWeightedAccuracy,
 	)
 	var i PerformanceSummary
 	err := row.Scan(
@@ -236,6 +350,12 @@ func (q *Queries) UpdatePerformanceSummary(ctx context.Context, arg UpdatePerfor
 		&i.AverageAccuracy,
 		&i.AverageTime,
 		&i.LastAttemptAt,
+		&i.RollingGrossWpm,
+		&i.RollingNetWpm,
+		&i.RollingCpm,
+		&i.RollingCharAccuracy,
+		&i.BestWeightedAccuracy,
+		&i.AverageWeightedAccuracy,
 	)
 	return i, err
 }
@@ -7,10 +7,14 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/google/uuid"
 )
 
 type Querier interface {
+	CompleteDictationAudio(ctx context.Context, arg CompleteDictationAudioParams) (Dictation, error)
 	CountAttemptsByDictation(ctx context.Context, arg CountAttemptsByDictationParams) (int64, error)
+	CreateAttemptSession(ctx context.Context, arg CreateAttemptSessionParams) (AttemptSession, error)
 	CreateAttempts(ctx context.Context, arg CreateAttemptsParams) (Attempt, error)
 	CreateAudioDictations(ctx context.Context, arg CreateAudioDictationsParams) (Dictation, error)
 	CreatePerformanceSummary(ctx context.Context, arg CreatePerformanceSummaryParams) (PerformanceSummary, error)
@@ -22,29 +26,46 @@ type Querier interface {
 	DeleteDictations(ctx context.Context, title sql.NullString) error
 	DeletePerformanceSummary(ctx context.Context, id int64) error
 	DeleteSetting(ctx context.Context, id int64) error
+	DeleteUserPerformanceSummaries(ctx context.Context, userID sql.NullInt64) error
 	DeleteUsers(ctx context.Context, username string) error
+	FailDictationAudio(ctx context.Context, arg FailDictationAudioParams) (Dictation, error)
 	GetAttemptById(ctx context.Context, id int64) (Attempt, error)
+	GetAttemptSession(ctx context.Context, id uuid.UUID) (AttemptSession, error)
 	GetDictation(ctx context.Context, id int64) (Dictation, error)
 	GetDictationsByTitle(ctx context.Context, title sql.NullString) (Dictation, error)
 	GetLatestAttempt(ctx context.Context, arg GetLatestAttemptParams) (Attempt, error)
 	GetPerformanceSummaryByID(ctx context.Context, id int64) (PerformanceSummary, error)
 	GetPerformanceSummaryByUserAndDictation(ctx context.Context, arg GetPerformanceSummaryByUserAndDictationParams) (PerformanceSummary, error)
+	GetRollingSpeed(ctx context.Context, arg GetRollingSpeedParams) (GetRollingSpeedRow, error)
 	GetSettingByID(ctx context.Context, id int64) (Setting, error)
 	GetSettingByUserID(ctx context.Context, userID sql.NullInt64) (Setting, error)
+	// Returns the session if it has not been used yet, with the elapsed time
+	// measured like UseAttemptSession does.
+	GetUnusedAttemptSession(ctx context.Context, id uuid.UUID) (GetUnusedAttemptSessionRow, error)
 	GetUsers(ctx context.Context, username string) (User, error)
+	GetWeightedAccuracyStats(ctx context.Context, arg GetWeightedAccuracyStatsParams) (GetWeightedAccuracyStatsRow, error)
 	ListAttemptsByDictation(ctx context.Context, dictationID sql.NullInt64) ([]Attempt, error)
 	ListAttemptsByUser(ctx context.Context, userID sql.NullInt64) ([]Attempt, error)
+	ListAttemptsToRescore(ctx context.Context, arg ListAttemptsToRescoreParams) ([]Attempt, error)
 	ListAudioDictations(ctx context.Context, userID sql.NullInt64) ([]Dictation, error)
+	ListDictationsByAudioStatus(ctx context.Context, audioStatus sql.NullString) ([]Dictation, error)
 	ListDictationsByUser(ctx context.Context, userID sql.NullInt64) ([]Dictation, error)
 	ListPerformanceSummaryByUser(ctx context.Context, userID sql.NullInt64) ([]PerformanceSummary, error)
 	ListTextDictations(ctx context.Context, userID sql.NullInt64) ([]Dictation, error)
 	ListUsers(ctx context.Context, arg ListUsersParams) ([]User, error)
+	QueueDictationAudio(ctx context.Context, id int64) (Dictation, error)
+	RebuildUserPerformanceSummaries(ctx context.Context, arg RebuildUserPerformanceSummariesParams) error
 	RecentAttemptsByUser(ctx context.Context, arg RecentAttemptsByUserParams) ([]PerformanceSummary, error)
+	SetDictationLexicon(ctx context.Context, arg SetDictationLexiconParams) (Dictation, error)
+	SetDictationTranscript(ctx context.Context, arg SetDictationTranscriptParams) (Dictation, error)
 	UpdateAttemptAccuracy(ctx context.Context, arg UpdateAttemptAccuracyParams) (Attempt, error)
 	UpdateDictation(ctx context.Context, arg UpdateDictationParams) (Dictation, error)
 	UpdatePerformanceSummary(ctx context.Context, arg UpdatePerformanceSummaryParams) (PerformanceSummary, error)
 	UpdateSetting(ctx context.Context, arg UpdateSettingParams) (Setting, error)
 	UpdateUsers(ctx context.Context, arg UpdateUsersParams) (User, error)
+	// Marks the session used, returning no rows if it was already used, and
+	// measures the elapsed time on the database clock that set started_at.
+	UseAttemptSession(ctx context.Context, id uuid.UUID) (UseAttemptSessionRow, error)
 	UserAggregatePerformance(ctx context.Context) ([]UserAggregatePerformanceRow, error)
 }
 
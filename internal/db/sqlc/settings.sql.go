@@ -8,26 +8,30 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/sqlc-dev/pqtype"
 )
 
 const createSetting = `-- name: CreateSetting :one
 INSERT INTO settings (
     user_id, default_voice, default_speed, 
     highlight_color_grammar, highlight_color_spelling, highlight_color_case, 
-    created_at, updated_at
+    tolerance, lexicon, created_at, updated_at
 ) VALUES (
-    $1, $2, $3, $4, $5, $6, NOW(), NOW()
+    $1, $2, $3, $4, $5, $6, $7, $8, NOW(), NOW()
 )
-RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 `
 
 type CreateSettingParams struct {
-	UserID                 sql.NullInt64   `json:"user_id"`
-	DefaultVoice           sql.NullString  `json:"default_voice"`
-	DefaultSpeed           sql.NullFloat64 `json:"default_speed"`
-	HighlightColorGrammar  sql.NullString  `json:"highlight_color_grammar"`
-	HighlightColorSpelling sql.NullString  `json:"highlight_color_spelling"`
-	HighlightColorCase     sql.NullString  `json:"highlight_color_case"`
+	UserID                 sql.NullInt64         `json:"user_id"`
+	DefaultVoice           sql.NullString        `json:"default_voice"`
+	DefaultSpeed           sql.NullFloat64       `json:"default_speed"`
+	HighlightColorGrammar  sql.NullString        `json:"highlight_color_grammar"`
+	HighlightColorSpelling sql.NullString        `json:"highlight_color_spelling"`
+	HighlightColorCase     sql.NullString        `json:"highlight_color_case"`
+	Tolerance              pqtype.NullRawMessage `json:"tolerance"`
+	Lexicon                pqtype.NullRawMessage `json:"lexicon"`
 }
 
 func (q *Queries) CreateSetting(ctx context.Context, arg CreateSettingParams) (Setting, error) {
@@ -38,6 +42,8 @@ func (q *Queries) CreateSetting(ctx context.Context, arg CreateSettingParams) (S
 		arg.HighlightColorGrammar,
 		arg.HighlightColorSpelling,
 		arg.HighlightColorCase,
+		arg.Tolerance,
+		arg.Lexicon,
 	)
 	var i Setting
 	err := row.Scan(
@@ -50,6 +56,8 @@ func (q *Queries) CreateSetting(ctx context.Context, arg CreateSettingParams) (S
 		&i.HighlightColorCase,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tolerance,
+		&i.Lexicon,
 	)
 	return i, err
 }
@@ -65,7 +73,7 @@ func (q *Queries) DeleteSetting(ctx context.Context, id int64) error {
 }
 
 const getSettingByID = `-- name: GetSettingByID :one
-SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 FROM settings
 WHERE id = $1
 `
@@ -83,12 +91,14 @@ func (q *Queries) GetSettingByID(ctx context.Context, id int64) (Setting, error)
 		&i.HighlightColorCase,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tolerance,
+		&i.Lexicon,
 	)
 	return i, err
 }
 
 const getSettingByUserID = `-- name: GetSettingByUserID :one
-SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+SELECT id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 FROM settings
 WHERE user_id = $1
 `
@@ -106,6 +116,8 @@ func (q *Queries) GetSettingByUserID(ctx context.Context, userID sql.NullInt64)
 		&i.HighlightColorCase,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tolerance,
+		&i.Lexicon,
 	)
 	return i, err
 }
@@ -118,18 +130,22 @@ SET
     highlight_color_grammar = $4,
     highlight_color_spelling = $5,
     highlight_color_case = $6,
+    tolerance = $7,
+    lexicon = $8,
     updated_at = NOW()
 WHERE id = $1
-RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at
+RETURNING id, user_id, default_voice, default_speed, highlight_color_grammar, highlight_color_spelling, highlight_color_case, created_at, updated_at, tolerance, lexicon
 `
 
 type UpdateSettingParams struct {
-	ID                     int64           `json:"id"`
-	DefaultVoice           sql.NullString  `json:"default_voice"`
-	DefaultSpeed           sql.NullFloat64 `json:"default_speed"`
-	HighlightColorGrammar  sql.NullString  `json:"highlight_color_grammar"`
-	HighlightColorSpelling sql.NullString  `json:"highlight_color_spelling"`
-	HighlightColorCase     sql.NullString  `json:"highlight_color_case"`
+	ID                     int64                 `json:"id"`
+	DefaultVoice           sql.NullString        `json:"default_voice"`
+	DefaultSpeed           sql.NullFloat64       `json:"default_speed"`
+	HighlightColorGrammar  sql.NullString        `json:"highlight_color_grammar"`
+	HighlightColorSpelling sql.NullString        `json:"highlight_color_spelling"`
+	HighlightColorCase     sql.NullString        `json:"highlight_color_case"`
+	Tolerance              pqtype.NullRawMessage `json:"tolerance"`
+	Lexicon                pqtype.NullRawMessage `json:"lexicon"`
 }
 
 func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) (Setting, error) {
@@ -140,6 +156,8 @@ func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) (S
 		arg.HighlightColorGrammar,
 		arg.HighlightColorSpelling,
 		arg.HighlightColorCase,
+		arg.Tolerance,
+		arg.Lexicon,
 	)
 	var i Setting
 	err := row.Scan(
@@ -152,6 +170,8 @@ func (q *Queries) UpdateSetting(ctx context.Context, arg UpdateSettingParams) (S
 		&i.HighlightColorCase,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.Tolerance,
+		&i.Lexicon,
 	)
 	return i, err
 }
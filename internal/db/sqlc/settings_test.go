@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 )
 
@@ -77,6 +78,8 @@ func TestUpdateSetting(t *testing.T) {
 		HighlightColorGrammar: sql.NullString{String: "#111111", Valid: true},
 		HighlightColorSpelling: sql.NullString{String: "#222222", Valid: true},
 		HighlightColorCase:     sql.NullString{String: "#333333", Valid: true},
+		Tolerance:              pqtype.NullRawMessage{RawMessage: []byte(`{"profile":"standard"}`), Valid: true},
+		Lexicon:                pqtype.NullRawMessage{RawMessage: []byte(`[{"written":"Nguyen","spoken":"Win"}]`), Valid: true},
 	}
 
 	setting2, err := testQueries.UpdateSetting(context.Background(), arg)
@@ -88,6 +91,8 @@ func TestUpdateSetting(t *testing.T) {
 	require.Equal(t, arg.HighlightColorGrammar, setting2.HighlightColorGrammar)
 	require.Equal(t, arg.HighlightColorSpelling, setting2.HighlightColorSpelling)
 	require.Equal(t, arg.HighlightColorCase, setting2.HighlightColorCase)
+	require.JSONEq(t, string(arg.Tolerance.RawMessage), string(setting2.Tolerance.RawMessage))
+	require.JSONEq(t, string(arg.Lexicon.RawMessage), string(setting2.Lexicon.RawMessage))
 }
 
 func TestDeleteSetting(t *testing.T) {
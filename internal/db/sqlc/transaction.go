@@ -3,9 +3,14 @@ package db
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 )
 
+// RollingWindow is how many of a user's most recent attempts at a dictation
+// the rolling speed averages in performance_summary cover.
+const RollingWindow = 10
 
 // Store defines all functions to execute db queries and transactions
 type Store interface {
@@ -13,6 +18,7 @@ type Store interface {
 	SubmitAttemptTx(ctx context.Context, arg CreateAttemptsParams) (SubmitAttemptTxResult, error)
 	CreateUserTx(ctx context.Context, arg CreateUsersParams) (CreateUserTxResult, error)
 	DeleteDictationTx(ctx context.Context, dictationID int64) error
+	RescoreAttemptsTx(ctx context.Context, updates []UpdateAttemptAccuracyParams) error
 }
 
 // SQLStore provides all functions to execute db queries and transactions
@@ -47,42 +53,90 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	return tx.Commit()
 }
 
+// ErrAttemptSessionUsed is returned by SubmitAttemptTx when the attempt's
+// session has already been used by another submission.
+var ErrAttemptSessionUsed = errors.New("attempt session already used")
+
 // SubmitAttemptTxResult contains the result of the SubmitAttemptTx operation
 type SubmitAttemptTxResult struct {
 	Attempt            Attempt
 	PerformanceSummary PerformanceSummary
 }
 
-// SubmitAttemptTx performs the necessary steps to submit an attempt and update performance summary
+// SubmitAttemptTx performs the necessary steps to submit an attempt and update performance summary.
+// The attempt's session, if it has one, is used up in the same transaction.
+// The summary is left zero for segment attempts.
 func (store *SQLStore) SubmitAttemptTx(ctx context.Context, arg CreateAttemptsParams) (SubmitAttemptTxResult, error) {
 	var result SubmitAttemptTxResult
 
 	err := store.execTx(ctx, func(q *Queries) error {
 		var err error
 
+		// A failed submission leaves the session usable, and of two
+		// submissions racing with one session only the first is stored
+		if arg.SessionID.Valid {
+			if _, err := q.UseAttemptSession(ctx, arg.SessionID.UUID); err != nil {
+				if err == sql.ErrNoRows {
+					return ErrAttemptSessionUsed
+				}
+				return err
+			}
+		}
+
 		// 1. Create the Attempt
 		result.Attempt, err = q.CreateAttempts(ctx, arg)
 		if err != nil {
 			return err
 		}
+		// Attempts on a single segment don't count towards the dictation's
+		// performance summary
+		if arg.Segment.Valid {
+			return nil
+		}
+
+		// 2. Average speed over the most recent attempts, including this one
+		rolling, err := q.GetRollingSpeed(ctx, GetRollingSpeedParams{
+			UserID:      arg.UserID,
+			DictationID: arg.DictationID,
+			Limit:       RollingWindow,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Weighted accuracy is aggregated from the attempts themselves, since
+		// attempts scored before it existed have none
+		weighted, err := q.GetWeightedAccuracyStats(ctx, GetWeightedAccuracyStatsParams{
+			UserID:      arg.UserID,
+			DictationID: arg.DictationID,
+		})
+		if err != nil {
+			return err
+		}
 
-		// 2. Get Performance Summary
+		// 3. Get Performance Summary
 		summary, err := q.GetPerformanceSummaryByUserAndDictation(ctx, GetPerformanceSummaryByUserAndDictationParams{
 			UserID:      arg.UserID,
 			DictationID: arg.DictationID,
 		})
 
-		// 3. Update or Create Summary
+		// 4. Update or Create Summary
 		if err == sql.ErrNoRows {
 			// Create new summary
 			result.PerformanceSummary, err = q.CreatePerformanceSummary(ctx, CreatePerformanceSummaryParams{
-				UserID:          arg.UserID,
-				DictationID:     arg.DictationID,
-				TotalAttempts:   sql.NullInt32{Int32: 1, Valid: true},
-				BestAccuracy:    arg.Accuracy,
-				AverageAccuracy: arg.Accuracy,
-				AverageTime:     arg.TimeSpent,
-				LastAttemptAt:   sql.NullTime{Time: result.Attempt.CreatedAt.Time, Valid: true},
+				UserID:                  arg.UserID,
+				DictationID:             arg.DictationID,
+				TotalAttempts:           sql.NullInt32{Int32: 1, Valid: true},
+				BestAccuracy:            arg.Accuracy,
+				AverageAccuracy:         arg.Accuracy,
+				AverageTime:             arg.TimeSpent,
+				LastAttemptAt:           sql.NullTime{Time: result.Attempt.CreatedAt.Time, Valid: true},
+				RollingGrossWpm:         sql.NullFloat64{Float64: rolling.RollingGrossWpm, Valid: true},
+				RollingNetWpm:           sql.NullFloat64{Float64: rolling.RollingNetWpm, Valid: true},
+				RollingCpm:              sql.NullFloat64{Float64: rolling.RollingCpm, Valid: true},
+				RollingCharAccuracy:     sql.NullFloat64{Float64: rolling.RollingCharAccuracy, Valid: true},
+				BestWeightedAccuracy:    sql.NullFloat64{Float64: weighted.BestWeightedAccuracy, Valid: true},
+				AverageWeightedAccuracy: sql.NullFloat64{Float64: weighted.AverageWeightedAccuracy, Valid: true},
 			})
 			if err != nil {
 				return err
@@ -93,7 +147,7 @@ func (store *SQLStore) SubmitAttemptTx(ctx context.Context, arg CreateAttemptsPa
 			// Update existing summary
 			newTotalAttempts := summary.TotalAttempts.Int32 + 1
 			newAvgAccuracy := ((summary.AverageAccuracy.Float64 * float64(summary.TotalAttempts.Int32)) + arg.Accuracy.Float64) / float64(newTotalAttempts)
-			
+
 			// Handle Average Time (if present)
 			currentAvgTime := summary.AverageTime.Float64
 			newTime := arg.TimeSpent.Float64
@@ -106,12 +160,18 @@ func (store *SQLStore) SubmitAttemptTx(ctx context.Context, arg CreateAttemptsPa
 			}
 
 			result.PerformanceSummary, err = q.UpdatePerformanceSummary(ctx, UpdatePerformanceSummaryParams{
-				ID:              summary.ID,
-				TotalAttempts:   sql.NullInt32{Int32: newTotalAttempts, Valid: true},
-				BestAccuracy:    sql.NullFloat64{Float64: newBestAccuracy, Valid: true},
-				AverageAccuracy: sql.NullFloat64{Float64: newAvgAccuracy, Valid: true},
-				AverageTime:     sql.NullFloat64{Float64: newAvgTime, Valid: true},
-				LastAttemptAt:   sql.NullTime{Time: result.Attempt.CreatedAt.Time, Valid: true},
+				ID:                      summary.ID,
+				TotalAttempts:           sql.NullInt32{Int32: newTotalAttempts, Valid: true},
+				BestAccuracy:            sql.NullFloat64{Float64: newBestAccuracy, Valid: true},
+				AverageAccuracy:         sql.NullFloat64{Float64: newAvgAccuracy, Valid: true},
+				AverageTime:             sql.NullFloat64{Float64: newAvgTime, Valid: true},
+				LastAttemptAt:           sql.NullTime{Time: result.Attempt.CreatedAt.Time, Valid: true},
+				RollingGrossWpm:         sql.NullFloat64{Float64: rolling.RollingGrossWpm, Valid: true},
+				RollingNetWpm:           sql.NullFloat64{Float64: rolling.RollingNetWpm, Valid: true},
+				RollingCpm:              sql.NullFloat64{Float64: rolling.RollingCpm, Valid: true},
+				RollingCharAccuracy:     sql.NullFloat64{Float64: rolling.RollingCharAccuracy, Valid: true},
+				BestWeightedAccuracy:    sql.NullFloat64{Float64: weighted.BestWeightedAccuracy, Valid: true},
+				AverageWeightedAccuracy: sql.NullFloat64{Float64: weighted.AverageWeightedAccuracy, Valid: true},
 			})
 			if err != nil {
 				return err
@@ -146,7 +206,7 @@ func (store *SQLStore) CreateUserTx(ctx context.Context, arg CreateUsersParams)
 		// 2. Create Default Settings
 		result.Setting, err = q.CreateSetting(ctx, CreateSettingParams{
 			UserID:                 sql.NullInt64{Int64: result.User.ID, Valid: true},
-			DefaultVoice:           sql.NullString{String: "en-US-Neural2-F", Valid: true}, 
+			DefaultVoice:           sql.NullString{String: "alloy", Valid: true},
 			DefaultSpeed:           sql.NullFloat64{Float64: 1.0, Valid: true},
 			HighlightColorGrammar:  sql.NullString{String: "#FFA500", Valid: true}, // Orange
 			HighlightColorSpelling: sql.NullString{String: "#FF0000", Valid: true}, // Red
@@ -191,6 +251,33 @@ func (store *SQLStore) DeleteDictationTx(ctx context.Context, dictationID int64)
 	})
 }
 
-
-
-
+// RescoreAttemptsTx stores re-scored attempts and recomputes the performance
+// summaries of their users from them, in one transaction so that summaries
+// never disagree with the attempts they are built from
+func (store *SQLStore) RescoreAttemptsTx(ctx context.Context, updates []UpdateAttemptAccuracyParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		var users []sql.NullInt64
+		for _, arg := range updates {
+			attempt, err := q.UpdateAttemptAccuracy(ctx, arg)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(users, attempt.UserID) {
+				users = append(users, attempt.UserID)
+			}
+		}
+		for _, userID := range users {
+			if err := q.DeleteUserPerformanceSummaries(ctx, userID); err != nil {
+				return err
+			}
+			err := q.RebuildUserPerformanceSummaries(ctx, RebuildUserPerformanceSummariesParams{
+				RollingWindow: RollingWindow,
+				UserID:        userID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"testing"
 
+	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 	"github.com/stretchr/testify/require"
 	"github.com/nilesh0729/PixelScribe/internal/util"
@@ -26,12 +27,15 @@ func TestSubmitAttemptTx(t *testing.T) {
 			Int32: 2,
 			Valid: true,
 		},
-		GrammaticalErrors: sql.NullInt32{Int32: 0, Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: 0, Valid: true},
 		SpellingErrors:    sql.NullInt32{Int32: 0, Valid: true},
 		CaseErrors:        sql.NullInt32{Int32: 0, Valid: true},
 		Accuracy:          sql.NullFloat64{Float64: 100, Valid: true},
+		WeightedAccuracy:  sql.NullFloat64{Float64: 100, Valid: true},
 		ComparisonData:    pqtype.NullRawMessage{RawMessage: []byte(`{}`), Valid: true},
 		TimeSpent:         sql.NullFloat64{Float64: 10.0, Valid: true},
+		GrossWpm:          sql.NullFloat64{Float64: 40, Valid: true},
+		NetWpm:            sql.NullFloat64{Float64: 40, Valid: true},
 	}
 
 	result1, err := store.SubmitAttemptTx(context.Background(), arg1)
@@ -42,6 +46,7 @@ func TestSubmitAttemptTx(t *testing.T) {
 	require.Equal(t, float64(100), result1.PerformanceSummary.BestAccuracy.Float64)
 	require.Equal(t, int32(1), result1.PerformanceSummary.TotalAttempts.Int32)
 	require.Equal(t, float64(10.0), result1.PerformanceSummary.AverageTime.Float64)
+	require.Equal(t, float64(40), result1.PerformanceSummary.RollingGrossWpm.Float64)
 
 	// round 2: Second Attempt (Low Accuracy)
 	arg2 := CreateAttemptsParams{
@@ -53,12 +58,15 @@ func TestSubmitAttemptTx(t *testing.T) {
 			Int32: 1,
 			Valid: true,
 		},
-		GrammaticalErrors: sql.NullInt32{Int32: 0, Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: 0, Valid: true},
 		SpellingErrors:    sql.NullInt32{Int32: 1, Valid: true},
 		CaseErrors:        sql.NullInt32{Int32: 0, Valid: true},
 		Accuracy:          sql.NullFloat64{Float64: 50, Valid: true},
+		WeightedAccuracy:  sql.NullFloat64{Float64: 70, Valid: true},
 		ComparisonData:    pqtype.NullRawMessage{RawMessage: []byte(`{}`), Valid: true},
 		TimeSpent:         sql.NullFloat64{Float64: 20.0, Valid: true},
+		GrossWpm:          sql.NullFloat64{Float64: 20, Valid: true},
+		NetWpm:            sql.NullFloat64{Float64: 10, Valid: true},
 	}
 
 	result2, err := store.SubmitAttemptTx(context.Background(), arg2)
@@ -77,8 +85,71 @@ func TestSubmitAttemptTx(t *testing.T) {
 	// Average Accuracy: (100 + 50) / 2 = 75
 	require.Equal(t, float64(75), result2.PerformanceSummary.AverageAccuracy.Float64)
 
+	// Weighted accuracy: best 100, average (100 + 70) / 2 = 85
+	require.Equal(t, float64(100), result2.PerformanceSummary.BestWeightedAccuracy.Float64)
+	require.Equal(t, float64(85), result2.PerformanceSummary.AverageWeightedAccuracy.Float64)
+
+	// Rolling speed covers both attempts: (40 + 20) / 2 and (40 + 10) / 2
+	require.Equal(t, float64(30), result2.PerformanceSummary.RollingGrossWpm.Float64)
+	require.Equal(t, float64(25), result2.PerformanceSummary.RollingNetWpm.Float64)
+
 	// Average Time: (10 + 20) / 2 = 15
 	require.Equal(t, float64(15), result2.PerformanceSummary.AverageTime.Float64)
+
+	// round 3: Practising one segment leaves the summary alone
+	arg3 := arg2
+	arg3.Accuracy = sql.NullFloat64{Float64: 0, Valid: true}
+	arg3.GrossWpm = sql.NullFloat64{Float64: 100, Valid: true}
+	arg3.Segment = sql.NullInt32{Int32: 1, Valid: true}
+	result3, err := store.SubmitAttemptTx(context.Background(), arg3)
+	require.NoError(t, err)
+	require.Equal(t, int32(1), result3.Attempt.Segment.Int32)
+	require.Zero(t, result3.PerformanceSummary.ID)
+
+	summary, err := store.GetPerformanceSummaryByUserAndDictation(context.Background(), GetPerformanceSummaryByUserAndDictationParams{
+		UserID:      arg1.UserID,
+		DictationID: arg1.DictationID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), summary.TotalAttempts.Int32)
+	require.Equal(t, float64(30), summary.RollingGrossWpm.Float64)
+}
+
+func TestSubmitAttemptTxUsesSession(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := RandomUser(t)
+	dict := RandomTextDictation(t, user)
+	session, err := testQueries.CreateAttemptSession(context.Background(), CreateAttemptSessionParams{
+		ID:          uuid.New(),
+		UserID:      user.ID,
+		DictationID: dict.ID,
+	})
+	require.NoError(t, err)
+
+	arg := CreateAttemptsParams{
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		DictationID: sql.NullInt64{Int64: dict.ID, Valid: true},
+		TypedText:   sql.NullString{String: "hello world", Valid: true},
+		Accuracy:    sql.NullFloat64{Float64: 100, Valid: true},
+		SessionID:   uuid.NullUUID{UUID: session.ID, Valid: true},
+	}
+	_, err = store.SubmitAttemptTx(context.Background(), arg)
+	require.NoError(t, err)
+
+	got, err := testQueries.GetAttemptSession(context.Background(), session.ID)
+	require.NoError(t, err)
+	require.True(t, got.UsedAt.Valid)
+
+	// A second submission with the session stores nothing
+	_, err = store.SubmitAttemptTx(context.Background(), arg)
+	require.ErrorIs(t, err, ErrAttemptSessionUsed)
+	count, err := testQueries.CountAttemptsByDictation(context.Background(), CountAttemptsByDictationParams{
+		UserID:      arg.UserID,
+		DictationID: arg.DictationID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
 }
 
 func TestCreateUserTx(t *testing.T) {
@@ -160,3 +231,37 @@ func TestDeleteDictationTx(t *testing.T) {
 		require.NotEqual(t, dict.ID, d.ID)
 	}
 }
+
+func TestRescoreAttemptsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	user := RandomUser(t)
+	dict := RandomTextDictation(t, user)
+
+	first := createRandomAttempt(t, user.ID, dict.ID)
+	second := createRandomAttempt(t, user.ID, dict.ID)
+
+	// Re-score the second attempt as if a newer scorer disagreed with the first
+	err := store.RescoreAttemptsTx(context.Background(), []UpdateAttemptAccuracyParams{{
+		ID:             second.ID,
+		Accuracy:       sql.NullFloat64{Float64: 60, Valid: true},
+		CorrectWords:   sql.NullInt32{Int32: 1, Valid: true},
+		TimeSpent:      second.TimeSpent,
+		TotalWords:     second.TotalWords,
+		ScoringVersion: sql.NullString{String: "test/v2", Valid: true},
+	}})
+	require.NoError(t, err)
+
+	rescored, err := testQueries.GetAttemptById(context.Background(), second.ID)
+	require.NoError(t, err)
+	require.Equal(t, "test/v2", rescored.ScoringVersion.String)
+
+	summary, err := testQueries.GetPerformanceSummaryByUserAndDictation(context.Background(), GetPerformanceSummaryByUserAndDictationParams{
+		UserID:      sql.NullInt64{Int64: user.ID, Valid: true},
+		DictationID: sql.NullInt64{Int64: dict.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), summary.TotalAttempts.Int32)
+	require.Equal(t, first.Accuracy.Float64, summary.BestAccuracy.Float64)
+	require.InDelta(t, (first.Accuracy.Float64+60)/2, summary.AverageAccuracy.Float64, 0.001)
+}
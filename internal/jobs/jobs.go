@@ -0,0 +1,397 @@
+// Package jobs runs background work on dictations, such as pre-generating
+// the audio of text dictations so playback starts without waiting for TTS,
+// outlining stored audio as waveforms and stretching it to other speeds.
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+)
+
+// Audio generation states stored in dictations.audio_status. Dictations
+// whose audio was not generated by PixelScribe have none.
+const (
+	AudioPending = "pending"
+	AudioReady   = "ready"
+	AudioFailed  = "failed"
+)
+
+// Options tune a Runner. Zero values take the defaults.
+type Options struct {
+	// Workers is how many dictations are processed at once. Defaults to 2.
+	Workers int
+	// MaxAttempts is how often generation is tried before the dictation is
+	// marked failed. Defaults to 3.
+	MaxAttempts int
+	// Backoff is the wait before the first retry, doubled for each one
+	// after. Defaults to 30 seconds.
+	Backoff time.Duration
+	// AudioURL returns where clients fetch a dictation's stored audio.
+	AudioURL func(dictationID int64) string
+}
+
+// Runner generates dictation audio, waveforms and speed variants in the
+// background. Work is queued in memory; the pending state in the database
+// lets a restarted Runner pick up where the last one stopped.
+type Runner struct {
+	store db.Store
+	tts   tts.Provider
+	blobs storage.Store
+	opts  Options
+
+	mu     sync.Mutex
+	queue  []task
+	queued map[task]bool
+	wake   chan struct{}
+}
+
+// task is a unit of queued work on one dictation.
+type task struct {
+	dictationID int64
+	waveform    bool    // compute the waveform rather than generate audio
+	speed       float64 // stretch the stored audio to this speed instead
+}
+
+// NewRunner returns a Runner that synthesizes with provider and keeps the
+// audio in blobs.
+func NewRunner(store db.Store, provider tts.Provider, blobs storage.Store, opts Options) *Runner {
+	if opts.Workers <= 0 {
+		opts.Workers = 2
+	}
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = 3
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = 30 * time.Second
+	}
+	if opts.AudioURL == nil {
+		opts.AudioURL = func(id int64) string { return fmt.Sprintf("/dictations/%d/audio", id) }
+	}
+	return &Runner{
+		store:  store,
+		tts:    provider,
+		blobs:  blobs,
+		opts:   opts,
+		queued: map[task]bool{},
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Enqueue schedules audio generation for a dictation whose audio_status is
+// pending. Enqueueing a dictation already waiting is a no-op.
+func (r *Runner) Enqueue(dictationID int64) {
+	r.push(task{dictationID: dictationID})
+}
+
+// EnqueueWaveform schedules computing the waveform of a dictation's stored
+// audio.
+func (r *Runner) EnqueueWaveform(dictationID int64) {
+	r.push(task{dictationID: dictationID, waveform: true})
+}
+
+// EnqueueSpeedVariant schedules stretching a dictation's stored audio to
+// play at speed, which should be rounded by audio.RoundSpeed.
+func (r *Runner) EnqueueSpeedVariant(dictationID int64, speed float64) {
+	r.push(task{dictationID: dictationID, speed: speed})
+}
+
+func (r *Runner) push(t task) {
+	r.mu.Lock()
+	if !r.queued[t] {
+		r.queued[t] = true
+		r.queue = append(r.queue, t)
+	}
+	r.mu.Unlock()
+	r.signal()
+}
+
+func (r *Runner) signal() {
+	select {
+	case r.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next blocks until a task is queued or ctx is done.
+func (r *Runner) next(ctx context.Context) (task, bool) {
+	for {
+		r.mu.Lock()
+		if len(r.queue) > 0 {
+			t := r.queue[0]
+			r.queue = r.queue[1:]
+			delete(r.queued, t)
+			more := len(r.queue) > 0
+			r.mu.Unlock()
+			if more {
+				// Pass the wake-up on to another idle worker
+				r.signal()
+			}
+			return t, true
+		}
+		r.mu.Unlock()
+
+		select {
+		case <-r.wake:
+		case <-ctx.Done():
+			return task{}, false
+		}
+	}
+}
+
+// Run queues the dictations left pending by an earlier process, then works
+// through the queue until ctx is done.
+func (r *Runner) Run(ctx context.Context) error {
+	pending, err := r.store.ListDictationsByAudioStatus(ctx, sql.NullString{String: AudioPending, Valid: true})
+	if err != nil {
+		return fmt.Errorf("cannot list pending dictations: %w", err)
+	}
+	for _, d := range pending {
+		r.Enqueue(d.ID)
+	}
+
+	var wg sync.WaitGroup
+	for range r.opts.Workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work(ctx)
+		}()
+	}
+	wg.Wait()
+	return ctx.Err()
+}
+
+func (r *Runner) work(ctx context.Context) {
+	for {
+		t, ok := r.next(ctx)
+		if !ok {
+			return
+		}
+		id := t.dictationID
+		// Waveforms and speed variants are not retried; asking for them
+		// queues them again
+		switch {
+		case t.waveform:
+			if err := r.GenerateWaveform(ctx, id); err != nil && ctx.Err() == nil {
+				log.Printf("cannot compute waveform for dictation %d: %v", id, err)
+			}
+			continue
+		case t.speed != 0:
+			if err := r.GenerateSpeedVariant(ctx, id, t.speed); err != nil && ctx.Err() == nil {
+				log.Printf("cannot stretch audio of dictation %d to speed %.2f: %v", id, t.speed, err)
+			}
+			continue
+		}
+		d, err := r.GenerateAudio(ctx, id)
+		if err == nil {
+			continue
+		}
+		log.Printf("cannot generate audio for dictation %d: %v", id, err)
+		if d.AudioStatus.String == AudioPending && ctx.Err() == nil {
+			delay := r.opts.Backoff << max(d.AudioAttempts-1, 0)
+			time.AfterFunc(delay, func() {
+				if ctx.Err() == nil {
+					r.Enqueue(id)
+				}
+			})
+		}
+	}
+}
+
+// GenerateAudio synthesizes a pending text dictation with its owner's
+// default voice, stores the audio and records the outcome on the dictation,
+// which it returns. A failure leaves the dictation pending for a retry until
+// MaxAttempts is reached, then marks it failed. Dictations that are not
+// pending are returned unchanged.
+func (r *Runner) GenerateAudio(ctx context.Context, dictationID int64) (db.Dictation, error) {
+	d, err := r.store.GetDictation(ctx, dictationID)
+	if err == sql.ErrNoRows {
+		return d, nil
+	}
+	if err != nil {
+		return d, err
+	}
+	if d.AudioStatus.String != AudioPending || d.Type.String != "text" {
+		return d, nil
+	}
+
+	key, duration, err := r.synthesize(ctx, d)
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down; the dictation stays pending for the next run
+			return d, err
+		}
+		status := AudioPending
+		if int(d.AudioAttempts)+1 >= r.opts.MaxAttempts {
+			status = AudioFailed
+		}
+		failed, failErr := r.store.FailDictationAudio(ctx, db.FailDictationAudioParams{
+			ID:          d.ID,
+			AudioStatus: sql.NullString{String: status, Valid: true},
+			AudioError:  sql.NullString{String: err.Error(), Valid: true},
+		})
+		if failErr != nil {
+			return d, fmt.Errorf("%w; cannot record failure: %v", err, failErr)
+		}
+		return failed, err
+	}
+
+	ready, err := r.store.CompleteDictationAudio(ctx, db.CompleteDictationAudioParams{
+		ID:              d.ID,
+		Content:         d.Content,
+		Lexicon:         d.Lexicon,
+		AudioUrl:        sql.NullString{String: r.opts.AudioURL(d.ID), Valid: true},
+		AudioKey:        sql.NullString{String: key, Valid: true},
+		AudioDurationMs: sql.NullInt64{Int64: duration.Milliseconds(), Valid: true},
+	})
+	if err == sql.ErrNoRows {
+		// The text or lexicon changed or the dictation was deleted while
+		// we worked; this audio is stale and a newer job covers the change
+		DeleteAudio(ctx, r.blobs, key)
+		return d, nil
+	}
+	if err != nil {
+		return d, err
+	}
+	if d.AudioKey.Valid && d.AudioKey.String != key {
+		DeleteAudio(ctx, r.blobs, d.AudioKey.String)
+	}
+	return ready, nil
+}
+
+// synthesize reads d's content aloud, verbalized with the dictation's and
+// then the owner's pronunciation lexicon, and stores the audio under a key
+// derived from the request, returning the key and the audio's duration.
+func (r *Runner) synthesize(ctx context.Context, d db.Dictation) (string, time.Duration, error) {
+	setting, err := r.store.GetSettingByUserID(ctx, d.UserID)
+	if err != nil && err != sql.ErrNoRows {
+		return "", 0, err
+	}
+	dictationLexicon, err := lexicon.Parse(d.Lexicon.RawMessage)
+	if err != nil {
+		return "", 0, err
+	}
+	userLexicon, err := lexicon.Parse(setting.Lexicon.RawMessage)
+	if err != nil {
+		return "", 0, err
+	}
+	text, _ := lexicon.Verbalize(d.Content.String, d.Language.String, dictationLexicon, userLexicon)
+	req := tts.WithDefaults(r.tts, tts.Request{Text: text}, setting.DefaultVoice.String, setting.DefaultSpeed.Float64)
+
+	speech, err := r.tts.Synthesize(ctx, req)
+	if err != nil {
+		return "", 0, err
+	}
+	duration, err := speech.Duration()
+	if err != nil {
+		return "", 0, err
+	}
+
+	key := fmt.Sprintf("dictations/%d/%s.%s", d.ID, tts.Key(r.tts, req), speech.Format)
+	if err := r.blobs.Put(ctx, key, bytes.NewReader(speech.Data), int64(len(speech.Data)), speech.Format.ContentType()); err != nil {
+		return "", 0, fmt.Errorf("cannot store audio: %w", err)
+	}
+	if err := r.storeWaveform(ctx, key, speech.Data); err != nil {
+		// The audio is usable without it, and asking for the waveform
+		// queues another try
+		log.Printf("cannot compute waveform for dictation %d: %v", d.ID, err)
+	}
+	return key, duration, nil
+}
+
+// GenerateWaveform computes and stores the waveform of a dictation's
+// stored audio. Dictations without stored audio are skipped.
+func (r *Runner) GenerateWaveform(ctx context.Context, dictationID int64) error {
+	d, err := r.store.GetDictation(ctx, dictationID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !d.AudioKey.Valid {
+		return nil
+	}
+
+	object, err := r.blobs.Open(ctx, d.AudioKey.String)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(object)
+	object.Close()
+	if err != nil {
+		return err
+	}
+	return r.storeWaveform(ctx, d.AudioKey.String, data)
+}
+
+// GenerateSpeedVariant stretches a dictation's stored WAV audio to play at
+// speed without changing its pitch and stores it beside the original.
+// Dictations without stored audio are skipped, and audio over
+// MaxStretchBytes is refused.
+func (r *Runner) GenerateSpeedVariant(ctx context.Context, dictationID int64, speed float64) error {
+	d, err := r.store.GetDictation(ctx, dictationID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !d.AudioKey.Valid {
+		return nil
+	}
+
+	object, err := r.blobs.Open(ctx, d.AudioKey.String)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if object.Size > MaxStretchBytes {
+		object.Close()
+		return ErrTooLongToStretch
+	}
+	data, err := io.ReadAll(object)
+	object.Close()
+	if err != nil {
+		return err
+	}
+	stretched, err := audio.StretchWAV(data, speed)
+	if err != nil {
+		return err
+	}
+	variant := storage.VariantKey(d.AudioKey.String, SpeedVariant(speed))
+	return r.blobs.Put(ctx, variant, bytes.NewReader(stretched), int64(len(stretched)), "audio/wav")
+}
+
+// storeWaveform outlines the audio stored under key and saves the waveform
+// beside it.
+func (r *Runner) storeWaveform(ctx context.Context, key string, data []byte) error {
+	waveform, err := audio.ComputeWaveform(data)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(waveform)
+	if err != nil {
+		return err
+	}
+	return r.blobs.Put(ctx, storage.VariantKey(key, WaveformVariant), bytes.NewReader(body), int64(len(body)), "application/json")
+}
@@ -0,0 +1,316 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+	"github.com/nilesh0729/PixelScribe/internal/tts"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// failingProvider fails every text containing "fail".
+type failingProvider struct {
+	tts.FakeProvider
+}
+
+func (p failingProvider) Synthesize(ctx context.Context, req tts.Request) (tts.Audio, error) {
+	if strings.Contains(req.Text, "fail") {
+		return tts.Audio{}, errors.New("provider unavailable")
+	}
+	return p.FakeProvider.Synthesize(ctx, req)
+}
+
+func pendingDictation(content string, attempts int32) db.Dictation {
+	return db.Dictation{
+		ID:            42,
+		UserID:        sql.NullInt64{Int64: 7, Valid: true},
+		Type:          sql.NullString{String: "text", Valid: true},
+		Content:       sql.NullString{String: content, Valid: true},
+		AudioStatus:   sql.NullString{String: AudioPending, Valid: true},
+		AudioAttempts: attempts,
+	}
+}
+
+func newTestRunner(t *testing.T, store db.Store) (*Runner, storage.Store) {
+	blobs, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	return NewRunner(store, failingProvider{}, blobs, Options{MaxAttempts: 3}), blobs
+}
+
+func TestGenerateAudio(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, blobs := newTestRunner(t, store)
+
+	d := pendingDictation("the quick brown fox", 0)
+	d.AudioKey = sql.NullString{String: "dictations/42/old.wav", Valid: true}
+	require.NoError(t, blobs.Put(context.Background(), d.AudioKey.String, strings.NewReader("old"), 3, "audio/wav"))
+	slower := storage.VariantKey(d.AudioKey.String, SpeedVariant(0.8))
+	require.NoError(t, blobs.Put(context.Background(), slower, strings.NewReader("old"), 3, "audio/wav"))
+
+	var stored db.CompleteDictationAudioParams
+	store.EXPECT().GetDictation(gomock.Any(), d.ID).Return(d, nil)
+	store.EXPECT().
+		GetSettingByUserID(gomock.Any(), d.UserID).
+		Return(db.Setting{DefaultVoice: sql.NullString{String: "low", Valid: true}}, nil)
+	store.EXPECT().
+		CompleteDictationAudio(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CompleteDictationAudioParams) (db.Dictation, error) {
+			stored = arg
+			ready := d
+			ready.AudioStatus = sql.NullString{String: AudioReady, Valid: true}
+			ready.AudioKey = arg.AudioKey
+			return ready, nil
+		})
+
+	ready, err := runner.GenerateAudio(context.Background(), d.ID)
+	require.NoError(t, err)
+	require.Equal(t, AudioReady, ready.AudioStatus.String)
+
+	require.Equal(t, d.Content, stored.Content)
+	require.Equal(t, "/dictations/42/audio", stored.AudioUrl.String)
+	// Four words of the fake voice take 1.5 seconds
+	require.Equal(t, int64(1500), stored.AudioDurationMs.Int64)
+	key := tts.Key(failingProvider{}, tts.Request{Text: d.Content.String, Voice: "low", Speed: 1})
+	require.Equal(t, "dictations/42/"+key+".wav", stored.AudioKey.String)
+
+	object, err := blobs.Open(context.Background(), stored.AudioKey.String)
+	require.NoError(t, err)
+	data, err := io.ReadAll(object)
+	require.NoError(t, err)
+	object.Close()
+	require.Equal(t, "RIFF", string(data[:4]))
+
+	// Its waveform is stored beside it
+	object, err = blobs.Open(context.Background(), storage.VariantKey(stored.AudioKey.String, WaveformVariant))
+	require.NoError(t, err)
+	var waveform audio.Waveform
+	require.NoError(t, json.NewDecoder(object).Decode(&waveform))
+	object.Close()
+	require.Equal(t, 150, waveform.Length)
+
+	// The audio it replaces is removed, along with its speed variants
+	_, err = blobs.Open(context.Background(), "dictations/42/old.wav")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+	_, err = blobs.Open(context.Background(), slower)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGenerateAudioVerbalizes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, _ := newTestRunner(t, store)
+
+	d := pendingDictation("Dr. Nguyen paid $5", 0)
+	d.Lexicon = pqtype.NullRawMessage{RawMessage: []byte(`[{"written":"Nguyen","spoken":"Win"}]`), Valid: true}
+
+	var stored db.CompleteDictationAudioParams
+	store.EXPECT().GetDictation(gomock.Any(), d.ID).Return(d, nil)
+	// The dictation's lexicon wins over the owner's
+	store.EXPECT().
+		GetSettingByUserID(gomock.Any(), d.UserID).
+		Return(db.Setting{Lexicon: pqtype.NullRawMessage{RawMessage: []byte(`[{"written":"Nguyen","spoken":"Nwin"},{"written":"paid","spoken":"sent"}]`), Valid: true}}, nil)
+	store.EXPECT().
+		CompleteDictationAudio(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CompleteDictationAudioParams) (db.Dictation, error) {
+			stored = arg
+			return d, nil
+		})
+
+	_, err := runner.GenerateAudio(context.Background(), d.ID)
+	require.NoError(t, err)
+
+	key := tts.Key(failingProvider{}, tts.Request{Text: "Doctor Win sent five dollars", Voice: "sine", Speed: 1})
+	require.Equal(t, "dictations/42/"+key+".wav", stored.AudioKey.String)
+	// Audio made with a lexicon that has since changed is not kept
+	require.Equal(t, d.Lexicon, stored.Lexicon)
+}
+
+func TestGenerateWaveform(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, blobs := newTestRunner(t, store)
+
+	recording := audio.EncodeWAV(audio.PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 8000)})
+	uploaded := db.Dictation{
+		ID:       43,
+		Type:     sql.NullString{String: "audio", Valid: true},
+		AudioKey: sql.NullString{String: "uploads/7/passage.wav", Valid: true},
+	}
+	require.NoError(t, blobs.Put(context.Background(), uploaded.AudioKey.String, bytes.NewReader(recording), int64(len(recording)), "audio/wav"))
+	store.EXPECT().GetDictation(gomock.Any(), uploaded.ID).Return(uploaded, nil)
+
+	require.NoError(t, runner.GenerateWaveform(context.Background(), uploaded.ID))
+	object, err := blobs.Open(context.Background(), "uploads/7/passage.waveform.json")
+	require.NoError(t, err)
+	var waveform audio.Waveform
+	require.NoError(t, json.NewDecoder(object).Decode(&waveform))
+	object.Close()
+	// Half a second at 100 pixels a second
+	require.Equal(t, 50, waveform.Length)
+	require.Equal(t, 80, waveform.SamplesPerPixel)
+
+	// Dictations without stored audio are skipped
+	linked := db.Dictation{ID: 44, AudioUrl: sql.NullString{String: "https://example.com/a.mp3", Valid: true}}
+	store.EXPECT().GetDictation(gomock.Any(), linked.ID).Return(linked, nil)
+	require.NoError(t, runner.GenerateWaveform(context.Background(), linked.ID))
+}
+
+func TestGenerateSpeedVariant(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, blobs := newTestRunner(t, store)
+
+	// One second of silence
+	recording := audio.EncodeWAV(audio.PCM{SampleRate: 8000, Channels: 1, BitsPerSample: 16, Data: make([]byte, 16000)})
+	uploaded := db.Dictation{
+		ID:       43,
+		Type:     sql.NullString{String: "audio", Valid: true},
+		AudioKey: sql.NullString{String: "uploads/7/passage.wav", Valid: true},
+	}
+	require.NoError(t, blobs.Put(context.Background(), uploaded.AudioKey.String, bytes.NewReader(recording), int64(len(recording)), "audio/wav"))
+	store.EXPECT().GetDictation(gomock.Any(), uploaded.ID).Times(2).Return(uploaded, nil)
+
+	require.NoError(t, runner.GenerateSpeedVariant(context.Background(), uploaded.ID, 0.5))
+	object, err := blobs.Open(context.Background(), "uploads/7/passage.x0.50.wav")
+	require.NoError(t, err)
+	data, err := io.ReadAll(object)
+	object.Close()
+	require.NoError(t, err)
+	duration, err := audio.WAVDuration(data)
+	require.NoError(t, err)
+	require.InDelta(t, 2*time.Second, duration, float64(10*time.Millisecond))
+
+	// Audio too long to stretch is left alone
+	huge := make([]byte, MaxStretchBytes+1)
+	copy(huge, recording)
+	require.NoError(t, blobs.Put(context.Background(), uploaded.AudioKey.String, bytes.NewReader(huge), int64(len(huge)), "audio/wav"))
+	require.ErrorIs(t, runner.GenerateSpeedVariant(context.Background(), uploaded.ID, 1.5), ErrTooLongToStretch)
+	_, err = blobs.Open(context.Background(), "uploads/7/passage.x1.50.wav")
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGenerateAudioFailure(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attempts   int32
+		wantStatus string
+	}{
+		{name: "Retry", attempts: 0, wantStatus: AudioPending},
+		{name: "GiveUp", attempts: 2, wantStatus: AudioFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			store := mockdb.NewMockStore(ctrl)
+			runner, _ := newTestRunner(t, store)
+
+			d := pendingDictation("this will fail", tc.attempts)
+			store.EXPECT().GetDictation(gomock.Any(), d.ID).Return(d, nil)
+			store.EXPECT().GetSettingByUserID(gomock.Any(), d.UserID).Return(db.Setting{}, sql.ErrNoRows)
+			store.EXPECT().
+				FailDictationAudio(gomock.Any(), db.FailDictationAudioParams{
+					ID:          d.ID,
+					AudioStatus: sql.NullString{String: tc.wantStatus, Valid: true},
+					AudioError:  sql.NullString{String: "provider unavailable", Valid: true},
+				}).
+				DoAndReturn(func(_ context.Context, arg db.FailDictationAudioParams) (db.Dictation, error) {
+					d.AudioStatus = arg.AudioStatus
+					d.AudioError = arg.AudioError
+					d.AudioAttempts++
+					return d, nil
+				})
+
+			failed, err := runner.GenerateAudio(context.Background(), d.ID)
+			require.EqualError(t, err, "provider unavailable")
+			require.Equal(t, tc.wantStatus, failed.AudioStatus.String)
+		})
+	}
+}
+
+func TestGenerateAudioStale(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, blobs := newTestRunner(t, store)
+
+	// The content changes before the audio is saved
+	d := pendingDictation("the quick brown fox", 0)
+	var key string
+	store.EXPECT().GetDictation(gomock.Any(), d.ID).Return(d, nil)
+	store.EXPECT().GetSettingByUserID(gomock.Any(), d.UserID).Return(db.Setting{}, sql.ErrNoRows)
+	store.EXPECT().
+		CompleteDictationAudio(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, arg db.CompleteDictationAudioParams) (db.Dictation, error) {
+			key = arg.AudioKey.String
+			return db.Dictation{}, sql.ErrNoRows
+		})
+
+	_, err := runner.GenerateAudio(context.Background(), d.ID)
+	require.NoError(t, err)
+	_, err = blobs.Open(context.Background(), key)
+	require.ErrorIs(t, err, storage.ErrNotFound)
+}
+
+func TestGenerateAudioSkips(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, _ := newTestRunner(t, store)
+
+	ready := pendingDictation("the quick brown fox", 1)
+	ready.AudioStatus.String = AudioReady
+	store.EXPECT().GetDictation(gomock.Any(), ready.ID).Return(ready, nil)
+	store.EXPECT().GetDictation(gomock.Any(), int64(99)).Return(db.Dictation{}, sql.ErrNoRows)
+
+	got, err := runner.GenerateAudio(context.Background(), ready.ID)
+	require.NoError(t, err)
+	require.Equal(t, ready, got)
+	_, err = runner.GenerateAudio(context.Background(), 99)
+	require.NoError(t, err)
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	store := mockdb.NewMockStore(ctrl)
+	runner, _ := newTestRunner(t, store)
+
+	// A dictation left pending by the last process is picked up
+	d := pendingDictation("the quick brown fox", 0)
+	done := make(chan struct{})
+	store.EXPECT().
+		ListDictationsByAudioStatus(gomock.Any(), sql.NullString{String: AudioPending, Valid: true}).
+		Return([]db.Dictation{d}, nil)
+	store.EXPECT().GetDictation(gomock.Any(), d.ID).Return(d, nil)
+	store.EXPECT().GetSettingByUserID(gomock.Any(), d.UserID).Return(db.Setting{}, sql.ErrNoRows)
+	store.EXPECT().
+		CompleteDictationAudio(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(context.Context, db.CompleteDictationAudioParams) (db.Dictation, error) {
+			close(done)
+			return d, nil
+		})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- runner.Run(ctx) }()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("pending dictation was not processed")
+	}
+	cancel()
+	require.ErrorIs(t, <-stopped, context.Canceled)
+}
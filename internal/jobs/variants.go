@@ -0,0 +1,44 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	"github.com/nilesh0729/PixelScribe/internal/storage"
+)
+
+// WaveformVariant is the suffix, for storage.VariantKey, of the stored
+// waveform of a dictation's audio.
+const WaveformVariant = "waveform.json"
+
+// SpeedVariant is the suffix, for storage.VariantKey, of the stored copy of
+// a dictation's audio time-stretched to play at speed.
+func SpeedVariant(speed float64) string {
+	return fmt.Sprintf("x%.2f.wav", speed)
+}
+
+// MaxStretchBytes is the largest audio file GenerateSpeedVariant stretches.
+// Stretching holds the audio as 64-bit samples several times over, so a
+// file this size takes a few hundred megabytes while it is worked on.
+const MaxStretchBytes = 32 << 20
+
+// ErrTooLongToStretch is returned by GenerateSpeedVariant for audio over
+// MaxStretchBytes.
+var ErrTooLongToStretch = fmt.Errorf("audio over %d MiB cannot be played at another speed", MaxStretchBytes>>20)
+
+// variants lists the suffixes of every blob that may be derived from a
+// dictation's audio.
+func variants() []string {
+	suffixes := []string{WaveformVariant}
+	for _, speed := range audio.Speeds() {
+		suffixes = append(suffixes, SpeedVariant(speed))
+	}
+	return suffixes
+}
+
+// DeleteAudio removes a dictation's stored audio and everything derived
+// from it.
+func DeleteAudio(ctx context.Context, blobs storage.Store, key string) error {
+	return storage.DeleteWithVariants(ctx, blobs, key, variants()...)
+}
@@ -0,0 +1,66 @@
+// Package legacyfont converts text typed with legacy 8-bit Hindi fonts, such
+// as Kruti Dev and DevLys, into Unicode Devanagari.
+//
+// Those fonts draw Devanagari glyphs over Latin-1 code points, so text typed
+// with them is stored as Latin letters and symbols ("Hkkjr" is shown as
+// भारत). Converting maps every glyph to its Unicode sequence and then
+// reorders the two marks that the fonts type out of phonetic order: the short
+// i matra (typed before its consonant) and the reph (typed after its
+// syllable).
+package legacyfont
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Encoding names the encoding of typed text.
+type Encoding string
+
+const (
+	Unicode  Encoding = "unicode"
+	KrutiDev Encoding = "krutidev"
+	// DevLys 010 uses the same Remington layout and glyph positions as
+	// Kruti Dev 010.
+	DevLys Encoding = "devlys"
+)
+
+// Encodings lists the supported encodings.
+func Encodings() []Encoding {
+	return []Encoding{Unicode, KrutiDev, DevLys}
+}
+
+// Parse returns the encoding called name. Names are case-insensitive and may
+// carry the font's version number, e.g. "Kruti Dev 010". An empty name is
+// Unicode.
+func Parse(name string) (Encoding, error) {
+	key := strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(name))
+	key = strings.TrimSuffix(key, "010")
+
+	switch key {
+	case "", string(Unicode):
+		return Unicode, nil
+	case string(KrutiDev):
+		return KrutiDev, nil
+	case string(DevLys):
+		return DevLys, nil
+	}
+	return "", fmt.Errorf("unsupported input encoding %q", name)
+}
+
+// ToUnicode converts text from enc to Unicode. Unicode text is returned
+// unchanged.
+func ToUnicode(text string, enc Encoding) (string, error) {
+	switch enc {
+	case Unicode:
+		return text, nil
+	case KrutiDev, DevLys:
+		return remington.convert(text), nil
+	}
+	return "", fmt.Errorf("unsupported input encoding %q", enc)
+}
@@ -0,0 +1,79 @@
+package legacyfont
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestKrutiDevToUnicode(t *testing.T) {
+	testCases := []struct {
+		legacy string
+		want   string
+	}{
+		{legacy: "Hkkjr", want: "भारत"},
+		{legacy: "vkSj", want: "और"},
+		{legacy: "esa gS", want: "में है"},
+		// Short i is typed before its consonant
+		{legacy: "ifjokj", want: "परिवार"},
+		{legacy: "fgUnh", want: "हिन्दी"},
+		// ...and before the whole conjunct
+		{legacy: "fLFkfr", want: "स्थिति"},
+		{legacy: "fo|ky;", want: "विद्यालय"},
+		// Reph is typed after its syllable, matras included
+		{legacy: "dk;Z", want: "कार्य"},
+		{legacy: "/keZ", want: "धर्म"},
+		{legacy: "fdZ", want: "र्कि"},
+		{legacy: "Lo:i", want: "स्वरूप"},
+		{legacy: "{k=h", want: "क्षत्री"},
+		{legacy: "Hkk\"kk A", want: "भाषा ।"},
+		// Nukta forms come out NFC-normalized
+		{legacy: "t+:j", want: "ज़रूर"},
+		{legacy: "2024", want: "2024"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.legacy, func(t *testing.T) {
+			got, err := ToUnicode(tc.legacy, KrutiDev)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+
+			got, err = ToUnicode(tc.legacy, DevLys)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestUnicodeUnchanged(t *testing.T) {
+	got, err := ToUnicode("fast कार्य", Unicode)
+	require.NoError(t, err)
+	require.Equal(t, "fast कार्य", got)
+}
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		want    Encoding
+		wantErr bool
+	}{
+		{name: "", want: Unicode},
+		{name: "UNICODE", want: Unicode},
+		{name: "krutidev", want: KrutiDev},
+		{name: "Kruti Dev 010", want: KrutiDev},
+		{name: "devlys_010", want: DevLys},
+		{name: "shivaji", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(tc.name)
+			if tc.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
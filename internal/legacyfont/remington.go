@@ -0,0 +1,220 @@
+package legacyfont
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	halant  = '्'
+	nukta   = '़'
+	shortI  = 'ि'
+	legacyI = 'f' // short i matra, typed before its consonant
+	reph    = 'Z' // half ra above the syllable, typed after it
+)
+
+// remington is the glyph table shared by Kruti Dev 010 and DevLys 010.
+var remington = newGlyphTable([][2]string{
+	// Vowels
+	{"v", "अ"}, {"vk", "आ"}, {"b", "इ"}, {"bZ", "ई"}, {"b±", "ईं"},
+	{"m", "उ"}, {"Å", "ऊ"}, {"_", "ऋ"}, {",", "ए"}, {",s", "ऐ"},
+	{"vks", "ओ"}, {"vkS", "औ"}, {"v‚", "ऑ"},
+
+	// Consonants; a half form followed by k is the full form
+	{"d", "क"}, {"D", "क्"}, {"Dk", "क"},
+	{"[k", "ख"}, {"[", "ख्"},
+	{"x", "ग"}, {"X", "ग्"}, {"Xk", "ग"},
+	{"?k", "घ"}, {"?", "घ्"}, {"Ä", "घ"},
+	{"³", "ङ"},
+	{"p", "च"}, {"P", "च्"}, {"Pk", "च"},
+	{"N", "छ"},
+	{"t", "ज"}, {"T", "ज्"}, {"Tk", "ज"},
+	{">", "झ"}, {"÷", "झ्"},
+	{"¥", "ञ"},
+	{"V", "ट"}, {"B", "ठ"}, {"M", "ड"}, {"<", "ढ"},
+	{".k", "ण"}, {".", "ण्"},
+	{"r", "त"}, {"R", "त्"}, {"Rk", "त"},
+	{"Fk", "थ"}, {"F", "थ्"},
+	{"n", "द"},
+	{"/k", "ध"}, {"/", "ध्"}, {"èk", "ध"}, {"è", "ध्"}, {"Ë", "ध्"},
+	{"u", "न"}, {"U", "न्"}, {"Uk", "न"},
+	{"i", "प"}, {"I", "प्"}, {"Ik", "प"},
+	{"Q", "फ"}, {"¶", "फ्"},
+	{"c", "ब"}, {"C", "ब्"}, {"Ck", "ब"},
+	{"Hk", "भ"}, {"H", "भ्"},
+	{"e", "म"}, {"E", "म्"}, {"Ek", "म"},
+	{";", "य"}, {"¸", "य्"},
+	{"j", "र"},
+	{"y", "ल"}, {"Y", "ल्"}, {"Yk", "ल"},
+	{"G", "ळ"},
+	{"o", "व"}, {"O", "व्"}, {"Ok", "व"},
+	{"'k", "श"}, {"'", "श्"},
+	{`"k`, "ष"}, {`"`, "ष्"},
+	{"l", "स"}, {"L", "स्"}, {"Lk", "स"},
+	{"g", "ह"},
+
+	// Consonants with nukta
+	{"d+", "क़"}, {"[+k", "ख़"}, {"[+", "ख़्"}, {"x+", "ग़"},
+	{"t+", "ज़"}, {"T+", "ज़्"}, {"M+", "ड़"}, {"<+", "ढ़"},
+	{"Q+", "फ़"}, {"¶+", "फ़्"}, {";+", "य़"}, {"j+", "ऱ"}, {"u+", "ऩ"},
+
+	// Conjuncts
+	{"{k", "क्ष"}, {"{", "क्ष्"},
+	{"=", "त्र"}, {"«", "त्र्"},
+	{"K", "ज्ञ"}, {"J", "श्र"},
+	{"Ø", "क्र"}, {"Ý", "फ्र"}, {"æ", "द्र"}, {"ç", "प्र"}, {"Á", "प्र"}, {"xz", "ग्र"},
+	{"#", "रु"}, {":", "रू"},
+	{"ô", "क्क"}, {"ä", "क्त"}, {"—", "कृ"},
+	{"Ùk", "त्त"}, {"Ù", "त्त्"},
+	{")", "द्ध"}, {"í", "द्द"}, {"|", "द्य"}, {"}", "द्व"}, {"–", "दृ"},
+	{"é", "न्न"}, {"™", "न्न्"},
+	{"ê", "ट्ट"}, {"ë", "ट्ठ"}, {"ì", "ड्ड"}, {"ï", "ड्ढ"},
+	{"Vî", "ट्य"}, {"Bî", "ठ्य"}, {"Mî", "ड्य"}, {"<î", "ढ्य"}, {"Nî", "छ्य"},
+	{"Vª", "ट्र"}, {"Mª", "ड्र"}, {"<ª", "ढ्र"}, {"Nª", "छ्र"},
+	{"à", "ह्न"}, {"á", "ह्य"}, {"â", "हृ"}, {"ã", "ह्म"}, {"ºz", "ह्र"}, {"º", "ह्"},
+
+	// Matras and signs
+	{"k", "ा"}, {"h", "ी"}, {"q", "ु"}, {"w", "ू"}, {"`", "ृ"},
+	{"s", "े"}, {"S", "ै"}, {"ks", "ो"}, {"kS", "ौ"}, {"‚", "ॉ"}, {"W", "ॅ"},
+	{"a", "ं"}, {"¡", "ँ"}, {"%", "ः"}, {"È", "ीं"},
+	{"~", "्"}, {"z", "्र"}, {"+", "़"}, {"·", "ऽ"}, {"ñ", "॰"},
+
+	// Digits
+	{"å", "०"}, {"ƒ", "१"}, {"„", "२"}, {"…", "३"}, {"†", "४"},
+	{"‡", "५"}, {"ˆ", "६"}, {"‰", "७"}, {"Š", "८"}, {"‹", "९"},
+
+	// Punctuation moved off the keys used for letters
+	{"A", "।"}, {"-", "."}, {"&", "-"}, {"]", ","}, {`\`, "?"}, {"@", "/"},
+	{"(", ";"}, {"¼", "("}, {"½", ")"}, {"¿", "{"}, {"À", "}"}, {"¾", "="},
+	{"^", "‘"}, {"*", "’"}, {"Þ", "“"}, {"ß", "”"},
+})
+
+// glyphTable maps legacy glyph sequences to Unicode.
+type glyphTable struct {
+	glyphs map[string]string
+	// longest is the length in runes of the longest glyph sequence.
+	longest int
+}
+
+func newGlyphTable(pairs [][2]string) *glyphTable {
+	t := &glyphTable{glyphs: make(map[string]string, len(pairs))}
+	for _, p := range pairs {
+		if _, dup := t.glyphs[p[0]]; dup {
+			panic("legacyfont: glyph mapped twice: " + p[0])
+		}
+		t.glyphs[p[0]] = p[1]
+		t.longest = max(t.longest, len([]rune(p[0])))
+	}
+	return t
+}
+
+func (t *glyphTable) convert(text string) string {
+	runes := mapGlyphs(t, []rune(text))
+	runes = placeShortI(runes)
+	runes = placeReph(runes)
+	return norm.NFC.String(string(runes))
+}
+
+// mapGlyphs replaces glyph sequences with their Unicode text, always taking
+// the longest sequence that matches. Characters not in the table (spaces,
+// digits, f and Z) are kept.
+func mapGlyphs(t *glyphTable, in []rune) []rune {
+	out := make([]rune, 0, len(in))
+	for i := 0; i < len(in); {
+		matched := false
+		for n := min(t.longest, len(in)-i); n > 0; n-- {
+			if u, ok := t.glyphs[string(in[i:i+n])]; ok {
+				out = append(out, []rune(u)...)
+				i += n
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			out = append(out, in[i])
+			i++
+		}
+	}
+	return out
+}
+
+// placeShortI moves each short i matra from before its consonant cluster to
+// after it, e.g. "fLFk" (f स् थ) becomes स्थि.
+func placeShortI(runes []rune) []rune {
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != legacyI {
+			continue
+		}
+		end := clusterEnd(runes, i+1)
+		if end == i+1 {
+			// Not followed by a consonant, so it's a literal f
+			continue
+		}
+		copy(runes[i:], runes[i+1:end])
+		runes[end-1] = shortI
+		i = end - 1
+	}
+	return runes
+}
+
+// placeReph replaces each reph marker with र् placed before the consonant
+// cluster it follows, skipping back over the cluster's matras, e.g. "dk;Z"
+// (क ा य Z) becomes कार्य.
+func placeReph(runes []rune) []rune {
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != reph {
+			continue
+		}
+		start := i
+		for start > 0 && isSign(runes[start-1]) {
+			start--
+		}
+		if start == 0 || !isConsonant(runes[start-1]) {
+			// Nothing to attach to, so it's a literal Z
+			continue
+		}
+		start--
+		for start >= 2 && runes[start-1] == halant && isConsonant(runes[start-2]) {
+			start -= 2
+		}
+
+		out := make([]rune, 0, len(runes)+1)
+		out = append(out, runes[:start]...)
+		out = append(out, 'र', halant)
+		out = append(out, runes[start:i]...)
+		out = append(out, runes[i+1:]...)
+		runes = out
+		i++
+	}
+	return runes
+}
+
+// clusterEnd returns the index just past the consonant cluster starting at i:
+// a consonant, optionally with nukta, joined by halants to further
+// consonants. It returns i if runes[i] is not a consonant.
+func clusterEnd(runes []rune, i int) int {
+	end := i
+	for end < len(runes) && isConsonant(runes[end]) {
+		end++
+		if end < len(runes) && runes[end] == nukta {
+			end++
+		}
+		if end+1 < len(runes) && runes[end] == halant && isConsonant(runes[end+1]) {
+			end++
+			continue
+		}
+		break
+	}
+	return end
+}
+
+func isConsonant(r rune) bool {
+	return (r >= 'क' && r <= 'ह') || (r >= '\u0958' && r <= '\u095f')
+}
+
+// isSign reports whether r is a matra or other sign written after a
+// consonant, which a reph skips over.
+func isSign(r rune) bool {
+	return strings.ContainsRune("ािीुूृॄेैोौॅॉंँः़", r)
+}
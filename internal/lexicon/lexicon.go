@@ -0,0 +1,252 @@
+// Package lexicon turns written text into the words a reader says aloud.
+// Speech synthesis reads its input through Verbalize so that abbreviations,
+// numbers and names come out the same way every time, and scoring uses the
+// replacements Verbalize made to accept what the learner actually heard.
+package lexicon
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Limits on a pronunciation lexicon.
+const (
+	// MaxEntries caps the entries of one lexicon.
+	MaxEntries = 500
+	// MaxWords is the longest written or spoken form an entry may have.
+	MaxWords = 8
+)
+
+// Entry says how a written word or phrase is read, such as "Dr." as
+// "Doctor" or "Nguyen" as "Win".
+type Entry struct {
+	Written string `json:"written"`
+	Spoken  string `json:"spoken"`
+}
+
+// Parse decodes a stored lexicon. Empty input yields nil.
+func Parse(data []byte) ([]Entry, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid lexicon: %w", err)
+	}
+	return entries, nil
+}
+
+// Validate checks that every entry has a written and a spoken form of at
+// most MaxWords words and that no written form is listed twice, ignoring
+// case.
+func Validate(entries []Entry) error {
+	if len(entries) > MaxEntries {
+		return fmt.Errorf("at most %d lexicon entries are allowed", MaxEntries)
+	}
+	seen := make(map[string]bool, len(entries))
+	for i, e := range entries {
+		written, spoken := strings.Fields(e.Written), strings.Fields(e.Spoken)
+		if len(written) == 0 || len(spoken) == 0 {
+			return fmt.Errorf("lexicon entry %d needs a written and a spoken form", i)
+		}
+		if len(written) > MaxWords || len(spoken) > MaxWords {
+			return fmt.Errorf("lexicon entry %d is longer than %d words", i, MaxWords)
+		}
+		if lead, _, _ := splitPunct(written[0]); lead != "" {
+			return fmt.Errorf("lexicon entry %d starts with punctuation", i)
+		}
+		key := normalize(e.Written)
+		if seen[key] {
+			return fmt.Errorf("lexicon lists %q twice", e.Written)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// abbreviations are read out in English text unless a lexicon says
+// otherwise.
+var abbreviations = []Entry{
+	{"Dr.", "Doctor"},
+	{"Mr.", "Mister"},
+	{"Mrs.", "Missus"},
+	{"Ms.", "Miz"},
+	{"Prof.", "Professor"},
+	{"Jr.", "Junior"},
+	{"Sr.", "Senior"},
+	{"Capt.", "Captain"},
+	{"Gen.", "General"},
+	{"Gov.", "Governor"},
+	{"Sen.", "Senator"},
+	{"Rev.", "Reverend"},
+	{"Mt.", "Mount"},
+	{"Ave.", "Avenue"},
+	{"Dept.", "Department"},
+	{"Govt.", "Government"},
+	{"Inc.", "Incorporated"},
+	{"Ltd.", "Limited"},
+	{"Corp.", "Corporation"},
+	{"etc.", "et cetera"},
+	{"e.g.", "for example"},
+	{"i.e.", "that is"},
+	{"vs.", "versus"},
+	{"approx.", "approximately"},
+	{"Jan.", "January"},
+	{"Feb.", "February"},
+	{"Aug.", "August"},
+	{"Sept.", "September"},
+	{"Oct.", "October"},
+	{"Nov.", "November"},
+	{"Dec.", "December"},
+}
+
+// rule is a lexicon entry ready for matching.
+type rule struct {
+	written string
+	words   int
+	spoken  string
+}
+
+// match reports whether words start with r's written form, apart from
+// punctuation before it and after it. It returns that punctuation and the
+// written form as it appears in words.
+func (r rule) match(words []string) (lead, written, trail string, ok bool) {
+	if len(words) < r.words {
+		return "", "", "", false
+	}
+	lead, _, _ = splitPunct(words[0])
+	text := strings.Join(words[:r.words], " ")[len(lead):]
+	n := len(r.written)
+	if len(text) < n || strings.ToLower(text[:n]) != r.written {
+		return "", "", "", false
+	}
+	trail = text[n:]
+	if strings.IndexFunc(trail, func(r rune) bool { return !unicode.IsPunct(r) }) >= 0 {
+		return "", "", "", false
+	}
+	return lead, text[:n], trail, true
+}
+
+// compile indexes the entries of lexicons by the first word of their written
+// form, longest entries first. An entry in an earlier lexicon hides one for
+// the same written form in a later lexicon.
+func compile(lexicons [][]Entry) map[string][]rule {
+	rules := make(map[string][]rule)
+	seen := make(map[string]bool)
+	for _, lexicon := range lexicons {
+		for _, e := range lexicon {
+			written := normalize(e.Written)
+			spoken := strings.Join(strings.Fields(e.Spoken), " ")
+			if written == "" || spoken == "" || seen[written] {
+				continue
+			}
+			seen[written] = true
+			words := strings.Fields(written)
+			_, first, _ := splitPunct(words[0])
+			rules[first] = append(rules[first], rule{written: written, words: len(words), spoken: spoken})
+		}
+	}
+	for _, rs := range rules {
+		sort.SliceStable(rs, func(i, j int) bool { return rs[i].words > rs[j].words })
+	}
+	return rules
+}
+
+var wordPattern = regexp.MustCompile(`\S+`)
+
+// Verbalize rewrites text the way it should be read aloud. Words and phrases
+// found in the lexicons are replaced by their spoken forms, ignoring case and
+// the punctuation around them; earlier lexicons win over later ones and
+// longer phrases over shorter ones. English text also has common
+// abbreviations, numbers, years, ordinals, percentages and amounts of money
+// spelled out. The language is a BCP 47 tag; when it is empty, text written
+// only in Latin script counts as English.
+//
+// Verbalize returns the spoken text and each replacement it made, with the
+// written form as it appears in text.
+func Verbalize(text, language string, lexicons ...[]Entry) (string, []Entry) {
+	english := isEnglish(language, text)
+	if english {
+		lexicons = append(lexicons[:len(lexicons):len(lexicons)], abbreviations)
+	}
+	rules := compile(lexicons)
+
+	spans := wordPattern.FindAllStringIndex(text, -1)
+	words := make([]string, len(spans))
+	for i, s := range spans {
+		words[i] = text[s[0]:s[1]]
+	}
+
+	var out strings.Builder
+	var forms []Entry
+	last := 0
+	for i := 0; i < len(words); {
+		lead, written, spoken, trail, n := replace(rules, english, words[i:])
+		if n == 0 {
+			i++
+			continue
+		}
+		out.WriteString(text[last:spans[i][0]])
+		out.WriteString(lead + spoken + trail)
+		forms = append(forms, Entry{Written: written, Spoken: spoken})
+		last = spans[i+n-1][1]
+		i += n
+	}
+	out.WriteString(text[last:])
+	return out.String(), forms
+}
+
+// replace finds the replacement for the phrase words start with, returning
+// how many words it spans or 0 if they are read as written.
+func replace(rules map[string][]rule, english bool, words []string) (lead, written, spoken, trail string, n int) {
+	_, core, _ := splitPunct(words[0])
+	for _, r := range rules[strings.ToLower(core)] {
+		if lead, written, trail, ok := r.match(words); ok {
+			return lead, written, r.spoken, trail, r.words
+		}
+	}
+	if english {
+		if lead, written, spoken, trail, ok := number(words[0]); ok {
+			return lead, written, spoken, trail, 1
+		}
+	}
+	return "", "", "", "", 0
+}
+
+// isEnglish reports whether text in language gets English verbalization.
+func isEnglish(language, text string) bool {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	if primary != "" {
+		return primary == "en"
+	}
+	for _, r := range text {
+		if unicode.IsLetter(r) && !unicode.Is(unicode.Latin, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// normalize lower-cases a form and collapses its white space.
+func normalize(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// splitPunct separates the punctuation before and after a word from its
+// core, the same way scoring does.
+func splitPunct(word string) (lead, core, trail string) {
+	runes := []rune(word)
+	i, j := 0, len(runes)
+	for i < j && unicode.IsPunct(runes[i]) {
+		i++
+	}
+	for j > i && unicode.IsPunct(runes[j-1]) {
+		j--
+	}
+	return string(runes[:i]), string(runes[i:j]), string(runes[j:])
+}
@@ -0,0 +1,136 @@
+package lexicon
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerbalize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		language string
+		lexicons [][]Entry
+		spoken   string
+		forms    []Entry
+	}{
+		{
+			name:   "Abbreviations",
+			text:   "Dr. Rao met Mrs. Iyer, e.g. on Tuesdays.",
+			spoken: "Doctor Rao met Missus Iyer, for example on Tuesdays.",
+			forms: []Entry{
+				{Written: "Dr.", Spoken: "Doctor"},
+				{Written: "Mrs.", Spoken: "Missus"},
+				{Written: "e.g.", Spoken: "for example"},
+			},
+		},
+		{
+			name:   "AbbreviationEndsSentence",
+			text:   "pens, paper, etc. Then ink",
+			spoken: "pens, paper, et cetera Then ink",
+			forms:  []Entry{{Written: "etc.", Spoken: "et cetera"}},
+		},
+		{
+			name:   "Numbers",
+			text:   "In 1998 about 1,250 people paid $5 for the 21st show, up 50%.",
+			spoken: "In nineteen ninety-eight about one thousand two hundred fifty people paid five dollars for the twenty-first show, up fifty percent.",
+			forms: []Entry{
+				{Written: "1998", Spoken: "nineteen ninety-eight"},
+				{Written: "1,250", Spoken: "one thousand two hundred fifty"},
+				{Written: "$5", Spoken: "five dollars"},
+				{Written: "21st", Spoken: "twenty-first"},
+				{Written: "50%", Spoken: "fifty percent"},
+			},
+		},
+		{
+			name:   "Years",
+			text:   "1905 2005 2024 1900",
+			spoken: "nineteen oh five two thousand five twenty twenty-four nineteen hundred",
+			forms: []Entry{
+				{Written: "1905", Spoken: "nineteen oh five"},
+				{Written: "2005", Spoken: "two thousand five"},
+				{Written: "2024", Spoken: "twenty twenty-four"},
+				{Written: "1900", Spoken: "nineteen hundred"},
+			},
+		},
+		{
+			name:   "Decimal",
+			text:   "3.05 kg",
+			spoken: "three point zero five kg",
+			forms:  []Entry{{Written: "3.05", Spoken: "three point zero five"}},
+		},
+		{
+			name:     "UserLexicon",
+			text:     "Ms. Nguyen flew to new  York.",
+			lexicons: [][]Entry{{{Written: "Nguyen", Spoken: "Win"}, {Written: "New York", Spoken: "New York City"}}},
+			spoken:   "Miz Win flew to New York City.",
+			forms: []Entry{
+				{Written: "Ms.", Spoken: "Miz"},
+				{Written: "Nguyen", Spoken: "Win"},
+				{Written: "new York", Spoken: "New York City"},
+			},
+		},
+		{
+			name: "EarlierLexiconWins",
+			text: "Dr. Jones",
+			lexicons: [][]Entry{
+				{{Written: "dr.", Spoken: "Drive"}},
+				{{Written: "Dr.", Spoken: "Doc"}},
+			},
+			spoken: "Drive Jones",
+			forms:  []Entry{{Written: "Dr.", Spoken: "Drive"}},
+		},
+		{
+			name:     "LongestPhraseWins",
+			text:     "St. Louis and St. Paul",
+			lexicons: [][]Entry{{{Written: "St.", Spoken: "Street"}, {Written: "St. Louis", Spoken: "Saint Louis"}}},
+			spoken:   "Saint Louis and Street Paul",
+			forms: []Entry{
+				{Written: "St. Louis", Spoken: "Saint Louis"},
+				{Written: "St.", Spoken: "Street"},
+			},
+		},
+		{
+			name:     "WholeWordsOnly",
+			text:     "Drew drove",
+			lexicons: [][]Entry{{{Written: "dr", Spoken: "doctor"}}},
+			spoken:   "Drew drove",
+		},
+		{
+			name:     "NotEnglish",
+			text:     "Dr. 1998",
+			language: "fr",
+			spoken:   "Dr. 1998",
+		},
+		{
+			name:     "OtherScriptLexiconOnly",
+			text:     "डॉ. शर्मा 1998",
+			lexicons: [][]Entry{{{Written: "डॉ.", Spoken: "डॉक्टर"}}},
+			spoken:   "डॉक्टर शर्मा 1998",
+			forms:    []Entry{{Written: "डॉ.", Spoken: "डॉक्टर"}},
+		},
+		{
+			name:   "NotANumber",
+			text:   "call 5:30 or 123456789012345",
+			spoken: "call 5:30 or 123456789012345",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			spoken, forms := Verbalize(tc.text, tc.language, tc.lexicons...)
+			require.Equal(t, tc.spoken, spoken)
+			require.Equal(t, tc.forms, forms)
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	require.NoError(t, Validate([]Entry{{Written: "Nguyen", Spoken: "Win"}, {Written: "Dr.", Spoken: "Doctor"}}))
+	require.Error(t, Validate([]Entry{{Written: "Nguyen", Spoken: " "}}))
+	require.Error(t, Validate([]Entry{{Written: ".NET", Spoken: "dot net"}}))
+	require.Error(t, Validate([]Entry{{Written: "Dr.", Spoken: "Doctor"}, {Written: "dr.", Spoken: "Drive"}}))
+	require.Error(t, Validate([]Entry{{Written: strings.Repeat("word ", MaxWords+1), Spoken: "words"}}))
+}
@@ -0,0 +1,207 @@
+package lexicon
+
+import (
+	"strconv"
+	"strings"
+)
+
+var (
+	smallNumbers = []string{
+		"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine",
+		"ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen", "sixteen",
+		"seventeen", "eighteen", "nineteen",
+	}
+	tensNumbers = []string{"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy", "eighty", "ninety"}
+	ordinals    = map[string]string{
+		"one": "first", "two": "second", "three": "third", "five": "fifth",
+		"eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+	// currencies maps a currency symbol to the singular and plural name of
+	// its unit.
+	currencies = map[string][2]string{
+		"$": {"dollar", "dollars"},
+		"£": {"pound", "pounds"},
+		"€": {"euro", "euros"},
+		"₹": {"rupee", "rupees"},
+	}
+)
+
+// maxNumber is the largest number spelled out; longer digit strings are
+// usually codes and phone numbers that are better read digit by digit.
+const maxNumber = 999_999_999_999
+
+// number spells out a word that is a number in English: "1998" as a year,
+// "1,250" and "3.5" as numbers, "21st" as an ordinal, "$5" and "₹200" as
+// amounts and "50%" as a percentage. It returns the punctuation around the
+// number and the number as written.
+func number(word string) (lead, written, spoken, trail string, ok bool) {
+	lead, core, trail := splitPunct(word)
+	written = core
+	percent := strings.HasPrefix(trail, "%")
+	if percent {
+		trail = trail[1:]
+		written += "%"
+	}
+	var unit [2]string
+	for symbol, names := range currencies {
+		if strings.HasPrefix(core, symbol) {
+			core, unit = core[len(symbol):], names
+			break
+		}
+	}
+	if unit[0] != "" && percent {
+		return "", "", "", "", false
+	}
+
+	switch {
+	case isOrdinal(core):
+		n, ok := parseDigits(core[:len(core)-2])
+		if !ok || unit[0] != "" || percent {
+			return "", "", "", "", false
+		}
+		spoken = ordinal(n)
+	case strings.Contains(core, "."):
+		whole, fraction, _ := strings.Cut(core, ".")
+		n, ok := parseDigits(whole)
+		if !ok || !allDigits(fraction) {
+			return "", "", "", "", false
+		}
+		spoken = cardinal(n) + " point"
+		for _, d := range fraction {
+			spoken += " " + smallNumbers[d-'0']
+		}
+		if unit[0] != "" {
+			spoken += " " + unit[1]
+		}
+	default:
+		n, ok := parseDigits(core)
+		if !ok {
+			return "", "", "", "", false
+		}
+		switch {
+		case unit[0] != "" && n == 1:
+			spoken = "one " + unit[0]
+		case unit[0] != "":
+			spoken = cardinal(n) + " " + unit[1]
+		case !percent && len(core) == 4 && n >= 1100 && n < 2100:
+			spoken = year(n)
+		default:
+			spoken = cardinal(n)
+		}
+	}
+	if percent {
+		spoken += " percent"
+	}
+	return lead, written, spoken, trail, true
+}
+
+// parseDigits reads a whole number written in digits with optional
+// thousands separators.
+func parseDigits(s string) (int64, bool) {
+	if strings.Contains(s, ",") {
+		groups := strings.Split(s, ",")
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return 0, false
+			}
+		}
+		s = strings.Join(groups, "")
+	}
+	if !allDigits(s) {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > maxNumber {
+		return 0, false
+	}
+	return n, true
+}
+
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+func isOrdinal(s string) bool {
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if strings.HasSuffix(s, suffix) || strings.HasSuffix(s, strings.ToUpper(suffix)) {
+			return len(s) > 2
+		}
+	}
+	return false
+}
+
+// cardinal spells out n, such as 1250 as "one thousand two hundred fifty".
+func cardinal(n int64) string {
+	if n == 0 {
+		return "zero"
+	}
+	var parts []string
+	for _, scale := range []struct {
+		value int64
+		name  string
+	}{{1_000_000_000, "billion"}, {1_000_000, "million"}, {1_000, "thousand"}, {1, ""}} {
+		if n < scale.value {
+			continue
+		}
+		parts = append(parts, belowThousand(int(n/scale.value)))
+		if scale.name != "" {
+			parts = append(parts, scale.name)
+		}
+		n %= scale.value
+	}
+	return strings.Join(parts, " ")
+}
+
+func belowThousand(n int) string {
+	var parts []string
+	if n >= 100 {
+		parts = append(parts, smallNumbers[n/100], "hundred")
+		n %= 100
+	}
+	switch {
+	case n >= 20 && n%10 != 0:
+		parts = append(parts, tensNumbers[n/10]+"-"+smallNumbers[n%10])
+	case n >= 20:
+		parts = append(parts, tensNumbers[n/10])
+	case n > 0:
+		parts = append(parts, smallNumbers[n])
+	}
+	return strings.Join(parts, " ")
+}
+
+// year reads n the way years are said: 1998 as "nineteen ninety-eight",
+// 1905 as "nineteen oh five" and 2005 as "two thousand five".
+func year(n int64) string {
+	century, rest := int(n/100), int(n%100)
+	switch {
+	case n >= 2000 && n < 2010:
+		return cardinal(n)
+	case rest == 0:
+		return belowThousand(century) + " hundred"
+	case rest < 10:
+		return belowThousand(century) + " oh " + smallNumbers[rest]
+	default:
+		return belowThousand(century) + " " + belowThousand(rest)
+	}
+}
+
+// ordinal spells out the ordinal of n, such as 21 as "twenty-first".
+func ordinal(n int64) string {
+	words := cardinal(n)
+	i := strings.LastIndexAny(words, " -") + 1
+	last := words[i:]
+	switch {
+	case ordinals[last] != "":
+		last = ordinals[last]
+	case strings.HasSuffix(last, "y"):
+		last = strings.TrimSuffix(last, "y") + "ieth"
+	default:
+		last += "th"
+	}
+	return words[:i] + last
+}
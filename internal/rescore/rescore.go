@@ -0,0 +1,211 @@
+// Package rescore re-grades stored attempts with a scoring.Scorer, so that a
+// scoring change can be applied to history instead of only new attempts.
+package rescore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/nilesh0729/PixelScribe/internal/transcript"
+	"github.com/sqlc-dev/pqtype"
+)
+
+// Report summarises a rescoring run.
+type Report struct {
+	Rescored int
+	// Skipped counts attempts without a dictation, or a segment of one, to
+	// score against.
+	Skipped int
+}
+
+// BatchSize is how many attempts are loaded, scored and stored at a time.
+const BatchSize = 500
+
+// Run re-scores every attempt whose scoring_version differs from the
+// scorer's, or every attempt when force is set. Attempts are worked through
+// in batches by id; each batch is stored together with the rebuilt
+// performance summaries of its users, so a failed run leaves no summary out
+// of step with its attempts.
+func Run(ctx context.Context, store db.Store, scorer scoring.Scorer, force bool) (Report, error) {
+	var report Report
+
+	var afterID int64
+	for {
+		attempts, err := store.ListAttemptsToRescore(ctx, db.ListAttemptsToRescoreParams{
+			Force:          force,
+			ScoringVersion: scoring.VersionOf(scorer),
+			AfterID:        afterID,
+			BatchSize:      BatchSize,
+		})
+		if err != nil {
+			return report, fmt.Errorf("cannot list attempts: %w", err)
+		}
+		if len(attempts) == 0 {
+			return report, nil
+		}
+		if err := rescoreBatch(ctx, store, scorer, attempts, &report); err != nil {
+			return report, err
+		}
+		if len(attempts) < BatchSize {
+			return report, nil
+		}
+		afterID = attempts[len(attempts)-1].ID
+	}
+}
+
+// rescoreBatch re-scores attempts and stores them in one transaction,
+// adding them to report once they are stored.
+func rescoreBatch(ctx context.Context, store db.Store, scorer scoring.Scorer, attempts []db.Attempt, report *Report) error {
+	dictations := map[int64]db.Dictation{}
+	users := map[int64]userScoring{}
+	var updates []db.UpdateAttemptAccuracyParams
+	for _, attempt := range attempts {
+		if !attempt.DictationID.Valid {
+			report.Skipped++
+			continue
+		}
+
+		dictation, ok := dictations[attempt.DictationID.Int64]
+		if !ok {
+			var err error
+			dictation, err = store.GetDictation(ctx, attempt.DictationID.Int64)
+			if err == sql.ErrNoRows {
+				report.Skipped++
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("cannot get dictation %d: %w", attempt.DictationID.Int64, err)
+			}
+			dictations[dictation.ID] = dictation
+		}
+
+		user, err := userSettings(ctx, store, users, attempt.UserID)
+		if err != nil {
+			return err
+		}
+
+		arg, err := rescoreAttempt(scorer, attempt, dictation, user)
+		if errors.Is(err, transcript.ErrNoSegment) {
+			report.Skipped++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("cannot score attempt %d: %w", attempt.ID, err)
+		}
+		updates = append(updates, arg)
+	}
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if err := store.RescoreAttemptsTx(ctx, updates); err != nil {
+		return fmt.Errorf("cannot store attempts %d to %d: %w", updates[0].ID, updates[len(updates)-1].ID, err)
+	}
+	report.Rescored += len(updates)
+	return nil
+}
+
+// userScoring is what a user's settings contribute to scoring.
+type userScoring struct {
+	tolerance *scoring.Tolerance
+	lexicon   []lexicon.Entry
+}
+
+// userSettings returns the tolerance profile and pronunciation lexicon in a
+// user's settings, caching them in users. Attempts without a user, and
+// users without settings, have neither.
+func userSettings(ctx context.Context, store db.Store, users map[int64]userScoring, userID sql.NullInt64) (userScoring, error) {
+	if !userID.Valid {
+		return userScoring{}, nil
+	}
+	if user, ok := users[userID.Int64]; ok {
+		return user, nil
+	}
+	setting, err := store.GetSettingByUserID(ctx, userID)
+	if err != nil && err != sql.ErrNoRows {
+		return userScoring{}, fmt.Errorf("cannot get settings of user %d: %w", userID.Int64, err)
+	}
+	var user userScoring
+	user.tolerance, err = scoring.ParseTolerance(setting.Tolerance.RawMessage)
+	if err != nil {
+		return userScoring{}, fmt.Errorf("cannot read settings of user %d: %w", userID.Int64, err)
+	}
+	user.lexicon, err = lexicon.Parse(setting.Lexicon.RawMessage)
+	if err != nil {
+		return userScoring{}, fmt.Errorf("cannot read settings of user %d: %w", userID.Int64, err)
+	}
+	users[userID.Int64] = user
+	return user, nil
+}
+
+func rescoreAttempt(scorer scoring.Scorer, attempt db.Attempt, dictation db.Dictation, user userScoring) (db.UpdateAttemptAccuracyParams, error) {
+	opts, err := scoring.ParseOptions(dictation.ScoringOptions.RawMessage)
+	if err != nil {
+		return db.UpdateAttemptAccuracyParams{}, err
+	}
+	dictationLexicon, err := lexicon.Parse(dictation.Lexicon.RawMessage)
+	if err != nil {
+		return db.UpdateAttemptAccuracyParams{}, err
+	}
+	original := dictation.Content.String
+	if attempt.Segment.Valid {
+		segment, err := transcript.Lookup(dictation.TranscriptSegments.RawMessage, int(attempt.Segment.Int32))
+		if err != nil {
+			return db.UpdateAttemptAccuracyParams{}, err
+		}
+		original = segment.Text
+	}
+	score, err := scorer.Score(scoring.Input{
+		Original: original,
+		Typed:    attempt.TypedText.String,
+		Options:  opts,
+		Language: dictation.Language.String,
+		// Speed is recomputed from the stored duration and keystroke count
+		Seconds:       attempt.TimeSpent.Float64,
+		Keystrokes:    int(attempt.Keystrokes.Int32),
+		UserTolerance: user.tolerance,
+		Lexicon:       append(dictationLexicon, user.lexicon...),
+	})
+	if err != nil {
+		return db.UpdateAttemptAccuracyParams{}, err
+	}
+
+	comparisonData, err := score.ComparisonJSON()
+	if err != nil {
+		return db.UpdateAttemptAccuracyParams{}, err
+	}
+	markSheetData, err := score.MarkSheetJSON()
+	if err != nil {
+		return db.UpdateAttemptAccuracyParams{}, err
+	}
+
+	timed := attempt.TimeSpent.Float64 > 0
+	return db.UpdateAttemptAccuracyParams{
+		ID:                attempt.ID,
+		Accuracy:          sql.NullFloat64{Float64: score.Accuracy, Valid: true},
+		WeightedAccuracy:  sql.NullFloat64{Float64: score.WeightedAccuracy, Valid: true},
+		CorrectWords:      sql.NullInt32{Int32: int32(score.CorrectWords), Valid: true},
+		WrongWordErrors:   sql.NullInt32{Int32: int32(score.Errors.WrongWord), Valid: true},
+		SpellingErrors:    sql.NullInt32{Int32: int32(score.Errors.Spelling), Valid: true},
+		CaseErrors:        sql.NullInt32{Int32: int32(score.Errors.Case), Valid: true},
+		PunctuationErrors: sql.NullInt32{Int32: int32(score.Errors.Punctuation), Valid: true},
+		OmissionErrors:    sql.NullInt32{Int32: int32(score.Errors.Omission), Valid: true},
+		InsertionErrors:   sql.NullInt32{Int32: int32(score.Errors.Insertion), Valid: true},
+		ParagraphErrors:   sql.NullInt32{Int32: int32(score.Errors.Paragraph), Valid: true},
+		ComparisonData:    pqtype.NullRawMessage{RawMessage: comparisonData, Valid: true},
+		TimeSpent:         attempt.TimeSpent,
+		TotalWords:        sql.NullInt32{Int32: int32(score.TotalWords), Valid: true},
+		MarkSheet:         pqtype.NullRawMessage{RawMessage: markSheetData, Valid: markSheetData != nil},
+		ScoringVersion:    sql.NullString{String: score.Version, Valid: true},
+		GrossWpm:          sql.NullFloat64{Float64: score.Speed.GrossWPM, Valid: timed},
+		NetWpm:            sql.NullFloat64{Float64: score.Speed.NetWPM, Valid: timed},
+		Cpm:               sql.NullFloat64{Float64: score.Speed.CPM, Valid: timed},
+		CharAccuracy:      sql.NullFloat64{Float64: score.Speed.CharAccuracy, Valid: true},
+		Kspc:              sql.NullFloat64{Float64: score.Speed.KSPC, Valid: score.Speed.KSPC > 0},
+	}, nil
+}
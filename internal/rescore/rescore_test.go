@@ -0,0 +1,243 @@
+package rescore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"testing"
+
+	mockdb "github.com/nilesh0729/PixelScribe/internal/db/mock"
+	db "github.com/nilesh0729/PixelScribe/internal/db/sqlc"
+	"github.com/nilesh0729/PixelScribe/internal/scoring"
+	"github.com/sqlc-dev/pqtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	scorer := scoring.DefaultScorer()
+	dictation := db.Dictation{
+		ID:      7,
+		Content: sql.NullString{String: "the quick brown fox", Valid: true},
+	}
+	attempts := []db.Attempt{
+		{
+			ID:          1,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "the quick fox", Valid: true},
+			TimeSpent:   sql.NullFloat64{Float64: 12, Valid: true},
+		},
+		{
+			ID:          2,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "the quick brown fox", Valid: true},
+			TimeSpent:   sql.NullFloat64{Float64: 9, Valid: true},
+		},
+		{ID: 3},
+	}
+
+	store.EXPECT().
+		ListAttemptsToRescore(gomock.Any(), gomock.Eq(db.ListAttemptsToRescoreParams{
+			Force:          true,
+			ScoringVersion: scoring.VersionOf(scorer),
+			BatchSize:      BatchSize,
+		})).
+		Times(1).
+		Return(attempts, nil)
+	// The dictation is fetched once and reused for both attempts
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(dictation, nil)
+
+	var updates []db.UpdateAttemptAccuracyParams
+	store.EXPECT().
+		RescoreAttemptsTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg []db.UpdateAttemptAccuracyParams) error {
+			updates = arg
+			return nil
+		})
+
+	report, err := Run(context.Background(), store, scorer, true)
+	require.NoError(t, err)
+	require.Equal(t, Report{Rescored: 2, Skipped: 1}, report)
+
+	require.Len(t, updates, 2)
+	require.Equal(t, int64(1), updates[0].ID)
+	require.InDelta(t, 75.0, updates[0].Accuracy.Float64, 0.001)
+	require.InDelta(t, 75.0, updates[0].WeightedAccuracy.Float64, 0.001)
+	require.Equal(t, int32(1), updates[0].OmissionErrors.Int32)
+	require.Equal(t, 12.0, updates[0].TimeSpent.Float64)
+	// "the quick fox" is 13 characters in 12 seconds
+	require.InDelta(t, 13.0, updates[0].GrossWpm.Float64, 0.001)
+	require.Equal(t, scoring.VersionOf(scorer), updates[0].ScoringVersion.String)
+	require.False(t, updates[0].MarkSheet.Valid)
+
+	require.Equal(t, int64(2), updates[1].ID)
+	require.Equal(t, 100.0, updates[1].Accuracy.Float64)
+}
+
+func TestRunAppliesUserTolerance(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	scorer := scoring.DefaultScorer()
+	userID := sql.NullInt64{Int64: 5, Valid: true}
+	attempts := []db.Attempt{
+		{
+			ID:          1,
+			UserID:      userID,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "I paid 21 dollars", Valid: true},
+		},
+		{
+			ID:          2,
+			UserID:      userID,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "I paid twenty-one dollars", Valid: true},
+		},
+	}
+
+	store.EXPECT().
+		ListAttemptsToRescore(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(attempts, nil)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.Dictation{ID: 7, Content: sql.NullString{String: "I paid twenty-one dollars", Valid: true}}, nil)
+	// The user's settings are fetched once and reused for both attempts
+	store.EXPECT().
+		GetSettingByUserID(gomock.Any(), gomock.Eq(userID)).
+		Times(1).
+		Return(db.Setting{
+			Tolerance: pqtype.NullRawMessage{RawMessage: json.RawMessage(`{"numbers":true}`), Valid: true},
+		}, nil)
+
+	var updates []db.UpdateAttemptAccuracyParams
+	store.EXPECT().
+		RescoreAttemptsTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg []db.UpdateAttemptAccuracyParams) error {
+			updates = arg
+			return nil
+		})
+
+	_, err := Run(context.Background(), store, scorer, true)
+	require.NoError(t, err)
+
+	require.Len(t, updates, 2)
+	require.Equal(t, 100.0, updates[0].Accuracy.Float64)
+	require.Equal(t, 100.0, updates[1].Accuracy.Float64)
+}
+
+func TestRunScoresSegments(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	dictation := db.Dictation{
+		ID:                 7,
+		Content:            sql.NullString{String: "Dear Sir, thank you for your letter.", Valid: true},
+		TranscriptSegments: pqtype.NullRawMessage{RawMessage: json.RawMessage(`[{"start_ms":0,"end_ms":900,"text":"Dear Sir,"},{"start_ms":900,"end_ms":3000,"text":"thank you for your letter."}]`), Valid: true},
+	}
+	attempts := []db.Attempt{
+		{
+			ID:          1,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "thank you for your letter.", Valid: true},
+			Segment:     sql.NullInt32{Int32: 2, Valid: true},
+		},
+		// The transcript no longer has a third segment
+		{
+			ID:          2,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "Yours faithfully,", Valid: true},
+			Segment:     sql.NullInt32{Int32: 3, Valid: true},
+		},
+	}
+
+	store.EXPECT().
+		ListAttemptsToRescore(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(attempts, nil)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(dictation, nil)
+	store.EXPECT().
+		RescoreAttemptsTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg []db.UpdateAttemptAccuracyParams) error {
+			require.Len(t, arg, 1)
+			require.Equal(t, int64(1), arg[0].ID)
+			require.Equal(t, 100.0, arg[0].Accuracy.Float64)
+			require.Equal(t, int32(5), arg[0].TotalWords.Int32)
+			return nil
+		})
+
+	report, err := Run(context.Background(), store, scoring.DefaultScorer(), true)
+	require.NoError(t, err)
+	require.Equal(t, Report{Rescored: 1, Skipped: 1}, report)
+}
+
+func TestRunPagesThroughAttempts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+
+	scorer := scoring.DefaultScorer()
+	// A full first batch of attempts with nothing to score against
+	first := make([]db.Attempt, BatchSize)
+	for i := range first {
+		first[i] = db.Attempt{ID: int64(i + 1)}
+	}
+	second := []db.Attempt{
+		{
+			ID:          BatchSize + 1,
+			DictationID: sql.NullInt64{Int64: 7, Valid: true},
+			TypedText:   sql.NullString{String: "the quick brown fox", Valid: true},
+		},
+	}
+
+	gomock.InOrder(
+		store.EXPECT().
+			ListAttemptsToRescore(gomock.Any(), gomock.Eq(db.ListAttemptsToRescoreParams{
+				ScoringVersion: scoring.VersionOf(scorer),
+				BatchSize:      BatchSize,
+			})).
+			Times(1).
+			Return(first, nil),
+		store.EXPECT().
+			ListAttemptsToRescore(gomock.Any(), gomock.Eq(db.ListAttemptsToRescoreParams{
+				ScoringVersion: scoring.VersionOf(scorer),
+				AfterID:        BatchSize,
+				BatchSize:      BatchSize,
+			})).
+			Times(1).
+			Return(second, nil),
+	)
+	store.EXPECT().
+		GetDictation(gomock.Any(), gomock.Eq(int64(7))).
+		Times(1).
+		Return(db.Dictation{ID: 7, Content: sql.NullString{String: "the quick brown fox", Valid: true}}, nil)
+	// Only the batch with something to store opens a transaction
+	store.EXPECT().
+		RescoreAttemptsTx(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg []db.UpdateAttemptAccuracyParams) error {
+			require.Len(t, arg, 1)
+			require.Equal(t, int64(BatchSize+1), arg[0].ID)
+			return nil
+		})
+
+	report, err := Run(context.Background(), store, scorer, false)
+	require.NoError(t, err)
+	require.Equal(t, Report{Rescored: 1, Skipped: BatchSize}, report)
+}
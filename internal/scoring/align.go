@@ -0,0 +1,267 @@
+// Package scoring compares a typed transcription against the original
+// dictation text.
+package scoring
+
+import "strings"
+
+// OpKind describes how a word in the original text relates to the typed text.
+type OpKind string
+
+const (
+	OpMatch      OpKind = "match"
+	OpSubstitute OpKind = "substitute"
+	OpOmit       OpKind = "omit"
+	OpInsert     OpKind = "insert"
+)
+
+// Op is a single step of an alignment. OriginalIndex and TypedIndex are the
+// word positions in their respective inputs, or -1 when the op does not
+// consume a word from that side (omissions have no typed word, insertions
+// have no original word).
+//
+// A match found by a tolerance rule may span several words on either side,
+// such as "do not" typed as "don't". OriginalWords and TypedWords count the
+// words the op consumes, Original and Typed hold them joined by spaces and
+// Tolerance names the rule that accepted them.
+type Op struct {
+	Kind          OpKind
+	OriginalIndex int
+	TypedIndex    int
+	OriginalWords int
+	TypedWords    int
+	Original      string
+	Typed         string
+	Tolerance     ToleranceRule
+}
+
+// Alignment is the minimal edit script turning the original words into the
+// typed words, together with per-kind counts. Line and paragraph break tokens
+// are aligned like words but left out of the counts.
+type Alignment struct {
+	Ops         []Op
+	Matched     int
+	Substituted int
+	Omitted     int
+	Inserted    int
+}
+
+// Accuracy returns the share of original words that were typed correctly, as
+// a percentage. Insertions do not reduce accuracy.
+func (a Alignment) Accuracy() float64 {
+	total := a.Matched + a.Substituted + a.Omitted
+	if total == 0 {
+		return 0
+	}
+	return float64(a.Matched) / float64(total) * 100
+}
+
+// Align computes a word-level Levenshtein alignment between original and typed.
+// Substitutions, omissions and insertions all cost one edit, so a single
+// dropped or extra word only affects that word instead of shifting every
+// word after it.
+func Align(original, typed []string) Alignment {
+	return AlignWith(original, typed, nil)
+}
+
+// MaxTypedWords is the most words a transcription of an original of
+// originalWords words may have. Alignment takes time and memory in
+// proportion to both lengths, so anything much longer than the original is
+// refused rather than scored.
+func MaxTypedWords(originalWords int) int {
+	return 2*originalWords + 50
+}
+
+// stepKind is how an alignment cell was reached.
+type stepKind uint8
+
+const (
+	stepMatch stepKind = iota
+	stepSubstitute
+	stepOmit
+	stepInsert
+	// stepTolerance is a match accepted by a tolerance rule.
+	stepTolerance
+)
+
+// step is the compact backpointer of an alignment cell: its kind and the
+// words it consumes on either side. The ops are rebuilt from the steps on
+// the way back.
+type step struct {
+	kind          stepKind
+	originalWords uint8
+	typedWords    uint8
+}
+
+// score ranks the alignments of original[:i] and typed[:j]: dist is the
+// edit distance, hits the number of matched original words and credit the
+// matches plus the partial credit of the substitutions on that path.
+type score struct {
+	dist   int32
+	hits   int32
+	credit float64
+}
+
+// better reports whether s beats t: a lower distance wins, then more
+// matches, then more partial credit.
+func (s score) better(t score) bool {
+	return s.dist < t.dist || (s.dist == t.dist && (s.hits > t.hits || (s.hits == t.hits && s.credit > t.credit)))
+}
+
+// AlignWith is Align with tolerance rules: a typed phrase that a rule of tol
+// accepts for an original phrase is a free match for every original word it
+// covers. A nil tol aligns exactly like Align.
+func AlignWith(original, typed []string, tol *Tolerance) Alignment {
+	n, m := len(original), len(typed)
+
+	var originalPhrases, typedPhrases [][]phrase
+	// Scores are only looked up as far back as the longest original phrase
+	window := 2
+	if mt := newMatcher(tol); mt != nil {
+		originalPhrases, typedPhrases = mt.phrases(original), mt.phrases(typed)
+		for _, ps := range originalPhrases {
+			for _, p := range ps {
+				window = max(window, p.words+1)
+			}
+		}
+	}
+
+	// Among alignments with the same distance the one with the most matches
+	// wins, so "a b c" vs "b x c" becomes omit/match/.. rather than three
+	// substitutions, and after that the one with the most partial credit, so
+	// a misspelt word is paired with the word it was meant to be. On ties
+	// the diagonal is kept.
+	//
+	// Only the last window rows of scores are kept; every cell keeps its
+	// step so the alignment can be walked back.
+	rows := make([][]score, window)
+	for k := range rows {
+		rows[k] = make([]score, m+1)
+	}
+	row := func(i int) []score { return rows[i%window] }
+	steps := make([]step, (n+1)*(m+1))
+	at := func(i, j int) *step { return &steps[i*(m+1)+j] }
+
+	for j := 0; j <= m; j++ {
+		row(0)[j] = score{dist: int32(j)}
+		*at(0, j) = step{kind: stepInsert, typedWords: 1}
+	}
+	for i := 1; i <= n; i++ {
+		prev, cur := row(i-1), row(i)
+		cur[0] = score{dist: int32(i)}
+		*at(i, 0) = step{kind: stepOmit, originalWords: 1}
+		for j := 1; j <= m; j++ {
+			sc := prev[j-1]
+			st := step{kind: stepMatch, originalWords: 1, typedWords: 1}
+			if original[i-1] == typed[j-1] {
+				sc.hits++
+				sc.credit++
+			} else {
+				sc.dist++
+				sc.credit += PartialCredit(Op{Kind: OpSubstitute, Original: original[i-1], Typed: typed[j-1]})
+				st.kind = stepSubstitute
+			}
+			omit, insert := prev[j], cur[j-1]
+			omit.dist++
+			insert.dist++
+			// A break is never substituted for a word; that is a missed
+			// word and an extra break, or the other way round
+			if IsBreak(original[i-1]) != IsBreak(typed[j-1]) {
+				sc, st = omit, step{kind: stepOmit, originalWords: 1}
+			}
+			if omit.better(sc) {
+				sc, st = omit, step{kind: stepOmit, originalWords: 1}
+			}
+			if insert.better(sc) {
+				sc, st = insert, step{kind: stepInsert, typedWords: 1}
+			}
+			if originalPhrases != nil {
+				for _, p := range originalPhrases[i] {
+					for _, q := range typedPhrases[j] {
+						if _, ok := p.equivalent(q); !ok {
+							continue
+						}
+						pc := row(i - p.words)[j-q.words]
+						pc.hits += int32(p.words)
+						pc.credit += float64(p.words)
+						if pc.better(sc) {
+							sc, st = pc, step{kind: stepTolerance, originalWords: uint8(p.words), typedWords: uint8(q.words)}
+						}
+					}
+				}
+			}
+			cur[j], *at(i, j) = sc, st
+		}
+	}
+
+	// Walk back from the bottom-right corner along the recorded steps.
+	ops := make([]Op, 0, max(n, m))
+	i, j := n, m
+	for i > 0 || j > 0 {
+		st := *at(i, j)
+		op := Op{OriginalIndex: -1, TypedIndex: -1, OriginalWords: int(st.originalWords), TypedWords: int(st.typedWords)}
+		switch st.kind {
+		case stepMatch:
+			op.Kind = OpMatch
+		case stepSubstitute:
+			op.Kind = OpSubstitute
+		case stepOmit:
+			op.Kind = OpOmit
+		case stepInsert:
+			op.Kind = OpInsert
+		case stepTolerance:
+			op.Kind = OpMatch
+			op.Tolerance = toleranceRule(originalPhrases[i], typedPhrases[j], op.OriginalWords, op.TypedWords)
+		}
+		i, j = i-op.OriginalWords, j-op.TypedWords
+		if op.OriginalWords > 0 {
+			op.OriginalIndex = i
+			op.Original = strings.Join(original[i:i+op.OriginalWords], " ")
+		}
+		if op.TypedWords > 0 {
+			op.TypedIndex = j
+			op.Typed = strings.Join(typed[j:j+op.TypedWords], " ")
+		}
+		ops = append(ops, op)
+	}
+
+	var a Alignment
+	a.Ops = make([]Op, len(ops))
+	for k, op := range ops {
+		a.Ops[len(ops)-1-k] = op
+		// Counts, and so accuracy, only cover words
+		if IsBreak(op.Original) || IsBreak(op.Typed) {
+			continue
+		}
+		switch op.Kind {
+		case OpMatch:
+			a.Matched += op.OriginalWords
+		case OpSubstitute:
+			a.Substituted++
+		case OpOmit:
+			a.Omitted++
+		case OpInsert:
+			a.Inserted++
+		}
+	}
+	return a
+}
+
+// toleranceRule finds the rule that accepted a tolerance step over
+// originalWords and typedWords words, trying phrase pairs in the same order
+// as AlignWith does.
+func toleranceRule(originalPhrases, typedPhrases []phrase, originalWords, typedWords int) ToleranceRule {
+	for _, p := range originalPhrases {
+		if p.words != originalWords {
+			continue
+		}
+		for _, q := range typedPhrases {
+			if q.words != typedWords {
+				continue
+			}
+			if rule, ok := p.equivalent(q); ok {
+				return rule
+			}
+		}
+	}
+	return ""
+}
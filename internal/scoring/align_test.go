@@ -0,0 +1,96 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func kinds(a Alignment) []OpKind {
+	var out []OpKind
+	for _, op := range a.Ops {
+		out = append(out, op.Kind)
+	}
+	return out
+}
+
+func TestAlign(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		typed    string
+		kinds    []OpKind
+		accuracy float64
+	}{
+		{
+			name:     "ExactMatch",
+			original: "the quick brown fox",
+			typed:    "the quick brown fox",
+			kinds:    []OpKind{OpMatch, OpMatch, OpMatch, OpMatch},
+			accuracy: 100,
+		},
+		{
+			name:     "DroppedWord",
+			original: "the quick brown fox",
+			typed:    "the brown fox",
+			kinds:    []OpKind{OpMatch, OpOmit, OpMatch, OpMatch},
+			accuracy: 75,
+		},
+		{
+			name:     "ExtraWord",
+			original: "the quick brown fox",
+			typed:    "the very quick brown fox",
+			kinds:    []OpKind{OpMatch, OpInsert, OpMatch, OpMatch, OpMatch},
+			accuracy: 100,
+		},
+		{
+			name:     "WrongWord",
+			original: "the quick brown fox",
+			typed:    "the quick green fox",
+			kinds:    []OpKind{OpMatch, OpMatch, OpSubstitute, OpMatch},
+			accuracy: 75,
+		},
+		{
+			name:     "PrefersMatchesOnTies",
+			original: "a b c",
+			typed:    "b x c",
+			kinds:    []OpKind{OpOmit, OpMatch, OpInsert, OpMatch},
+			accuracy: 66.667,
+		},
+		{
+			name:     "EmptyTyped",
+			original: "hello world",
+			typed:    "",
+			kinds:    []OpKind{OpOmit, OpOmit},
+			accuracy: 0,
+		},
+		{
+			name:     "EmptyOriginal",
+			original: "",
+			typed:    "hello",
+			kinds:    []OpKind{OpInsert},
+			accuracy: 0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Align(strings.Fields(tc.original), strings.Fields(tc.typed))
+			require.Equal(t, tc.kinds, kinds(a))
+			require.InDelta(t, tc.accuracy, a.Accuracy(), 0.001)
+		})
+	}
+}
+
+func TestAlignPositions(t *testing.T) {
+	a := Align([]string{"a", "b", "c"}, []string{"a", "x", "c", "d"})
+
+	require.Equal(t, 2, a.Matched)
+	require.Equal(t, 1, a.Substituted)
+	require.Equal(t, 0, a.Omitted)
+	require.Equal(t, 1, a.Inserted)
+
+	require.Equal(t, Op{Kind: OpSubstitute, OriginalIndex: 1, TypedIndex: 1, OriginalWords: 1, TypedWords: 1, Original: "b", Typed: "x"}, a.Ops[1])
+	require.Equal(t, Op{Kind: OpInsert, OriginalIndex: -1, TypedIndex: 3, TypedWords: 1, Typed: "d"}, a.Ops[3])
+}
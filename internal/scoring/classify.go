@@ -0,0 +1,141 @@
+package scoring
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ErrorCounts tallies the mistakes in an alignment by category.
+type ErrorCounts struct {
+	Case        int
+	Punctuation int
+	Spelling    int
+	WrongWord   int
+	Omission    int
+	Insertion   int
+	Paragraph   int
+}
+
+// Total returns the number of mistakes across all categories.
+func (c ErrorCounts) Total() int {
+	return c.Case + c.Punctuation + c.Spelling + c.WrongWord + c.Omission + c.Insertion + c.Paragraph
+}
+
+// CountErrors classifies every non-matching op of an alignment.
+func CountErrors(a Alignment) ErrorCounts {
+	var c ErrorCounts
+	for _, op := range a.Ops {
+		switch categoryOf(op) {
+		case CategoryCase:
+			c.Case++
+		case CategoryPunctuation:
+			c.Punctuation++
+		case CategorySpelling:
+			c.Spelling++
+		case CategoryWrongWord:
+			c.WrongWord++
+		case CategoryOmission:
+			c.Omission++
+		case CategoryInsertion:
+			c.Insertion++
+		case CategoryParagraph:
+			c.Paragraph++
+		}
+	}
+	return c
+}
+
+// categoryOf returns the mistake category of op, or "" for matches. A missed,
+// extra or wrong line or paragraph break is a paragraph mistake.
+func categoryOf(op Op) Category {
+	if op.Kind != OpMatch && (IsBreak(op.Original) || IsBreak(op.Typed)) {
+		return CategoryParagraph
+	}
+	switch op.Kind {
+	case OpSubstitute:
+		return Classify(op.Original, op.Typed)
+	case OpOmit:
+		return CategoryOmission
+	case OpInsert:
+		return CategoryInsertion
+	}
+	return ""
+}
+
+// Classify decides why typed was substituted for original. Punctuation is
+// ignored before looking at letters, so "Hello," typed as "hello" counts as a
+// single case error rather than two mistakes.
+func Classify(original, typed string) Category {
+	bareOriginal, bareTyped := stripPunct(original), stripPunct(typed)
+	switch {
+	case bareOriginal == bareTyped:
+		return CategoryPunctuation
+	case strings.EqualFold(bareOriginal, bareTyped):
+		return CategoryCase
+	}
+
+	lowerOriginal, lowerTyped := strings.ToLower(bareOriginal), strings.ToLower(bareTyped)
+	d := charDistance(lowerOriginal, lowerTyped)
+	if d <= maxSpellingEdits(lowerOriginal) && d < len([]rune(lowerOriginal)) {
+		return CategorySpelling
+	}
+	return CategoryWrongWord
+}
+
+// maxSpellingEdits is how many character edits a word may have and still be
+// considered a misspelling rather than a different word.
+func maxSpellingEdits(word string) int {
+	switch n := len([]rune(word)); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+func stripPunct(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsPunct(r) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// maxDistanceCells bounds the work of one charDistance call. Words too long
+// to compare within it are counted as entirely different.
+const maxDistanceCells = 1 << 16
+
+// charDistance is the optimal string alignment distance between a and b in
+// runes: Levenshtein distance where swapping two adjacent letters counts as a
+// single edit, so "teh" is one typo away from "the".
+func charDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	if (len(ra)+1)*(len(rb)+1) > maxDistanceCells {
+		return max(len(ra), len(rb))
+	}
+	// Only the last three rows of the matrix are needed.
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j-1]+cost, prev[j]+1, curr[j-1]+1)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,57 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	testCases := []struct {
+		original string
+		typed    string
+		category Category
+	}{
+		{"Hello", "hello", CategoryCase},
+		{"Hello,", "hello", CategoryCase},
+		{"world.", "world", CategoryPunctuation},
+		{"don't", "dont", CategoryPunctuation},
+		{"receive", "recieve", CategorySpelling},
+		{"cat", "cta", CategorySpelling},
+		{"necessary", "neccesary", CategorySpelling},
+		{"cat", "dog", CategoryWrongWord},
+		{"elephant", "giraffe", CategoryWrongWord},
+		{"a", "I", CategoryWrongWord},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.original+"/"+tc.typed, func(t *testing.T) {
+			require.Equal(t, tc.category, Classify(tc.original, tc.typed))
+		})
+	}
+}
+
+func TestCountErrors(t *testing.T) {
+	original := strings.Fields("The quick brown fox jumps over the lazy dog.")
+	typed := strings.Fields("the quikc brown jumps over a lazy lazy dog")
+
+	counts := CountErrors(Align(original, typed))
+	require.Equal(t, ErrorCounts{
+		Case:        1,
+		Punctuation: 1,
+		Spelling:    1,
+		WrongWord:   1,
+		Omission:    1,
+		Insertion:   1,
+	}, counts)
+	require.Equal(t, 6, counts.Total())
+}
+
+func TestCharDistance(t *testing.T) {
+	require.Equal(t, 0, charDistance("", ""))
+	require.Equal(t, 3, charDistance("", "abc"))
+	require.Equal(t, 1, charDistance("नमस्ते", "नमस्त"))
+	require.Equal(t, 3, charDistance("kitten", "sitting"))
+	require.Equal(t, 1, charDistance("the", "teh"))
+}
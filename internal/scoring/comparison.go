@@ -0,0 +1,88 @@
+package scoring
+
+// ComparisonVersion is the version of the comparison_data JSON schema
+// produced by NewComparison. Bump it whenever a field changes meaning or is
+// removed; see docs/schemas/comparison_data.v1.json.
+const ComparisonVersion = 1
+
+// Category names the kind of mistake an op represents.
+type Category string
+
+const (
+	CategoryCase        Category = "case"
+	CategoryPunctuation Category = "punctuation"
+	CategorySpelling    Category = "spelling"
+	CategoryWrongWord   Category = "wrong_word"
+	CategoryOmission    Category = "omission"
+	CategoryInsertion   Category = "insertion"
+	CategoryParagraph   Category = "paragraph"
+)
+
+// Span is a half-open range of Unicode code point offsets into a text.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// ComparisonToken is one aligned step between the original and typed texts.
+// Original fields are absent for insertions and typed fields are absent for
+// omissions. Matches accepted by a tolerance rule name it in Tolerance and,
+// when they cover more than one word on a side, give the word count and a
+// span over all of those words. With layout scoring, line and paragraph breaks
+// are tokens too, written as LineBreak or ParagraphBreak, and the indices count
+// them.
+type ComparisonToken struct {
+	Op            OpKind        `json:"op"`
+	Category      Category      `json:"category,omitempty"`
+	Tolerance     ToleranceRule `json:"tolerance,omitempty"`
+	Original      string        `json:"original,omitempty"`
+	Typed         string        `json:"typed,omitempty"`
+	OriginalIndex *int          `json:"original_index,omitempty"`
+	TypedIndex    *int          `json:"typed_index,omitempty"`
+	OriginalWords int           `json:"original_words,omitempty"`
+	TypedWords    int           `json:"typed_words,omitempty"`
+	OriginalSpan  *Span         `json:"original_span,omitempty"`
+	TypedSpan     *Span         `json:"typed_span,omitempty"`
+}
+
+// Comparison is the server-generated diff stored in attempts.comparison_data.
+type Comparison struct {
+	Version int               `json:"version"`
+	Tokens  []ComparisonToken `json:"tokens"`
+}
+
+// NewComparison builds the comparison document for an alignment of the given
+// original and typed tokens.
+func NewComparison(a Alignment, original, typed []Token) Comparison {
+	c := Comparison{
+		Version: ComparisonVersion,
+		Tokens:  make([]ComparisonToken, 0, len(a.Ops)),
+	}
+	for _, op := range a.Ops {
+		t := ComparisonToken{
+			Op:        op.Kind,
+			Category:  categoryOf(op),
+			Tolerance: op.Tolerance,
+			Original:  op.Original,
+			Typed:     op.Typed,
+		}
+		if op.OriginalIndex >= 0 {
+			idx, last := op.OriginalIndex, op.OriginalIndex+max(op.OriginalWords, 1)-1
+			t.OriginalIndex = &idx
+			t.OriginalSpan = &Span{Start: original[idx].Start, End: original[last].End}
+			if op.OriginalWords > 1 {
+				t.OriginalWords = op.OriginalWords
+			}
+		}
+		if op.TypedIndex >= 0 {
+			idx, last := op.TypedIndex, op.TypedIndex+max(op.TypedWords, 1)-1
+			t.TypedIndex = &idx
+			t.TypedSpan = &Span{Start: typed[idx].Start, End: typed[last].End}
+			if op.TypedWords > 1 {
+				t.TypedWords = op.TypedWords
+			}
+		}
+		c.Tokens = append(c.Tokens, t)
+	}
+	return c
+}
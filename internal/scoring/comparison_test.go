@@ -0,0 +1,50 @@
+package scoring
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenizeOffsets(t *testing.T) {
+	tokens := Tokenize("  héllo\tbig\n world ")
+
+	require.Equal(t, []Token{
+		{Text: "héllo", Start: 2, End: 7},
+		{Text: "big", Start: 8, End: 11},
+		{Text: "world", Start: 13, End: 18},
+	}, tokens)
+}
+
+func TestNewComparison(t *testing.T) {
+	original := Tokenize("the quick brown fox jumps")
+	typed := Tokenize("the brown fax jumps over")
+	a := Align(Words(original), Words(typed))
+
+	c := NewComparison(a, original, typed)
+	require.Equal(t, ComparisonVersion, c.Version)
+	require.Len(t, c.Tokens, len(a.Ops))
+
+	omitted := c.Tokens[1]
+	require.Equal(t, OpOmit, omitted.Op)
+	require.Equal(t, CategoryOmission, omitted.Category)
+	require.Equal(t, &Span{Start: 4, End: 9}, omitted.OriginalSpan)
+	require.Nil(t, omitted.TypedSpan)
+	require.Nil(t, omitted.TypedIndex)
+
+	substituted := c.Tokens[3]
+	require.Equal(t, OpSubstitute, substituted.Op)
+	require.Equal(t, "fox", substituted.Original)
+	require.Equal(t, "fax", substituted.Typed)
+	require.Equal(t, &Span{Start: 10, End: 13}, substituted.TypedSpan)
+
+	inserted := c.Tokens[5]
+	require.Equal(t, OpInsert, inserted.Op)
+	require.Nil(t, inserted.OriginalSpan)
+
+	data, err := json.Marshal(c)
+	require.NoError(t, err)
+	require.Contains(t, string(data), `"version":1`)
+	require.NotContains(t, string(data), `"category":""`)
+}
@@ -0,0 +1,46 @@
+package scoring
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// PartialCredit is the share of a word an op earns towards weighted accuracy.
+// Matches earn full credit for every original word they cover and omissions
+// none. A misspelling earns what is left of the word after its character
+// edits, as a share of the original's length: one slip in "necessary" keeps
+// 8/9 of the word, while one slip in "the" keeps 2/3. Case and punctuation
+// slips earn half a word and a wholly different word earns nothing.
+func PartialCredit(op Op) float64 {
+	if IsBreak(op.Original) || IsBreak(op.Typed) {
+		return 0
+	}
+	switch op.Kind {
+	case OpMatch:
+		return float64(max(op.OriginalWords, 1))
+	case OpSubstitute:
+		switch Classify(op.Original, op.Typed) {
+		case CategoryCase, CategoryPunctuation:
+			return 0.5
+		case CategorySpelling:
+			original := strings.ToLower(stripPunct(op.Original))
+			d := charDistance(original, strings.ToLower(stripPunct(op.Typed)))
+			return max(0, 1-float64(d)/float64(utf8.RuneCountInString(original)))
+		}
+	}
+	return 0
+}
+
+// WeightedAccuracy is like Alignment.Accuracy but gives near-miss words
+// partial credit, see PartialCredit. Breaks earn no credit.
+func WeightedAccuracy(a Alignment) float64 {
+	total := a.Matched + a.Substituted + a.Omitted
+	if total == 0 {
+		return 0
+	}
+	var credit float64
+	for _, op := range a.Ops {
+		credit += PartialCredit(op)
+	}
+	return credit / float64(total) * 100
+}
@@ -0,0 +1,45 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPartialCredit(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		typed    string
+		credit   float64
+	}{
+		{"Match", "necessary", "necessary", 1},
+		// The same slip costs a short word a larger share than a long one
+		{"OneEditShortWord", "cat", "kat", 2.0 / 3},
+		{"OneEditLongWord", "necessary", "neccessary", 8.0 / 9},
+		{"TwoEditsLongWord", "necessary", "nesesary", 7.0 / 9},
+		{"Transposition", "the", "teh", 2.0 / 3},
+		{"Case", "Hello", "hello", 0.5},
+		{"Punctuation", "hello,", "hello", 0.5},
+		{"DifferentWord", "quick", "slow", 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := Align([]string{tc.original}, []string{tc.typed})
+			require.Len(t, a.Ops, 1)
+			require.InDelta(t, tc.credit, PartialCredit(a.Ops[0]), 0.001)
+		})
+	}
+}
+
+func TestWeightedAccuracy(t *testing.T) {
+	a := Align(strings.Fields("the quick brown fox jumps"), strings.Fields("the quikc fox jumps over"))
+
+	// 3 matches + 0.8 for "quikc", one edit in five letters, nothing for the
+	// omitted "brown"; the inserted "over" costs nothing, as in strict accuracy
+	require.InDelta(t, 60.0, a.Accuracy(), 0.001)
+	require.InDelta(t, 76.0, WeightedAccuracy(a), 0.001)
+	require.Zero(t, WeightedAccuracy(Alignment{}))
+}
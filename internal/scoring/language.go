@@ -0,0 +1,84 @@
+package scoring
+
+import (
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Analyzer turns a text in one language into the normalized tokens that are
+// aligned and compared, so that differences the typist can't see or control
+// (composed vs decomposed letters, curly vs straight quotes, full-width forms)
+// are not scored as mistakes.
+type Analyzer struct {
+	// Language is the primary language subtag the analyzer serves, e.g. "hi".
+	// It is empty for the default analyzer.
+	Language string
+	// form is the Unicode normalization applied to every token.
+	form norm.Form
+	// segmentIdeographs makes every Han, Hiragana and Katakana character its
+	// own word, since Chinese and Japanese are written without spaces.
+	segmentIdeographs bool
+	// foldJoiners drops zero width (non-)joiners, which change how Indic
+	// conjuncts are drawn but not which word was typed.
+	foldJoiners bool
+}
+
+var analyzers = map[string]Analyzer{}
+
+func init() {
+	// Devanagari: NFC also puts nukta letters such as क़ into one canonical
+	// sequence, whichever way the keyboard produced them.
+	for _, lang := range []string{"hi", "mr", "ne", "sa", "mai", "kok", "bho"} {
+		analyzers[lang] = Analyzer{Language: lang, form: norm.NFC, foldJoiners: true}
+	}
+	for _, lang := range []string{"zh", "ja"} {
+		analyzers[lang] = Analyzer{Language: lang, form: norm.NFKC, segmentIdeographs: true}
+	}
+}
+
+// AnalyzerFor picks the analyzer for a BCP 47 language tag such as "hi-IN" or
+// "en-US". Languages without special handling get NFKC folding and white
+// space tokenization.
+func AnalyzerFor(language string) Analyzer {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(language)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	if a, ok := analyzers[primary]; ok {
+		return a
+	}
+	return Analyzer{form: norm.NFKC}
+}
+
+// Tokenize segments text and normalizes every token. Offsets refer to text as
+// given, so comparisons still highlight the right part of the source.
+func (a Analyzer) Tokenize(text string) []Token {
+	tokens := segment([]rune(text), a.segmentIdeographs)
+	kept := tokens[:0]
+	for _, t := range tokens {
+		t.Text = a.Normalize(t.Text)
+		if t.Text != "" {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// Normalize applies the analyzer's Unicode normalization and folds quote and
+// dash variants to their ASCII forms.
+func (a Analyzer) Normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '‘', '’', '‚', '‛', '′', '`', '´':
+			return '\''
+		case '“', '”', '„', '‟', '″', '«', '»':
+			return '"'
+		case '‐', '‑', '‒', '–', '—', '―', '−':
+			return '-'
+		case '\u200c', '\u200d':
+			if a.foldJoiners {
+				return -1
+			}
+		}
+		return r
+	}, a.form.String(s))
+}
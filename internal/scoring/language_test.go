@@ -0,0 +1,112 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzerFor(t *testing.T) {
+	require.Equal(t, "hi", AnalyzerFor("hi-IN").Language)
+	require.Equal(t, "ja", AnalyzerFor("ja_JP").Language)
+	require.Equal(t, "zh", AnalyzerFor(" ZH-Hans ").Language)
+	require.Equal(t, "", AnalyzerFor("en-US").Language)
+	require.Equal(t, "", AnalyzerFor("").Language)
+}
+
+func TestAnalyzerTokenize(t *testing.T) {
+	testCases := []struct {
+		name     string
+		language string
+		text     string
+		want     []Token
+	}{
+		{
+			name:     "SmartQuotesAndDashes",
+			language: "en-US",
+			text:     "“It’s” well—known",
+			want: []Token{
+				{Text: `"It's"`, Start: 0, End: 6},
+				{Text: "well-known", Start: 7, End: 17},
+			},
+		},
+		{
+			name:     "FullWidthFolded",
+			language: "en-US",
+			text:     "ＡＢＣ１２３",
+			want:     []Token{{Text: "ABC123", Start: 0, End: 6}},
+		},
+		{
+			name:     "ZeroWidthSpaceSeparates",
+			language: "en-US",
+			text:     "one\u200btwo",
+			want: []Token{
+				{Text: "one", Start: 0, End: 3},
+				{Text: "two", Start: 4, End: 7},
+			},
+		},
+		{
+			// Precomposed QA (U+0958) and KA + NUKTA are the same letter
+			name:     "DevanagariNukta",
+			language: "hi",
+			text:     "\u0958लम",
+			want:     []Token{{Text: "\u0915\u093cलम", Start: 0, End: 3}},
+		},
+		{
+			// Zero width joiners only change how the conjunct is drawn
+			name:     "DevanagariJoiners",
+			language: "hi",
+			text:     "क्\u200dष",
+			want:     []Token{{Text: "क्ष", Start: 0, End: 4}},
+		},
+		{
+			name:     "ChineseCharacters",
+			language: "zh-CN",
+			text:     "我爱北京。",
+			want: []Token{
+				{Text: "我", Start: 0, End: 1},
+				{Text: "爱", Start: 1, End: 2},
+				{Text: "北", Start: 2, End: 3},
+				{Text: "京。", Start: 3, End: 5},
+			},
+		},
+		{
+			name:     "JapaneseMixedScripts",
+			language: "ja",
+			text:     "「東京」へ行くPC",
+			want: []Token{
+				{Text: "「東", Start: 0, End: 2},
+				{Text: "京」", Start: 2, End: 4},
+				{Text: "へ", Start: 4, End: 5},
+				{Text: "行", Start: 5, End: 6},
+				{Text: "く", Start: 6, End: 7},
+				{Text: "PC", Start: 7, End: 9},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, AnalyzerFor(tc.language).Tokenize(tc.text))
+		})
+	}
+}
+
+func TestScoreNormalizesBeforeAlignment(t *testing.T) {
+	result, err := WordAlignScorer{}.Score(Input{
+		Original: "He said “don’t” — twice",
+		Typed:    `He said "don't" - twice`,
+		Language: "en",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 100.0, result.Accuracy)
+
+	result, err = WordAlignScorer{}.Score(Input{
+		Original: "我爱北京",
+		Typed:    "我爱南京",
+		Language: "zh",
+	})
+	require.NoError(t, err)
+	require.Equal(t, 4, result.TotalWords)
+	require.Equal(t, 3, result.CorrectWords)
+}
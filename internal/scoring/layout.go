@@ -0,0 +1,59 @@
+package scoring
+
+// Break tokens stand for the line and paragraph boundaries of a text when
+// layout scoring is enabled. Words never contain white space, so they cannot
+// be mistaken for a break.
+const (
+	LineBreak      = "\n"
+	ParagraphBreak = "\n\n"
+)
+
+// IsBreak reports whether word is a LineBreak or ParagraphBreak token.
+func IsBreak(word string) bool {
+	return word == LineBreak || word == ParagraphBreak
+}
+
+// WithBreaks returns tokens with a break token inserted wherever the gap
+// between two words in text holds a line break. One line break makes a
+// LineBreak and a blank line, or a Unicode paragraph separator, makes a
+// ParagraphBreak. Breaks before the first and after the last word are
+// ignored. tokens must come from text, so their offsets index into it.
+func WithBreaks(text string, tokens []Token) []Token {
+	if len(tokens) < 2 {
+		return tokens
+	}
+	runes := []rune(text)
+	out := make([]Token, 0, len(tokens))
+	out = append(out, tokens[0])
+	for k := 1; k < len(tokens); k++ {
+		prev, next := tokens[k-1], tokens[k]
+		if lines := countLineBreaks(runes[prev.End:next.Start]); lines > 0 {
+			brk := Token{Text: LineBreak, Start: prev.End, End: next.Start}
+			if lines > 1 {
+				brk.Text = ParagraphBreak
+			}
+			out = append(out, brk)
+		}
+		out = append(out, next)
+	}
+	return out
+}
+
+// countLineBreaks counts the line breaks in a run of separators, treating
+// "\r\n" as one and a paragraph separator as two.
+func countLineBreaks(gap []rune) int {
+	n := 0
+	for i, r := range gap {
+		switch r {
+		case '\n':
+			if i == 0 || gap[i-1] != '\r' {
+				n++
+			}
+		case '\r', '\u2028', '\u0085':
+			n++
+		case '\u2029':
+			n += 2
+		}
+	}
+	return n
+}
@@ -0,0 +1,100 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithBreaks(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "NoBreaks",
+			text: "one two  three",
+			want: []string{"one", "two", "three"},
+		},
+		{
+			name: "LineBreak",
+			text: "one\ntwo\r\nthree",
+			want: []string{"one", LineBreak, "two", LineBreak, "three"},
+		},
+		{
+			name: "ParagraphBreak",
+			text: "one.\n\n\ntwo.\r\n  \r\nthree",
+			want: []string{"one.", ParagraphBreak, "two.", ParagraphBreak, "three"},
+		},
+		{
+			name: "UnicodeSeparators",
+			text: "one\u2028two\u2029three",
+			want: []string{"one", LineBreak, "two", ParagraphBreak, "three"},
+		},
+		{
+			name: "OuterBreaksIgnored",
+			text: "\n\none two\n",
+			want: []string{"one", "two"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tokens := WithBreaks(tc.text, Tokenize(tc.text))
+			require.Equal(t, tc.want, Words(tokens))
+		})
+	}
+}
+
+func TestScoreLayout(t *testing.T) {
+	original := "Dear Sir,\n\nThank you for the letter.\nYours truly"
+	typed := "Dear Sir, Thank you for the letter.\n\nYours truly"
+
+	result, err := WordAlignScorer{}.Score(Input{Original: original, Typed: typed})
+	require.NoError(t, err)
+	require.Zero(t, result.Errors.Total())
+
+	result, err = WordAlignScorer{}.Score(Input{
+		Original: original,
+		Typed:    typed,
+		Options:  Options{Layout: true, Rubric: "steno"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 9, result.TotalWords)
+	require.Equal(t, 9, result.CorrectWords)
+	require.InDelta(t, 100.0, result.Accuracy, 0.001)
+	require.InDelta(t, 100.0, result.WeightedAccuracy, 0.001)
+	require.Equal(t, ErrorCounts{Paragraph: 2}, result.Errors)
+	require.Equal(t, 2, result.MarkSheet.Paragraphing)
+	require.Equal(t, 2, result.MarkSheet.HalfMistakes)
+
+	var breaks []ComparisonToken
+	for _, token := range result.Comparison.Tokens {
+		if token.Category == CategoryParagraph {
+			breaks = append(breaks, token)
+		}
+	}
+	require.Len(t, breaks, 2)
+
+	// The missed paragraph break spans the blank line in the original
+	missed := breaks[0]
+	require.Equal(t, OpOmit, missed.Op)
+	require.Equal(t, ParagraphBreak, missed.Original)
+	require.Equal(t, "\n\n", string([]rune(original)[missed.OriginalSpan.Start:missed.OriginalSpan.End]))
+
+	// A line break typed as a paragraph break is a single mistake
+	wrong := breaks[1]
+	require.Equal(t, OpSubstitute, wrong.Op)
+	require.Equal(t, LineBreak, wrong.Original)
+	require.Equal(t, ParagraphBreak, wrong.Typed)
+}
+
+func TestAlignNeverSubstitutesBreaks(t *testing.T) {
+	a := Align([]string{"one", ParagraphBreak, "two"}, []string{"one", "three", "two"})
+	require.Equal(t, 2, a.Matched)
+	require.Equal(t, 0, a.Substituted)
+	require.Equal(t, 1, a.Inserted)
+	require.Equal(t, 0, a.Omitted)
+	require.Equal(t, ErrorCounts{Insertion: 1, Paragraph: 1}, CountErrors(a))
+}
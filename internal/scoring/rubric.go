@@ -0,0 +1,131 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Options are the per-dictation scoring settings stored in
+// dictations.scoring_options.
+type Options struct {
+	// Rubric names a registered Rubric used to grade attempts. Empty means
+	// attempts only get the plain accuracy score.
+	Rubric string `json:"rubric,omitempty"`
+	// PassThreshold overrides the rubric's default maximum error percentage.
+	PassThreshold float64 `json:"pass_threshold,omitempty"`
+	// Tolerance lists the spelling, number and phrasing differences accepted
+	// as matches. When set it replaces the attempting user's own profile.
+	Tolerance *Tolerance `json:"tolerance,omitempty"`
+	// Layout keeps line and paragraph breaks as tokens, so a missed or extra
+	// break is scored as a paragraph mistake.
+	Layout bool `json:"layout,omitempty"`
+}
+
+// ParseOptions decodes stored scoring options. Empty input yields the zero
+// Options.
+func ParseOptions(data []byte) (Options, error) {
+	var opts Options
+	if len(data) == 0 {
+		return opts, nil
+	}
+	if err := json.Unmarshal(data, &opts); err != nil {
+		return opts, fmt.Errorf("invalid scoring options: %w", err)
+	}
+	return opts, nil
+}
+
+// Validate checks that the options refer to a known rubric and tolerance
+// profile.
+func (o Options) Validate() error {
+	if o.Rubric != "" {
+		if _, ok := LookupRubric(o.Rubric); !ok {
+			return fmt.Errorf("unknown scoring rubric %q", o.Rubric)
+		}
+	}
+	if o.PassThreshold < 0 || o.PassThreshold > 100 {
+		return fmt.Errorf("pass_threshold must be between 0 and 100")
+	}
+	if o.Tolerance != nil {
+		return o.Tolerance.Validate()
+	}
+	return nil
+}
+
+// MarkSheet is an exam-style grade for a single attempt.
+type MarkSheet struct {
+	Rubric          string  `json:"rubric"`
+	TotalWords      int     `json:"total_words"`
+	FullMistakes    int     `json:"full_mistakes"`
+	HalfMistakes    int     `json:"half_mistakes"`
+	Omissions       int     `json:"omissions"`
+	Substitutions   int     `json:"substitutions"`
+	Additions       int     `json:"additions"`
+	Spelling        int     `json:"spelling"`
+	Capitalisation  int     `json:"capitalisation"`
+	Punctuation     int     `json:"punctuation"`
+	Paragraphing    int     `json:"paragraphing"`
+	ErrorPercentage float64 `json:"error_percentage"`
+	PassThreshold   float64 `json:"pass_threshold"`
+	Passed          bool    `json:"passed"`
+}
+
+// Rubric turns classified mistakes into a mark sheet.
+type Rubric interface {
+	Name() string
+	Grade(counts ErrorCounts, totalWords int, opts Options) MarkSheet
+}
+
+var (
+	rubricsMu sync.RWMutex
+	rubrics   = map[string]Rubric{}
+)
+
+// RegisterRubric makes a rubric available to dictations by name. It panics if
+// the name is already taken.
+func RegisterRubric(r Rubric) {
+	rubricsMu.Lock()
+	defer rubricsMu.Unlock()
+	if _, dup := rubrics[r.Name()]; dup {
+		panic("scoring: rubric registered twice: " + r.Name())
+	}
+	rubrics[r.Name()] = r
+}
+
+// LookupRubric returns the rubric registered under name.
+func LookupRubric(name string) (Rubric, bool) {
+	rubricsMu.RLock()
+	defer rubricsMu.RUnlock()
+	r, ok := rubrics[name]
+	return r, ok
+}
+
+// Rubrics returns the names of all registered rubrics in sorted order.
+func Rubrics() []string {
+	rubricsMu.RLock()
+	defer rubricsMu.RUnlock()
+	names := make([]string, 0, len(rubrics))
+	for name := range rubrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Grade applies the rubric named in opts, returning nil when no rubric is set.
+func Grade(counts ErrorCounts, totalWords int, opts Options) (*MarkSheet, error) {
+	if opts.Rubric == "" {
+		return nil, nil
+	}
+	r, ok := LookupRubric(opts.Rubric)
+	if !ok {
+		return nil, fmt.Errorf("unknown scoring rubric %q", opts.Rubric)
+	}
+	sheet := r.Grade(counts, totalWords, opts)
+	return &sheet, nil
+}
+
+func init() {
+	RegisterRubric(StenoRubric{})
+}
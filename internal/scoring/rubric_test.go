@@ -0,0 +1,55 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStenoRubric(t *testing.T) {
+	counts := ErrorCounts{
+		Case:        2,
+		Punctuation: 1,
+		Spelling:    1,
+		WrongWord:   1,
+		Omission:    2,
+		Insertion:   1,
+	}
+
+	sheet, err := Grade(counts, 200, Options{Rubric: "steno"})
+	require.NoError(t, err)
+	require.NotNil(t, sheet)
+
+	require.Equal(t, 4, sheet.FullMistakes)
+	require.Equal(t, 4, sheet.HalfMistakes)
+	require.InDelta(t, 3.0, sheet.ErrorPercentage, 0.001)
+	require.Equal(t, DefaultStenoPassThreshold, sheet.PassThreshold)
+	require.True(t, sheet.Passed)
+
+	sheet, err = Grade(counts, 200, Options{Rubric: "steno", PassThreshold: 2})
+	require.NoError(t, err)
+	require.False(t, sheet.Passed)
+}
+
+func TestGradeWithoutRubric(t *testing.T) {
+	sheet, err := Grade(ErrorCounts{Omission: 3}, 10, Options{})
+	require.NoError(t, err)
+	require.Nil(t, sheet)
+
+	_, err = Grade(ErrorCounts{}, 10, Options{Rubric: "unknown"})
+	require.Error(t, err)
+}
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions(nil)
+	require.NoError(t, err)
+	require.Equal(t, Options{}, opts)
+
+	opts, err = ParseOptions([]byte(`{"rubric":"steno","pass_threshold":7}`))
+	require.NoError(t, err)
+	require.Equal(t, Options{Rubric: "steno", PassThreshold: 7}, opts)
+	require.NoError(t, opts.Validate())
+
+	require.Error(t, Options{Rubric: "unknown"}.Validate())
+	require.Error(t, Options{PassThreshold: 120}.Validate())
+}
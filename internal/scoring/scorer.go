@@ -0,0 +1,233 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+)
+
+// Input is everything a Scorer needs to grade one attempt.
+type Input struct {
+	Original string
+	Typed    string
+	Options  Options
+	// Language is the dictation's BCP 47 language tag; it picks the Analyzer.
+	Language string
+	// Seconds is how long the attempt took, used for speed metrics.
+	Seconds float64
+	// Keystrokes is the number of keys pressed, including corrections, if the
+	// client reported it.
+	Keystrokes int
+	// UserTolerance is the attempting user's tolerance profile. It applies
+	// when Options does not set one.
+	UserTolerance *Tolerance
+	// Lexicon is the pronunciation lexicon the original was read aloud with,
+	// the dictation's entries before the user's.
+	Lexicon []lexicon.Entry
+}
+
+// Result is the outcome of scoring an attempt.
+type Result struct {
+	// Version identifies the scorer that produced the result, see VersionOf.
+	Version      string
+	TotalWords   int
+	CorrectWords int
+	Accuracy     float64
+	// WeightedAccuracy gives near-miss words partial credit.
+	WeightedAccuracy float64
+	Errors           ErrorCounts
+	Speed            Speed
+	Comparison       Comparison
+	MarkSheet        *MarkSheet
+}
+
+// ComparisonJSON encodes the comparison for attempts.comparison_data.
+func (r Result) ComparisonJSON() ([]byte, error) {
+	return json.Marshal(r.Comparison)
+}
+
+// MarkSheetJSON encodes the mark sheet for attempts.mark_sheet, returning nil
+// when the attempt was not graded against a rubric.
+func (r Result) MarkSheetJSON() ([]byte, error) {
+	if r.MarkSheet == nil {
+		return nil, nil
+	}
+	return json.Marshal(r.MarkSheet)
+}
+
+// Scorer grades attempts. Implementations must be deterministic: scoring the
+// same input with the same Name and Version always gives the same Result, so
+// that a stored scoring_version tells exactly how an attempt was graded.
+type Scorer interface {
+	Name() string
+	// Version is bumped whenever a change alters the result for some input.
+	Version() int
+	Score(in Input) (Result, error)
+}
+
+// VersionOf returns the identifier stored in attempts.scoring_version, such
+// as "wordalign/v5".
+func VersionOf(s Scorer) string {
+	return fmt.Sprintf("%s/v%d", s.Name(), s.Version())
+}
+
+var (
+	scorersMu sync.RWMutex
+	scorers   = map[string]Scorer{}
+)
+
+// RegisterScorer makes a scorer available by its VersionOf identifier. It
+// panics if that identifier is already taken.
+func RegisterScorer(s Scorer) {
+	scorersMu.Lock()
+	defer scorersMu.Unlock()
+	id := VersionOf(s)
+	if _, dup := scorers[id]; dup {
+		panic("scoring: scorer registered twice: " + id)
+	}
+	scorers[id] = s
+}
+
+// LookupScorer returns the scorer registered under id. A bare name such as
+// "wordalign" returns the latest registered version of that scorer, and an
+// empty id returns DefaultScorer.
+func LookupScorer(id string) (Scorer, bool) {
+	if id == "" {
+		return DefaultScorer(), true
+	}
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	if s, ok := scorers[id]; ok {
+		return s, true
+	}
+	var latest Scorer
+	for _, s := range scorers {
+		if s.Name() == id && (latest == nil || s.Version() > latest.Version()) {
+			latest = s
+		}
+	}
+	return latest, latest != nil
+}
+
+// Scorers returns the identifiers of all registered scorers in sorted order.
+func Scorers() []string {
+	scorersMu.RLock()
+	defer scorersMu.RUnlock()
+	ids := make([]string, 0, len(scorers))
+	for id := range scorers {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// DefaultScorer is the scorer used for new attempts unless configured
+// otherwise.
+func DefaultScorer() Scorer {
+	return WordAlignScorer{}
+}
+
+func init() {
+	for release := 1; release <= wordAlignLatest; release++ {
+		RegisterScorer(WordAlignScorer{Release: release})
+	}
+}
+
+// wordAlignLatest is the current version of WordAlignScorer.
+const wordAlignLatest = 7
+
+// WordAlignScorer scores attempts with a word-level alignment, classifies
+// every mistake and grades the result with the dictation's rubric.
+//
+// Versions:
+//   - v1: word alignment, error categories and rubrics.
+//   - v2: typing speed metrics.
+//   - v3: language-aware tokenization and Unicode normalization.
+//   - v4: tolerance rules for spelling variants, numbers, contractions,
+//     homophones and custom alternatives.
+//   - v5: weighted accuracy with partial credit for near-miss words.
+//   - v6: layout-aware scoring of line and paragraph breaks.
+//   - v7: spoken forms of abbreviations, numbers and lexicon entries, as
+//     read aloud, are accepted for the written ones.
+//
+// Every version stays registered. An earlier version is the current code
+// with the features added after it switched off, so it reproduces what that
+// version measured, though fixes made since apply to it as well.
+type WordAlignScorer struct {
+	// Release pins an earlier version; zero is the latest.
+	Release int
+}
+
+func (WordAlignScorer) Name() string {
+	return "wordalign"
+}
+
+func (s WordAlignScorer) Version() int {
+	if s.Release == 0 {
+		return wordAlignLatest
+	}
+	return s.Release
+}
+
+func (s WordAlignScorer) Score(in Input) (Result, error) {
+	version := s.Version()
+	if version < 1 || version > wordAlignLatest {
+		return Result{}, fmt.Errorf("no wordalign version %d", version)
+	}
+
+	tokenize := Tokenize
+	if version >= 3 {
+		tokenize = AnalyzerFor(in.Language).Tokenize
+	}
+	originalTokens := tokenize(in.Original)
+	typedTokens := tokenize(in.Typed)
+
+	var tolerance *Tolerance
+	if version >= 4 {
+		tolerance = in.Options.Tolerance
+		if tolerance == nil {
+			tolerance = in.UserTolerance
+		}
+	}
+	if version >= 7 {
+		// Accept what the learner heard, "Doctor" for "Dr."
+		_, forms := lexicon.Verbalize(in.Original, in.Language, in.Lexicon)
+		tolerance = tolerance.WithPronunciations(forms)
+	}
+
+	originalWords, typedWords := originalTokens, typedTokens
+	if in.Options.Layout && version >= 6 {
+		originalTokens = WithBreaks(in.Original, originalTokens)
+		typedTokens = WithBreaks(in.Typed, typedTokens)
+	}
+
+	// Align word sequences so a dropped or extra word only costs that word
+	alignment := AlignWith(Words(originalTokens), Words(typedTokens), tolerance)
+
+	result := Result{
+		Version:      VersionOf(s),
+		TotalWords:   len(originalWords),
+		CorrectWords: alignment.Matched,
+		Accuracy:     alignment.Accuracy(),
+		Errors:       CountErrors(alignment),
+		Comparison:   NewComparison(alignment, originalTokens, typedTokens),
+	}
+	// Without partial credit, every word counts in full or not at all
+	result.WeightedAccuracy = result.Accuracy
+	if version >= 5 {
+		result.WeightedAccuracy = WeightedAccuracy(alignment)
+	}
+	if version >= 2 {
+		result.Speed = MeasureSpeed(alignment, originalWords, typedWords, in.Seconds, result.Errors.Total(), in.Keystrokes)
+	}
+
+	markSheet, err := Grade(result.Errors, result.TotalWords, in.Options)
+	if err != nil {
+		return Result{}, err
+	}
+	result.MarkSheet = markSheet
+	return result, nil
+}
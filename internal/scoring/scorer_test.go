@@ -0,0 +1,86 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLookupScorer(t *testing.T) {
+	s, ok := LookupScorer("")
+	require.True(t, ok)
+	require.Equal(t, VersionOf(DefaultScorer()), VersionOf(s))
+
+	s, ok = LookupScorer("wordalign/v7")
+	require.True(t, ok)
+	require.Equal(t, "wordalign", s.Name())
+
+	s, ok = LookupScorer("wordalign")
+	require.True(t, ok)
+	require.Equal(t, VersionOf(DefaultScorer()), VersionOf(s))
+
+	_, ok = LookupScorer("wordalign/v0")
+	require.False(t, ok)
+	require.Contains(t, Scorers(), "wordalign/v7")
+}
+
+func TestWordAlignScorer(t *testing.T) {
+	result, err := WordAlignScorer{}.Score(Input{
+		Original: "The quick brown fox",
+		Typed:    "the quick fox",
+		Options:  Options{Rubric: "steno"},
+	})
+	require.NoError(t, err)
+
+	require.Equal(t, "wordalign/v7", result.Version)
+	require.Equal(t, 4, result.TotalWords)
+	require.Equal(t, 2, result.CorrectWords)
+	require.InDelta(t, 50.0, result.Accuracy, 0.001)
+	require.Equal(t, ErrorCounts{Case: 1, Omission: 1}, result.Errors)
+	require.Len(t, result.Comparison.Tokens, 4)
+	require.NotNil(t, result.MarkSheet)
+	require.Equal(t, 1, result.MarkSheet.FullMistakes)
+	require.Equal(t, 1, result.MarkSheet.HalfMistakes)
+
+	sheet, err := result.MarkSheetJSON()
+	require.NoError(t, err)
+	require.Contains(t, string(sheet), `"rubric":"steno"`)
+}
+
+func TestWordAlignScorerVersions(t *testing.T) {
+	in := Input{Original: "Dr. Smith arrived", Typed: "Doctor Smith arived", Seconds: 60}
+
+	testCases := []struct {
+		id           string
+		correctWords int
+		// partialCredit is set when the misspelling earns some credit
+		partialCredit bool
+		timed         bool
+	}{
+		{id: "wordalign/v1", correctWords: 1},
+		{id: "wordalign/v2", correctWords: 1, timed: true},
+		{id: "wordalign/v5", correctWords: 1, partialCredit: true, timed: true},
+		{id: "wordalign/v7", correctWords: 2, partialCredit: true, timed: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.id, func(t *testing.T) {
+			scorer, ok := LookupScorer(tc.id)
+			require.True(t, ok)
+
+			result, err := scorer.Score(in)
+			require.NoError(t, err)
+			require.Equal(t, tc.id, result.Version)
+			require.Equal(t, tc.correctWords, result.CorrectWords)
+			if tc.partialCredit {
+				require.Greater(t, result.WeightedAccuracy, result.Accuracy)
+			} else {
+				require.Equal(t, result.Accuracy, result.WeightedAccuracy)
+			}
+			require.Equal(t, tc.timed, result.Speed.CPM > 0)
+		})
+	}
+
+	_, err := WordAlignScorer{Release: 8}.Score(in)
+	require.Error(t, err)
+}
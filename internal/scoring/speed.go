@@ -0,0 +1,95 @@
+package scoring
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// charsPerWord is the standard word length used for WPM: five keystrokes,
+// spaces and punctuation included.
+const charsPerWord = 5
+
+// Speed holds the typing speed metrics of an attempt. WPM and CPM are zero
+// when the attempt has no duration, and KSPC is zero when the client did not
+// report a keystroke count.
+type Speed struct {
+	// GrossWPM is typed characters / 5 per minute, regardless of mistakes.
+	GrossWPM float64
+	// NetWPM is GrossWPM less one word per minute for every uncorrected
+	// mistake, never below zero.
+	NetWPM float64
+	// CPM is typed characters per minute.
+	CPM float64
+	// CharAccuracy is the percentage of original characters reproduced,
+	// from the character edit distances of the aligned words.
+	CharAccuracy float64
+	// KSPC is keystrokes per character of the typed text. Values above one
+	// mean the typist corrected as they went.
+	KSPC float64
+}
+
+// MeasureSpeed computes the speed metrics for typed against original, given
+// their word alignment, the time taken in seconds, the number of word-level
+// mistakes left in the text and the raw keystroke count (zero if unknown).
+func MeasureSpeed(a Alignment, original, typed []Token, seconds float64, mistakes, keystrokes int) Speed {
+	var s Speed
+
+	// Whitespace is normalised to single spaces so that line breaks or double
+	// spaces don't count as typed characters or character errors
+	typedChars := utf8.RuneCountInString(joinTokens(typed))
+
+	if originalChars := utf8.RuneCountInString(joinTokens(original)); originalChars > 0 {
+		correct := originalChars - charErrors(a)
+		s.CharAccuracy = float64(max(correct, 0)) / float64(originalChars) * 100
+	}
+
+	if seconds > 0 {
+		minutes := seconds / 60
+		s.CPM = float64(typedChars) / minutes
+		s.GrossWPM = s.CPM / charsPerWord
+		s.NetWPM = max(s.GrossWPM-float64(mistakes)/minutes, 0)
+	}
+
+	if keystrokes > 0 && typedChars > 0 {
+		s.KSPC = float64(keystrokes) / float64(typedChars)
+	}
+	return s
+}
+
+// charErrors counts the character edits along an alignment: the edit
+// distance of every pair of words that differ and every character of an
+// omitted or extra word. Working word by word keeps the cost in proportion to
+// the length of the texts rather than its square.
+func charErrors(a Alignment) int {
+	n := 0
+	for _, op := range a.Ops {
+		if IsBreak(op.Original) || IsBreak(op.Typed) {
+			continue
+		}
+		switch op.Kind {
+		case OpMatch, OpSubstitute:
+			if op.Original != op.Typed {
+				n += charDistance(op.Original, op.Typed)
+			}
+		case OpOmit:
+			n += utf8.RuneCountInString(op.Original)
+		case OpInsert:
+			n += utf8.RuneCountInString(op.Typed)
+		}
+	}
+	return n
+}
+
+// joinTokens rebuilds the text of tokens with a single space wherever the
+// source had white space, so words written without spaces (as in Chinese)
+// stay joined.
+func joinTokens(tokens []Token) string {
+	var b strings.Builder
+	for i, t := range tokens {
+		if i > 0 && t.Start > tokens[i-1].End {
+			b.WriteByte(' ')
+		}
+		b.WriteString(t.Text)
+	}
+	return b.String()
+}
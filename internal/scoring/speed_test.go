@@ -0,0 +1,121 @@
+package scoring
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeasureSpeed(t *testing.T) {
+	testCases := []struct {
+		name       string
+		original   string
+		typed      string
+		seconds    float64
+		mistakes   int
+		keystrokes int
+		want       Speed
+	}{
+		{
+			// 30 characters in 30 seconds: 60 CPM, 12 WPM
+			name:       "Perfect",
+			original:   "the quick brown fox jumps over",
+			typed:      "the quick brown fox jumps over",
+			seconds:    30,
+			keystrokes: 36,
+			want:       Speed{GrossWPM: 12, NetWPM: 12, CPM: 60, CharAccuracy: 100, KSPC: 1.2},
+		},
+		{
+			// Each uncorrected mistake costs one word per minute
+			name:     "NetPenalisedByMistakes",
+			original: "the quick brown fox jumps over",
+			typed:    "the quick brown fox jumps over",
+			seconds:  30,
+			mistakes: 3,
+			want:     Speed{GrossWPM: 12, NetWPM: 6, CPM: 60, CharAccuracy: 100},
+		},
+		{
+			name:     "NetNeverNegative",
+			original: "the quick brown fox jumps over",
+			typed:    "the quick brown fox jumps over",
+			seconds:  30,
+			mistakes: 10,
+			want:     Speed{GrossWPM: 12, NetWPM: 0, CPM: 60, CharAccuracy: 100},
+		},
+		{
+			// Line breaks and double spaces are not extra characters
+			name:     "WhitespaceNormalised",
+			original: "hello world",
+			typed:    "hello\n\nworld  ",
+			seconds:  60,
+			want:     Speed{GrossWPM: 11.0 / 5, NetWPM: 11.0 / 5, CPM: 11, CharAccuracy: 100},
+		},
+		{
+			// One typo in ten characters
+			name:     "CharAccuracy",
+			original: "typing fun",
+			typed:    "typinf fun",
+			want:     Speed{CharAccuracy: 90},
+		},
+		{
+			// A missed and an extra word cost their characters
+			name:     "OmittedAndInserted",
+			original: "typing is fun",
+			typed:    "typing fun too",
+			want:     Speed{CharAccuracy: 100 - 500.0/13},
+		},
+		{
+			name:     "NothingTyped",
+			original: "abc",
+			typed:    "",
+			seconds:  10,
+			want:     Speed{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			original, typed := Tokenize(tc.original), Tokenize(tc.typed)
+			alignment := Align(Words(original), Words(typed))
+			got := MeasureSpeed(alignment, original, typed, tc.seconds, tc.mistakes, tc.keystrokes)
+			require.InDelta(t, tc.want.GrossWPM, got.GrossWPM, 0.001)
+			require.InDelta(t, tc.want.NetWPM, got.NetWPM, 0.001)
+			require.InDelta(t, tc.want.CPM, got.CPM, 0.001)
+			require.InDelta(t, tc.want.CharAccuracy, got.CharAccuracy, 0.001)
+			require.InDelta(t, tc.want.KSPC, got.KSPC, 0.001)
+		})
+	}
+}
+
+func TestMeasureSpeedLongText(t *testing.T) {
+	// 1,000 words with every tenth one misspelt, and one very long word
+	words := make([]string, 1000)
+	typed := make([]string, len(words))
+	for i := range words {
+		words[i] = "word"
+		typed[i] = "word"
+		if i%10 == 0 {
+			typed[i] = "wrod"
+		}
+	}
+	words[len(words)-1] = strings.Repeat("a", 10000)
+	typed[len(typed)-1] = strings.Repeat("b", 10000)
+	original, typedTokens := Tokenize(strings.Join(words, " ")), Tokenize(strings.Join(typed, " "))
+	alignment := Align(Words(original), Words(typedTokens))
+
+	var before, after runtime.MemStats
+	runtime.ReadMemStats(&before)
+	start := time.Now()
+	got := MeasureSpeed(alignment, original, typedTokens, 60, 0, 0)
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	// 100 swaps of one edit each and 10,000 wrong characters
+	originalChars := 999*5 + 10000
+	require.InDelta(t, float64(originalChars-100-10000)/float64(originalChars)*100, got.CharAccuracy, 0.001)
+	require.Less(t, elapsed, time.Second)
+	require.Less(t, after.TotalAlloc-before.TotalAlloc, uint64(16<<20))
+}
@@ -0,0 +1,44 @@
+package scoring
+
+// DefaultStenoPassThreshold is the highest error percentage that still passes
+// a stenography skill test unless the dictation sets its own threshold.
+const DefaultStenoPassThreshold = 5.0
+
+// StenoRubric marks attempts the way government stenographer skill tests do.
+// Omitted, substituted and added words are full mistakes; spelling,
+// capitalisation, punctuation and paragraphing slips are half mistakes. The
+// error percentage is (full + half/2) / total words.
+type StenoRubric struct{}
+
+func (StenoRubric) Name() string {
+	return "steno"
+}
+
+func (r StenoRubric) Grade(counts ErrorCounts, totalWords int, opts Options) MarkSheet {
+	threshold := opts.PassThreshold
+	if threshold == 0 {
+		threshold = DefaultStenoPassThreshold
+	}
+
+	sheet := MarkSheet{
+		Rubric:         r.Name(),
+		TotalWords:     totalWords,
+		Omissions:      counts.Omission,
+		Substitutions:  counts.WrongWord,
+		Additions:      counts.Insertion,
+		Spelling:       counts.Spelling,
+		Capitalisation: counts.Case,
+		Punctuation:    counts.Punctuation,
+		Paragraphing:   counts.Paragraph,
+		PassThreshold:  threshold,
+	}
+	sheet.FullMistakes = sheet.Omissions + sheet.Substitutions + sheet.Additions
+	sheet.HalfMistakes = sheet.Spelling + sheet.Capitalisation + sheet.Punctuation + sheet.Paragraphing
+
+	if totalWords > 0 {
+		weighted := float64(sheet.FullMistakes) + float64(sheet.HalfMistakes)/2
+		sheet.ErrorPercentage = weighted / float64(totalWords) * 100
+		sheet.Passed = sheet.ErrorPercentage <= threshold
+	}
+	return sheet
+}
@@ -0,0 +1,87 @@
+package scoring
+
+import "unicode"
+
+// Token is a word together with its position in the source text. Start and
+// End are half-open offsets counted in Unicode code points, not bytes. When a
+// token comes from an Analyzer, Text is the normalized form that was compared
+// while the offsets still point into the unnormalized source.
+type Token struct {
+	Text  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text on white space, like strings.Fields, but keeps the
+// offsets of every word so results can be mapped back onto the source text.
+// It does not normalize; use an Analyzer for that.
+func Tokenize(text string) []Token {
+	return segment([]rune(text), false)
+}
+
+// Words returns the text of each token.
+func Words(tokens []Token) []string {
+	words := make([]string, len(tokens))
+	for i, t := range tokens {
+		words[i] = t.Text
+	}
+	return words
+}
+
+// segment splits runes into tokens at separators. With ideographs set, every
+// Han, Hiragana and Katakana character is a token of its own, keeping any
+// punctuation written right before or after it.
+func segment(runes []rune, ideographs bool) []Token {
+	var tokens []Token
+	start := -1
+	// ideographic is set while the current token holds an ideograph, and
+	// leading is set while it holds nothing but punctuation.
+	ideographic, leading := false, false
+
+	flush := func(end int) {
+		if start >= 0 {
+			tokens = append(tokens, Token{Text: string(runes[start:end]), Start: start, End: end})
+			start = -1
+		}
+	}
+
+	for i, r := range runes {
+		switch {
+		case isSeparator(r):
+			flush(i)
+		case ideographs && isIdeograph(r):
+			if start >= 0 && !leading {
+				flush(i)
+			}
+			if start < 0 {
+				start = i
+			}
+			ideographic, leading = true, false
+		case unicode.IsPunct(r):
+			if start < 0 {
+				start = i
+				ideographic, leading = false, true
+			}
+		default:
+			if start >= 0 && ideographic {
+				flush(i)
+			}
+			if start < 0 {
+				start = i
+			}
+			ideographic, leading = false, false
+		}
+	}
+	flush(len(runes))
+	return tokens
+}
+
+// isSeparator reports whether r separates words. Zero width spaces and byte
+// order marks count, as some editors insert them between words.
+func isSeparator(r rune) bool {
+	return unicode.IsSpace(r) || r == '\u200b' || r == '\ufeff'
+}
+
+func isIdeograph(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
@@ -0,0 +1,498 @@
+package scoring
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+)
+
+// ToleranceRule names the rule that let two differently written words count
+// as a match.
+type ToleranceRule string
+
+const (
+	RuleSpellingVariant ToleranceRule = "spelling_variant"
+	RuleNumber          ToleranceRule = "number"
+	RuleContraction     ToleranceRule = "contraction"
+	RuleHomophone       ToleranceRule = "homophone"
+	RuleAlternative     ToleranceRule = "alternative"
+	RulePronunciation   ToleranceRule = "pronunciation"
+)
+
+const (
+	// maxAlternatives caps the custom alternative groups of one profile.
+	maxAlternatives = 500
+	// maxPhraseWords is the longest phrase a tolerance rule may span.
+	maxPhraseWords = 8
+)
+
+// Tolerance is a set of rules under which a typed word or phrase is accepted
+// for a differently written original. It is stored per dictation in
+// scoring_options and per user in settings.tolerance.
+type Tolerance struct {
+	// Profile names a preset from ToleranceProfiles. Rules enabled below are
+	// added to the preset's.
+	Profile string `json:"profile,omitempty"`
+	// SpellingVariants accepts British and American spellings of a word, such
+	// as "colour" for "color".
+	SpellingVariants bool `json:"spelling_variants,omitempty"`
+	// Numbers accepts numbers written in digits or words, such as "21" for
+	// "twenty-one" and "1st" for "first".
+	Numbers bool `json:"numbers,omitempty"`
+	// Contractions accepts contracted and expanded forms, such as "don't" for
+	// "do not".
+	Contractions bool `json:"contractions,omitempty"`
+	// Homophones accepts words that sound the same, such as "their" for
+	// "there".
+	Homophones bool `json:"homophones,omitempty"`
+	// Alternatives are groups of words or phrases that are accepted for each
+	// other, such as ["okay", "OK"].
+	Alternatives [][]string `json:"alternatives,omitempty"`
+
+	// pronunciations are the replacements made when the original was read
+	// aloud. They are set by the scorer, never stored.
+	pronunciations []lexicon.Entry
+}
+
+var toleranceProfiles = map[string]Tolerance{
+	"strict":   {},
+	"standard": {SpellingVariants: true, Numbers: true, Contractions: true},
+	"lenient":  {SpellingVariants: true, Numbers: true, Contractions: true, Homophones: true},
+}
+
+// ToleranceProfiles returns the names of the preset profiles in sorted order.
+func ToleranceProfiles() []string {
+	names := make([]string, 0, len(toleranceProfiles))
+	for name := range toleranceProfiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ParseTolerance decodes a stored tolerance profile. Empty input yields nil.
+func ParseTolerance(data []byte) (*Tolerance, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var t Tolerance
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("invalid tolerance: %w", err)
+	}
+	return &t, nil
+}
+
+// Validate checks that the profile exists and every alternative group names
+// at least two phrases.
+func (t Tolerance) Validate() error {
+	if t.Profile != "" {
+		if _, ok := toleranceProfiles[t.Profile]; !ok {
+			return fmt.Errorf("unknown tolerance profile %q", t.Profile)
+		}
+	}
+	if len(t.Alternatives) > maxAlternatives {
+		return fmt.Errorf("at most %d alternative groups are allowed", maxAlternatives)
+	}
+	for i, group := range t.Alternatives {
+		if len(group) < 2 {
+			return fmt.Errorf("alternative group %d needs at least two phrases", i)
+		}
+		for _, phrase := range group {
+			n := len(strings.Fields(phrase))
+			if n == 0 {
+				return fmt.Errorf("alternative group %d has an empty phrase", i)
+			}
+			if n > maxPhraseWords {
+				return fmt.Errorf("alternative group %d has a phrase longer than %d words", i, maxPhraseWords)
+			}
+		}
+	}
+	return nil
+}
+
+// WithPronunciations returns a copy of t that also accepts the spoken form
+// of each replacement lexicon.Verbalize made in the original, such as
+// "Doctor" for "Dr.". t may be nil.
+func (t *Tolerance) WithPronunciations(forms []lexicon.Entry) *Tolerance {
+	if len(forms) == 0 {
+		return t
+	}
+	var with Tolerance
+	if t != nil {
+		with = *t
+	}
+	with.pronunciations = forms
+	return &with
+}
+
+// resolved returns t with its profile's rules merged in.
+func (t Tolerance) resolved() Tolerance {
+	preset := toleranceProfiles[t.Profile]
+	t.SpellingVariants = t.SpellingVariants || preset.SpellingVariants
+	t.Numbers = t.Numbers || preset.Numbers
+	t.Contractions = t.Contractions || preset.Contractions
+	t.Homophones = t.Homophones || preset.Homophones
+	return t
+}
+
+// phraseKey is a canonical form a phrase has under one rule. Two phrases with
+// a key in common are equivalent under that rule.
+type phraseKey struct {
+	key  string
+	rule ToleranceRule
+}
+
+// phrase is a run of consecutive words that some rule recognises. Only the
+// outside of the phrase may carry punctuation, and that punctuation, like the
+// capitalisation of the first letter, has to agree for two phrases to match
+// so that "Colour," typed as "color" is still a punctuation mistake.
+type phrase struct {
+	words    int
+	bare     string
+	leading  string
+	trailing string
+	caps     int
+	keys     []phraseKey
+}
+
+const (
+	capsNone = iota
+	capsLower
+	capsUpper
+)
+
+func (p phrase) equivalent(q phrase) (ToleranceRule, bool) {
+	if p.bare == q.bare || p.leading != q.leading || p.trailing != q.trailing {
+		return "", false
+	}
+	if p.caps != capsNone && q.caps != capsNone && p.caps != q.caps {
+		return "", false
+	}
+	for _, a := range p.keys {
+		for _, b := range q.keys {
+			if a.key == b.key {
+				return a.rule, true
+			}
+		}
+	}
+	return "", false
+}
+
+// matcher finds the phrases a tolerance recognises in a list of words.
+type matcher struct {
+	tolerance    Tolerance
+	alternatives map[string]int
+	// pronunciations maps the written and spoken forms of every replacement
+	// to the replacements they belong to.
+	pronunciations map[string][]int
+	longest        int
+}
+
+// newMatcher returns nil when t enables no rule.
+func newMatcher(t *Tolerance) *matcher {
+	if t == nil {
+		return nil
+	}
+	m := &matcher{tolerance: t.resolved(), longest: 1}
+	if m.tolerance.Contractions {
+		m.longest = max(m.longest, 3)
+	}
+	if m.tolerance.Numbers {
+		m.longest = maxPhraseWords
+	}
+	if len(m.tolerance.Alternatives) > 0 {
+		m.alternatives = make(map[string]int)
+		for i, group := range m.tolerance.Alternatives {
+			for _, alt := range group {
+				words := strings.Fields(strings.ToLower(alt))
+				for k, w := range words {
+					_, words[k], _ = splitPunct(w)
+				}
+				m.alternatives[strings.Join(words, " ")] = i
+				m.longest = max(m.longest, len(words))
+			}
+		}
+	}
+	if len(m.tolerance.pronunciations) > 0 {
+		m.pronunciations = make(map[string][]int)
+		written := make(map[string]int)
+		for _, form := range m.tolerance.pronunciations {
+			key := strings.ToLower(strings.Join(strings.Fields(form.Written), " "))
+			group, ok := written[key]
+			if !ok {
+				group = len(written)
+				written[key] = group
+				m.pronunciations[key] = append(m.pronunciations[key], group)
+				m.longest = max(m.longest, len(strings.Fields(key)))
+			}
+			spoken := strings.ToLower(strings.Join(strings.Fields(form.Spoken), " "))
+			for _, key := range []string{spoken, strings.ReplaceAll(spoken, "-", " ")} {
+				if !slices.Contains(m.pronunciations[key], group) {
+					m.pronunciations[key] = append(m.pronunciations[key], group)
+				}
+				m.longest = max(m.longest, len(strings.Fields(key)))
+			}
+		}
+	}
+	tol := m.tolerance
+	if !tol.SpellingVariants && !tol.Numbers && !tol.Contractions && !tol.Homophones && len(m.alternatives) == 0 && len(m.pronunciations) == 0 {
+		return nil
+	}
+	return m
+}
+
+// phrases returns, for every end position i in 0..len(words), the phrases
+// made of words[i-n:i] that at least one rule recognises.
+func (m *matcher) phrases(words []string) [][]phrase {
+	out := make([][]phrase, len(words)+1)
+	for end := 1; end <= len(words); end++ {
+		for n := 1; n <= m.longest && n <= end; n++ {
+			if p, ok := m.phrase(words[end-n : end]); ok {
+				out[end] = append(out[end], p)
+			}
+			out[end] = append(out[end], m.pronounced(words[end-n:end])...)
+		}
+	}
+	return out
+}
+
+func (m *matcher) phrase(words []string) (phrase, bool) {
+	p := phrase{words: len(words)}
+	bare := make([]string, len(words))
+	for i, w := range words {
+		lead, core, trail := splitPunct(w)
+		if core == "" || (i > 0 && lead != "") || (i < len(words)-1 && trail != "") {
+			return phrase{}, false
+		}
+		if i == 0 {
+			p.leading = lead
+			p.caps = capsOf(core)
+		}
+		if i == len(words)-1 {
+			p.trailing = trail
+		}
+		bare[i] = strings.ToLower(core)
+	}
+	p.bare = strings.Join(bare, " ")
+
+	add := func(rule ToleranceRule, key string) {
+		p.keys = append(p.keys, phraseKey{key: string(rule) + ":" + key, rule: rule})
+	}
+	tol := m.tolerance
+	if len(words) == 1 {
+		if us, ok := spellingVariants[p.bare]; ok && tol.SpellingVariants {
+			add(RuleSpellingVariant, us)
+		}
+		if group, ok := homophones[p.bare]; ok && tol.Homophones {
+			add(RuleHomophone, group)
+		}
+		if tol.Contractions {
+			for _, expanded := range contractions[p.bare] {
+				add(RuleContraction, expanded)
+			}
+		}
+	}
+	if tol.Contractions {
+		if canonical, ok := expansions[p.bare]; ok {
+			add(RuleContraction, canonical)
+		}
+	}
+	if tol.Numbers {
+		if n, ok := parseNumber(bare); ok {
+			add(RuleNumber, n)
+		}
+	}
+	if group, ok := m.alternatives[p.bare]; ok {
+		add(RuleAlternative, strconv.Itoa(group))
+	}
+	return p, len(p.keys) > 0
+}
+
+// pronounced returns the phrases words form under the pronunciation rule:
+// the written form of a replacement or the spoken form it was read as.
+// Unlike other rules a written form may have punctuation inside, as "e.g."
+// and "St. Louis" do, so only punctuation before it and after it is split
+// off. A written form ending in a full stop, such as "etc.", also matches at
+// the end of a sentence, where the spoken form is followed by one.
+func (m *matcher) pronounced(words []string) []phrase {
+	if m.pronunciations == nil {
+		return nil
+	}
+	lead, core, _ := splitPunct(words[0])
+	if core == "" {
+		return nil
+	}
+	text := strings.ToLower(strings.Join(words, " "))[len(lead):]
+	_, _, trail := splitPunct(words[len(words)-1])
+	trailRunes := []rune(trail)
+	for k := 0; k <= len(trailRunes); k++ {
+		rest := string(trailRunes[len(trailRunes)-k:])
+		key := strings.TrimSuffix(text, rest)
+		groups, ok := m.pronunciations[key]
+		if !ok {
+			continue
+		}
+		p := phrase{words: len(words), bare: key, leading: lead, trailing: rest, caps: capsOf(core)}
+		for _, group := range groups {
+			p.keys = append(p.keys, phraseKey{key: string(RulePronunciation) + ":" + strconv.Itoa(group), rule: RulePronunciation})
+		}
+		if rest == "" && strings.HasSuffix(key, ".") {
+			sentenceEnd := p
+			sentenceEnd.trailing = "."
+			return []phrase{p, sentenceEnd}
+		}
+		return []phrase{p}
+	}
+	return nil
+}
+
+// splitPunct separates the punctuation before and after a word from its core.
+func splitPunct(word string) (lead, core, trail string) {
+	runes := []rune(word)
+	i, j := 0, len(runes)
+	for i < j && unicode.IsPunct(runes[i]) {
+		i++
+	}
+	for j > i && unicode.IsPunct(runes[j-1]) {
+		j--
+	}
+	return string(runes[:i]), string(runes[i:j]), string(runes[j:])
+}
+
+func capsOf(word string) int {
+	for _, r := range word {
+		switch {
+		case unicode.IsUpper(r):
+			return capsUpper
+		case unicode.IsLower(r):
+			return capsLower
+		}
+		return capsNone
+	}
+	return capsNone
+}
+
+// parseNumber returns the canonical value of a number written in digits
+// ("1,000", "21st") or English words ("one thousand", "twenty-first").
+// Ordinals get a "th" suffix so they only match other ordinals.
+func parseNumber(words []string) (string, bool) {
+	if len(words) == 1 {
+		if n, ok := parseDigits(words[0]); ok {
+			return n, true
+		}
+	}
+	var parts []string
+	for _, w := range words {
+		parts = append(parts, strings.Split(w, "-")...)
+	}
+	ordinal := false
+	if last, ok := ordinalWords[parts[len(parts)-1]]; ok {
+		parts[len(parts)-1] = last
+		ordinal = true
+	}
+
+	// group is the value below the current scale word and small the part of
+	// it below a hundred. state tracks what the previous word was so that
+	// "one two" or "twenty thirty" are rejected.
+	const (
+		start = iota
+		afterTens
+		afterUnit
+		afterHundred
+		afterAnd
+	)
+	var total, group, small int64
+	state, lastScale := start, int64(0)
+	for i, part := range parts {
+		if v, ok := numberUnits[part]; ok || (part == "a" && i+1 < len(parts)) {
+			if !ok {
+				v = 1
+			}
+			switch {
+			case state == afterTens && v > 0 && v < 10:
+				small += v
+			case state == start || state == afterHundred || state == afterAnd:
+				small = v
+			default:
+				return "", false
+			}
+			state = afterUnit
+			continue
+		}
+		if v, ok := numberTens[part]; ok {
+			if state != start && state != afterHundred && state != afterAnd {
+				return "", false
+			}
+			small, state = v, afterTens
+			continue
+		}
+		switch part {
+		case "and":
+			if (state != afterHundred && !(state == start && total > 0)) || i == len(parts)-1 {
+				return "", false
+			}
+			state = afterAnd
+			continue
+		case "hundred":
+			if (state != afterUnit && state != afterTens) || small == 0 || group != 0 {
+				return "", false
+			}
+			group, small, state = small*100, 0, afterHundred
+			continue
+		}
+		scale, ok := numberScales[part]
+		if !ok || state == start || state == afterAnd || (lastScale != 0 && scale >= lastScale) {
+			return "", false
+		}
+		total += (group + small) * scale
+		group, small, state, lastScale = 0, 0, start, scale
+	}
+	if state == afterAnd {
+		return "", false
+	}
+	total += group + small
+	n := strconv.FormatInt(total, 10)
+	if ordinal {
+		n += "th"
+	}
+	return n, true
+}
+
+// parseDigits reads a whole number with optional thousands separators and an
+// optional ordinal suffix.
+func parseDigits(word string) (string, bool) {
+	ordinal := false
+	for _, suffix := range []string{"st", "nd", "rd", "th"} {
+		if strings.HasSuffix(word, suffix) {
+			word, ordinal = strings.TrimSuffix(word, suffix), true
+			break
+		}
+	}
+	if word == "" {
+		return "", false
+	}
+	if strings.Contains(word, ",") {
+		groups := strings.Split(word, ",")
+		for i, g := range groups {
+			if (i == 0 && (len(g) == 0 || len(g) > 3)) || (i > 0 && len(g) != 3) {
+				return "", false
+			}
+		}
+		word = strings.Join(groups, "")
+	}
+	n, err := strconv.ParseInt(word, 10, 64)
+	if err != nil || n < 0 || word[0] == '+' {
+		return "", false
+	}
+	s := strconv.FormatInt(n, 10)
+	if ordinal {
+		s += "th"
+	}
+	return s, true
+}
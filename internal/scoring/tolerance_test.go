@@ -0,0 +1,289 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nilesh0729/PixelScribe/internal/lexicon"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlignWithTolerance(t *testing.T) {
+	testCases := []struct {
+		name      string
+		tolerance Tolerance
+		original  string
+		typed     string
+		matched   int
+		rule      ToleranceRule
+	}{
+		{
+			name:      "SpellingVariant",
+			tolerance: Tolerance{SpellingVariants: true},
+			original:  "the colour of the theatre",
+			typed:     "the color of the theater",
+			matched:   5,
+			rule:      RuleSpellingVariant,
+		},
+		{
+			name:      "SpellingVariantDisabled",
+			tolerance: Tolerance{Numbers: true},
+			original:  "the colour",
+			typed:     "the color",
+			matched:   1,
+		},
+		{
+			name:      "NumberWords",
+			tolerance: Tolerance{Numbers: true},
+			original:  "I paid twenty-one dollars",
+			typed:     "I paid 21 dollars",
+			matched:   4,
+			rule:      RuleNumber,
+		},
+		{
+			name:      "MultiWordNumber",
+			tolerance: Tolerance{Numbers: true},
+			original:  "about 1,250 people",
+			typed:     "about one thousand two hundred and fifty people",
+			matched:   3,
+			rule:      RuleNumber,
+		},
+		{
+			name:      "Ordinal",
+			tolerance: Tolerance{Numbers: true},
+			original:  "the 1st day",
+			typed:     "the first day",
+			matched:   3,
+			rule:      RuleNumber,
+		},
+		{
+			name:      "OrdinalIsNotCardinal",
+			tolerance: Tolerance{Numbers: true},
+			original:  "the 1st day",
+			typed:     "the one day",
+			matched:   2,
+		},
+		{
+			name:      "Contraction",
+			tolerance: Tolerance{Contractions: true},
+			original:  "we do not agree",
+			typed:     "we don't agree",
+			matched:   4,
+			rule:      RuleContraction,
+		},
+		{
+			name:      "Expansion",
+			tolerance: Tolerance{Contractions: true},
+			original:  "It's late",
+			typed:     "It is late",
+			matched:   2,
+			rule:      RuleContraction,
+		},
+		{
+			name:      "Homophone",
+			tolerance: Tolerance{Homophones: true},
+			original:  "over there",
+			typed:     "over their",
+			matched:   2,
+			rule:      RuleHomophone,
+		},
+		{
+			name:      "Alternative",
+			tolerance: Tolerance{Alternatives: [][]string{{"okay", "OK"}, {"e-mail", "email"}}},
+			original:  "send an e-mail, okay",
+			typed:     "send an email, ok",
+			matched:   4,
+			rule:      RuleAlternative,
+		},
+		{
+			name:      "Profile",
+			tolerance: Tolerance{Profile: "standard"},
+			original:  "ten metres",
+			typed:     "10 meters",
+			matched:   2,
+			rule:      RuleNumber,
+		},
+		{
+			name:      "PunctuationStillCounts",
+			tolerance: Tolerance{SpellingVariants: true},
+			original:  "colour,",
+			typed:     "color",
+			matched:   0,
+		},
+		{
+			name:      "CapitalisationStillCounts",
+			tolerance: Tolerance{SpellingVariants: true},
+			original:  "Colour",
+			typed:     "color",
+			matched:   0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := AlignWith(strings.Fields(tc.original), strings.Fields(tc.typed), &tc.tolerance)
+			require.Equal(t, tc.matched, a.Matched)
+
+			var rules []ToleranceRule
+			for _, op := range a.Ops {
+				if op.Tolerance != "" {
+					require.Equal(t, OpMatch, op.Kind)
+					rules = append(rules, op.Tolerance)
+				}
+			}
+			if tc.rule == "" {
+				require.Empty(t, rules)
+			} else {
+				require.Contains(t, rules, tc.rule)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	testCases := []struct {
+		text string
+		want string
+		ok   bool
+	}{
+		{"zero", "0", true},
+		{"forty-two", "42", true},
+		{"a hundred", "100", true},
+		{"one hundred and five", "105", true},
+		{"twenty one hundred", "2100", true},
+		{"two million three thousand", "2003000", true},
+		{"twenty-first", "21th", true},
+		{"one hundredth", "100th", true},
+		{"1,000,000", "1000000", true},
+		{"3rd", "3th", true},
+		{"one two", "", false},
+		{"twenty thirty", "", false},
+		{"thousand", "", false},
+		{"one thousand one million", "", false},
+		{"hundred and", "", false},
+		{"1,00", "", false},
+		{"and", "", false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.text, func(t *testing.T) {
+			got, ok := parseNumber(strings.Fields(tc.text))
+			require.Equal(t, tc.ok, ok)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestToleranceValidate(t *testing.T) {
+	require.NoError(t, Tolerance{Profile: "lenient"}.Validate())
+	require.Error(t, Tolerance{Profile: "anything"}.Validate())
+	require.Error(t, Tolerance{Alternatives: [][]string{{"okay"}}}.Validate())
+	require.Error(t, Tolerance{Alternatives: [][]string{{"okay", " "}}}.Validate())
+	require.Equal(t, []string{"lenient", "standard", "strict"}, ToleranceProfiles())
+}
+
+func TestScoreReportsTolerance(t *testing.T) {
+	original := "We do not like grey skies"
+	result, err := WordAlignScorer{}.Score(Input{
+		Original:      original,
+		Typed:         "We don't like gray skies",
+		UserTolerance: &Tolerance{Profile: "standard"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 6, result.CorrectWords)
+	require.InDelta(t, 100.0, result.Accuracy, 0.001)
+	require.Zero(t, result.Errors.Total())
+
+	contraction := result.Comparison.Tokens[1]
+	require.Equal(t, RuleContraction, contraction.Tolerance)
+	require.Equal(t, "do not", contraction.Original)
+	require.Equal(t, 2, contraction.OriginalWords)
+	require.Equal(t, "do not", string([]rune(original)[contraction.OriginalSpan.Start:contraction.OriginalSpan.End]))
+	require.Equal(t, RuleSpellingVariant, result.Comparison.Tokens[3].Tolerance)
+
+	// A dictation's own tolerance replaces the user's.
+	result, err = WordAlignScorer{}.Score(Input{
+		Original:      original,
+		Typed:         "We don't like gray skies",
+		Options:       Options{Tolerance: &Tolerance{Profile: "strict"}},
+		UserTolerance: &Tolerance{Profile: "standard"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, result.CorrectWords)
+}
+
+func TestScoreAcceptsPronunciations(t *testing.T) {
+	testCases := []struct {
+		name     string
+		original string
+		typed    string
+		lexicon  []lexicon.Entry
+		language string
+		correct  int
+	}{
+		{
+			name:     "Abbreviation",
+			original: "Dr. Rao arrived, e.g. early.",
+			typed:    "Doctor Rao arrived, for example early.",
+			correct:  5,
+		},
+		{
+			name:     "AbbreviationEndsSentence",
+			original: "We met Jones Jr.",
+			typed:    "We met Jones Junior.",
+			correct:  4,
+		},
+		{
+			name:     "Year",
+			original: "It was 1998, I think.",
+			typed:    "It was nineteen ninety eight, I think.",
+			correct:  5,
+		},
+		{
+			name:     "Money",
+			original: "It costs $5.",
+			typed:    "It costs five dollars.",
+			correct:  3,
+		},
+		{
+			name:     "Lexicon",
+			original: "Ask Nguyen today",
+			typed:    "Ask Win today",
+			lexicon:  []lexicon.Entry{{Written: "Nguyen", Spoken: "Win"}},
+			correct:  3,
+		},
+		{
+			name:     "WrongPunctuation",
+			original: "Dr. Rao, please",
+			typed:    "Doctor Rao please",
+			correct:  2,
+		},
+		{
+			name:     "NotEnglish",
+			original: "Dr. Rao",
+			typed:    "Doctor Rao",
+			language: "fr",
+			correct:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := WordAlignScorer{}.Score(Input{
+				Original: tc.original,
+				Typed:    tc.typed,
+				Language: tc.language,
+				Lexicon:  tc.lexicon,
+			})
+			require.NoError(t, err)
+			require.Equal(t, tc.correct, result.CorrectWords)
+			if tc.correct == result.TotalWords {
+				rules := make([]ToleranceRule, 0, len(result.Comparison.Tokens))
+				for _, token := range result.Comparison.Tokens {
+					rules = append(rules, token.Tolerance)
+				}
+				require.Contains(t, rules, RulePronunciation)
+			}
+		})
+	}
+}
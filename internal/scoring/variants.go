@@ -0,0 +1,193 @@
+package scoring
+
+import "strings"
+
+// Word tables used by the tolerance rules. Every entry is lower case.
+
+// spellingVariants maps British and American spellings to the American one.
+var spellingVariants = map[string]string{}
+
+// homophones maps each word of a homophone group to the group's first word.
+var homophones = map[string]string{}
+
+// contractions maps a contraction to its canonical expansions, and expansions
+// maps every accepted expansion to its canonical form.
+var (
+	contractions = map[string][]string{}
+	expansions   = map[string]string{}
+)
+
+var spellingPairs = [][2]string{
+	{"aeroplane", "airplane"}, {"aluminium", "aluminum"}, {"analogue", "analog"},
+	{"catalogue", "catalog"}, {"dialogue", "dialog"}, {"programme", "program"},
+	{"programmes", "programs"}, {"cheque", "check"}, {"cheques", "checks"},
+	{"grey", "gray"}, {"tyre", "tire"}, {"tyres", "tires"}, {"plough", "plow"},
+	{"mould", "mold"}, {"ageing", "aging"}, {"judgement", "judgment"},
+	{"acknowledgement", "acknowledgment"}, {"jewellery", "jewelry"},
+	{"manoeuvre", "maneuver"}, {"paediatric", "pediatric"}, {"encyclopaedia", "encyclopedia"},
+	{"anaemia", "anemia"}, {"anaesthetic", "anesthetic"}, {"oestrogen", "estrogen"},
+	{"defence", "defense"}, {"offence", "offense"}, {"licence", "license"},
+	{"pretence", "pretense"}, {"enrol", "enroll"}, {"enrolment", "enrollment"},
+	{"fulfil", "fulfill"}, {"fulfilment", "fulfillment"}, {"instalment", "installment"},
+	{"skilful", "skillful"}, {"wilful", "willful"}, {"kerb", "curb"}, {"sceptical", "skeptical"},
+	{"storey", "story"}, {"draught", "draft"}, {"pyjamas", "pajamas"}, {"moustache", "mustache"},
+	{"cosy", "cozy"}, {"sulphur", "sulfur"}, {"axe", "ax"}, {"whisky", "whiskey"},
+	{"analyse", "analyze"}, {"analysed", "analyzed"}, {"analysing", "analyzing"},
+	{"paralyse", "paralyze"}, {"paralysed", "paralyzed"}, {"catalyse", "catalyze"},
+	{"centred", "centered"}, {"practise", "practice"}, {"practised", "practiced"},
+}
+
+// Stems that take -ise/-ize, -our/-or, a doubled l and -re/-er.
+var (
+	izeStems = []string{
+		"apolog", "author", "capital", "categor", "central", "character", "civil",
+		"critic", "emphas", "final", "global", "harmon", "legal", "maxim", "memor",
+		"minim", "modern", "normal", "optim", "organ", "priorit", "real", "recogn",
+		"special", "standard", "summar", "symbol", "util", "visual",
+	}
+	izeSuffixes = []string{"e", "es", "ed", "ing", "er", "ers", "ation", "ations"}
+
+	ourStems    = []string{"arm", "behavi", "col", "endeav", "fav", "flav", "harb", "hon", "hum", "lab", "neighb", "od", "rum", "savi", "vap", "vig"}
+	ourSuffixes = []string{"", "s", "ed", "ing", "ful", "able", "ite", "ites", "hood", "er", "ers"}
+
+	doubleLStems    = []string{"cancel", "counsel", "dial", "duel", "fuel", "jewel", "label", "level", "marvel", "model", "quarrel", "signal", "total", "travel"}
+	doubleLSuffixes = []string{"ed", "ing", "er", "ers"}
+
+	treStems = []string{"cent", "fib", "lit", "lust", "met", "somb", "spect", "theat"}
+)
+
+var homophoneGroups = [][]string{
+	{"their", "there", "they're"}, {"to", "too", "two"}, {"your", "you're"},
+	{"its", "it's"}, {"whose", "who's"}, {"hear", "here"}, {"know", "no"},
+	{"knew", "new"}, {"write", "right", "rite"}, {"weather", "whether"},
+	{"principal", "principle"}, {"stationary", "stationery"}, {"complement", "compliment"},
+	{"bare", "bear"}, {"brake", "break"}, {"buy", "by", "bye"}, {"cite", "site", "sight"},
+	{"fair", "fare"}, {"flour", "flower"}, {"for", "four"}, {"hole", "whole"},
+	{"mail", "male"}, {"meat", "meet"}, {"one", "won"}, {"pair", "pear", "pare"},
+	{"peace", "piece"}, {"plain", "plane"}, {"rain", "reign", "rein"}, {"sea", "see"},
+	{"sole", "soul"}, {"son", "sun"}, {"tail", "tale"}, {"threw", "through"},
+	{"wait", "weight"}, {"weak", "week"}, {"wear", "where"}, {"which", "witch"},
+	{"wood", "would"}, {"allowed", "aloud"}, {"aisle", "isle"}, {"ate", "eight"},
+	{"blew", "blue"}, {"cell", "sell"}, {"dear", "deer"}, {"die", "dye"},
+	{"hour", "our"}, {"made", "maid"}, {"road", "rode"}, {"scene", "seen"},
+}
+
+var contractionTable = map[string][]string{
+	"don't": {"do not"}, "doesn't": {"does not"}, "didn't": {"did not"},
+	"can't": {"cannot"}, "couldn't": {"could not"}, "won't": {"will not"},
+	"wouldn't": {"would not"}, "shan't": {"shall not"}, "shouldn't": {"should not"},
+	"mightn't": {"might not"}, "mustn't": {"must not"}, "needn't": {"need not"},
+	"isn't": {"is not"}, "aren't": {"are not"}, "wasn't": {"was not"}, "weren't": {"were not"},
+	"hasn't": {"has not"}, "haven't": {"have not"}, "hadn't": {"had not"},
+	"i'm": {"i am"}, "let's": {"let us"}, "y'all": {"you all"},
+}
+
+// contractionSuffixes are the endings added to pronouns and a few other words
+// to form contractions, with what they stand for.
+var contractionSuffixes = map[string][]string{
+	"'re": {"are"}, "'ve": {"have"}, "'ll": {"will"}, "'d": {"would", "had"}, "'s": {"is", "has"},
+}
+
+var contractionSubjects = map[string][]string{
+	"'re": {"you", "we", "they", "who", "what"},
+	"'ve": {"i", "you", "we", "they", "who", "could", "would", "should", "might", "must"},
+	"'ll": {"i", "you", "he", "she", "it", "we", "they", "that", "there", "who", "what"},
+	"'d":  {"i", "you", "he", "she", "it", "we", "they", "that", "there", "who"},
+	"'s":  {"he", "she", "it", "that", "there", "here", "what", "where", "who", "how"},
+}
+
+var (
+	numberUnits = map[string]int64{
+		"zero": 0, "one": 1, "two": 2, "three": 3, "four": 4, "five": 5, "six": 6,
+		"seven": 7, "eight": 8, "nine": 9, "ten": 10, "eleven": 11, "twelve": 12,
+		"thirteen": 13, "fourteen": 14, "fifteen": 15, "sixteen": 16,
+		"seventeen": 17, "eighteen": 18, "nineteen": 19,
+	}
+	numberTens = map[string]int64{
+		"twenty": 20, "thirty": 30, "forty": 40, "fifty": 50,
+		"sixty": 60, "seventy": 70, "eighty": 80, "ninety": 90,
+	}
+	numberScales = map[string]int64{
+		"thousand": 1_000, "million": 1_000_000, "billion": 1_000_000_000,
+	}
+	// irregularOrdinals are the cardinals whose ordinal is not formed by
+	// adding "th".
+	irregularOrdinals = map[string]string{
+		"zero": "zeroth", "one": "first", "two": "second", "three": "third",
+		"five": "fifth", "eight": "eighth", "nine": "ninth", "twelve": "twelfth",
+	}
+	// ordinalWords maps ordinal number words to their cardinal form.
+	ordinalWords = map[string]string{}
+)
+
+func init() {
+	for _, pair := range spellingPairs {
+		addSpellingVariant(pair[0], pair[1])
+	}
+	for _, stem := range izeStems {
+		for _, suffix := range izeSuffixes {
+			addSpellingVariant(stem+"is"+suffix, stem+"iz"+suffix)
+		}
+	}
+	for _, stem := range ourStems {
+		for _, suffix := range ourSuffixes {
+			addSpellingVariant(stem+"our"+suffix, stem+"or"+suffix)
+		}
+	}
+	for _, stem := range doubleLStems {
+		for _, suffix := range doubleLSuffixes {
+			addSpellingVariant(stem+"l"+suffix, stem+suffix)
+		}
+	}
+	for _, stem := range treStems {
+		addSpellingVariant(stem+"re", stem+"er")
+		addSpellingVariant(stem+"res", stem+"ers")
+	}
+
+	for _, group := range homophoneGroups {
+		for _, word := range group {
+			homophones[word] = group[0]
+		}
+	}
+
+	for contraction, expanded := range contractionTable {
+		addContraction(contraction, expanded...)
+	}
+	for suffix, subjects := range contractionSubjects {
+		for _, subject := range subjects {
+			var expanded []string
+			for _, verb := range contractionSuffixes[suffix] {
+				expanded = append(expanded, subject+" "+verb)
+			}
+			addContraction(subject+suffix, expanded...)
+		}
+	}
+	expansions["can not"] = "cannot"
+
+	for cardinal, ordinal := range irregularOrdinals {
+		ordinalWords[ordinal] = cardinal
+	}
+	for word := range numberUnits {
+		if _, irregular := irregularOrdinals[word]; !irregular {
+			ordinalWords[word+"th"] = word
+		}
+	}
+	for word := range numberTens {
+		ordinalWords[strings.TrimSuffix(word, "y")+"ieth"] = word
+	}
+	for _, word := range []string{"hundred", "thousand", "million", "billion"} {
+		ordinalWords[word+"th"] = word
+	}
+}
+
+func addSpellingVariant(british, american string) {
+	spellingVariants[british] = american
+	spellingVariants[american] = american
+}
+
+func addContraction(contraction string, expanded ...string) {
+	contractions[contraction] = expanded
+	for _, e := range expanded {
+		expansions[e] = e
+	}
+}
@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	data := "not really audio"
+	require.NoError(t, store.Put(ctx, "dictations/1/speech.mp3", strings.NewReader(data), int64(len(data)), "audio/mpeg"))
+
+	object, err := store.Open(ctx, "dictations/1/speech.mp3")
+	require.NoError(t, err)
+	got, err := io.ReadAll(object)
+	require.NoError(t, err)
+	require.NoError(t, object.Close())
+	require.Equal(t, data, string(got))
+	require.Equal(t, int64(len(data)), object.Size)
+	require.Equal(t, "audio/mpeg", object.ContentType)
+	require.NotEmpty(t, object.ETag)
+
+	// Replacing the blob changes its ETag
+	require.NoError(t, store.Put(ctx, "dictations/1/speech.mp3", strings.NewReader(data+"!"), int64(len(data)+1), "audio/mpeg"))
+	replaced, err := store.Open(ctx, "dictations/1/speech.mp3")
+	require.NoError(t, err)
+	require.NoError(t, replaced.Close())
+	require.NotEqual(t, object.ETag, replaced.ETag)
+
+	require.NoError(t, store.Delete(ctx, "dictations/1/speech.mp3"))
+	_, err = store.Open(ctx, "dictations/1/speech.mp3")
+	require.ErrorIs(t, err, ErrNotFound)
+	require.NoError(t, store.Delete(ctx, "dictations/1/speech.mp3"))
+
+	// A short write leaves nothing behind
+	require.Error(t, store.Put(ctx, "short.wav", strings.NewReader(data), 100, "audio/wav"))
+	_, err = store.Open(ctx, "short.wav")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCheckKey(t *testing.T) {
+	for _, key := range []string{"a.mp3", "dictations/1/a.wav"} {
+		require.NoError(t, checkKey(key), key)
+	}
+	for _, key := range []string{"", "/etc/passwd", "../secret", "..", "a/../../b", "a//b", "a/./b"} {
+		require.Error(t, checkKey(key), key)
+	}
+}
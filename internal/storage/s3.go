@@ -0,0 +1,320 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// S3Options configure an S3Store.
+type S3Options struct {
+	// Endpoint is the server's base URL, such as "http://minio:9000".
+	// Empty uses AWS in Region.
+	Endpoint string
+	// Region defaults to "us-east-1", which MinIO accepts.
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle addresses the bucket in the path instead of the host name,
+	// as MinIO and most other S3-compatible servers require.
+	PathStyle bool
+}
+
+// S3Store keeps blobs in an S3-compatible bucket, signing requests with AWS
+// Signature Version 4.
+type S3Store struct {
+	opts   S3Options
+	base   *url.URL
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewS3Store returns an S3Store for opts.Bucket. It does not contact the
+// server.
+func NewS3Store(opts S3Options) (*S3Store, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("S3 bucket is required")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	endpoint := opts.Endpoint
+	if endpoint == "" {
+		endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	base, err := url.Parse(strings.TrimSuffix(endpoint, "/"))
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("invalid S3 endpoint %q", endpoint)
+	}
+	if !opts.PathStyle {
+		base.Host = opts.Bucket + "." + base.Host
+	}
+	return &S3Store{
+		opts:   opts,
+		base:   base,
+		client: &http.Client{Timeout: 5 * time.Minute},
+		now:    time.Now,
+	}, nil
+}
+
+// objectURL returns the URL of key, escaping each path segment.
+func (s *S3Store) objectURL(key string) *url.URL {
+	u := *s.base
+	segments := strings.Split(key, "/")
+	if s.opts.PathStyle {
+		segments = append([]string{s.opts.Bucket}, segments...)
+	}
+	for i, seg := range segments {
+		segments[i] = uriEncode(seg)
+	}
+	u.RawPath = u.Path + "/" + strings.Join(segments, "/")
+	u.Path, _ = url.PathUnescape(u.RawPath)
+	return &u
+}
+
+// do signs and sends a request for key with size bytes of body.
+func (s *S3Store) do(ctx context.Context, method, key string, body io.Reader, size int64, header http.Header) (*http.Response, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, s.objectURL(key).String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		// S3 rejects chunked uploads without a length
+		req.ContentLength = size
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, "UNSIGNED-PAYLOAD")
+	return s.client.Do(req)
+}
+
+// sign adds AWS Signature Version 4 headers to req. Host, Range,
+// Content-Type and x-amz-* headers are signed.
+func (s *S3Store) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" || name == "content-type" {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.opts.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+
+	key := hmacSHA256([]byte("AWS4"+s.opts.SecretAccessKey), date)
+	key = hmacSHA256(key, s.opts.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.opts.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			parts = append(parts, uriEncode(k)+"="+uriEncode(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode escapes s as Signature Version 4 requires: everything but
+// unreserved characters, with upper-case hex digits.
+func uriEncode(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+// responseError describes a failed S3 request.
+func responseError(op, key string, resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("S3 %s %q failed with status %d: %s", op, key, resp.StatusCode, strings.TrimSpace(string(body)))
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	header := http.Header{}
+	if contentType != "" {
+		header.Set("Content-Type", contentType)
+	}
+	resp, err := s.do(ctx, http.MethodPut, key, io.LimitReader(r, size), size, header)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return responseError("PUT", key, resp)
+	}
+	return nil
+}
+
+// Open checks the object exists and returns a reader that fetches it with
+// range requests, so seeking skips the bytes in between.
+func (s *S3Store) Open(ctx context.Context, key string) (*Object, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil, 0, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, responseError("HEAD", key, resp)
+	}
+
+	object := &Object{
+		ReadSeekCloser: &s3Reader{store: s, ctx: ctx, key: key, size: resp.ContentLength},
+		Size:           resp.ContentLength,
+		ContentType:    resp.Header.Get("Content-Type"),
+		ETag:           resp.Header.Get("ETag"),
+	}
+	if object.ContentType == "" || object.ContentType == "binary/octet-stream" {
+		object.ContentType = contentTypeOf(key)
+	}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		object.ModTime = modTime
+	}
+	return object, nil
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil, 0, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return responseError("DELETE", key, resp)
+	}
+	return nil
+}
+
+// s3Reader reads an object from its current offset with one GET, starting
+// a new one after each seek.
+type s3Reader struct {
+	store *S3Store
+	ctx   context.Context
+	key   string
+	size  int64
+	pos   int64
+	body  io.ReadCloser
+}
+
+func (r *s3Reader) Read(p []byte) (int, error) {
+	if r.pos >= r.size {
+		return 0, io.EOF
+	}
+	if r.body == nil {
+		header := http.Header{}
+		header.Set("Range", "bytes="+strconv.FormatInt(r.pos, 10)+"-")
+		resp, err := r.store.do(r.ctx, http.MethodGet, r.key, nil, 0, header)
+		if err != nil {
+			return 0, err
+		}
+		if resp.StatusCode != http.StatusPartialContent && resp.StatusCode != http.StatusOK {
+			defer resp.Body.Close()
+			return 0, responseError("GET", r.key, resp)
+		}
+		r.body = resp.Body
+	}
+	n, err := r.body.Read(p)
+	r.pos += int64(n)
+	return n, err
+}
+
+func (r *s3Reader) Seek(offset int64, whence int) (int64, error) {
+	pos := offset
+	switch whence {
+	case io.SeekCurrent:
+		pos += r.pos
+	case io.SeekEnd:
+		pos += r.size
+	}
+	if pos < 0 {
+		return 0, errors.New("seek before start of object")
+	}
+	if pos != r.pos && r.body != nil {
+		r.body.Close()
+		r.body = nil
+	}
+	r.pos = pos
+	return pos, nil
+}
+
+func (r *s3Reader) Close() error {
+	if r.body == nil {
+		return nil
+	}
+	err := r.body.Close()
+	r.body = nil
+	return err
+}
@@ -0,0 +1,93 @@
+// Package storage keeps audio files and other blobs behind a pluggable
+// backend, addressed by slash-separated keys such as
+// "dictations/42/speech.mp3".
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned for a key with no blob.
+var ErrNotFound = errors.New("blob not found")
+
+// Object is an open blob. Callers must close it.
+type Object struct {
+	io.ReadSeekCloser
+	Size        int64
+	ModTime     time.Time
+	ContentType string
+	// ETag is a quoted HTTP entity tag that changes whenever the blob does,
+	// or empty if the backend has none.
+	ETag string
+}
+
+// Store saves and serves blobs. Implementations must be safe for concurrent
+// use.
+type Store interface {
+	// Put stores size bytes from r under key, replacing any blob there.
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open returns the blob under key, or ErrNotFound.
+	Open(ctx context.Context, key string) (*Object, error)
+	// Delete removes the blob under key. Deleting a missing key is not an
+	// error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Options select and configure the Store returned by New.
+type Options struct {
+	// Backend is "local" (the default) or "s3".
+	Backend string
+	// Dir is the LocalStore directory.
+	Dir string
+	S3  S3Options
+}
+
+// New returns the Store for opts.Backend.
+func New(opts Options) (Store, error) {
+	switch opts.Backend {
+	case "", "local":
+		if opts.Dir == "" {
+			return nil, errors.New("local storage needs a directory")
+		}
+		return NewLocalStore(opts.Dir)
+	case "s3":
+		return NewS3Store(opts.S3)
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q, available: local, s3", opts.Backend)
+	}
+}
+
+// checkKey rejects keys that are empty, absolute or escape the store.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || path.Clean(key) != key || strings.HasPrefix(key, "../") || key == ".." {
+		return fmt.Errorf("invalid blob key %q", key)
+	}
+	return nil
+}
+
+// contentTypes pins the types of the files PixelScribe stores, which the
+// system MIME tables name inconsistently.
+var contentTypes = map[string]string{
+	".mp3":  "audio/mpeg",
+	".wav":  "audio/wav",
+	".json": "application/json",
+}
+
+// contentTypeOf guesses a blob's content type from its key's extension.
+func contentTypeOf(key string) string {
+	ext := strings.ToLower(path.Ext(key))
+	if t, ok := contentTypes[ext]; ok {
+		return t
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
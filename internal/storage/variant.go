@@ -0,0 +1,25 @@
+package storage
+
+import (
+	"context"
+	"path"
+	"strings"
+)
+
+// VariantKey names a blob derived from the one at key, such as a re-encoded
+// copy, beside it: "uploads/1/a.wav" with suffix "x0.80.wav" is
+// "uploads/1/a.x0.80.wav".
+func VariantKey(key, suffix string) string {
+	return strings.TrimSuffix(key, path.Ext(key)) + "." + suffix
+}
+
+// DeleteWithVariants removes the blob at key and its variants with the given
+// suffixes, whether or not they were ever made.
+func DeleteWithVariants(ctx context.Context, s Store, key string, suffixes ...string) error {
+	for _, suffix := range suffixes {
+		if err := s.Delete(ctx, VariantKey(key, suffix)); err != nil {
+			return err
+		}
+	}
+	return s.Delete(ctx, key)
+}
@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVariantKey(t *testing.T) {
+	require.Equal(t, "uploads/1/a.x0.80.wav", VariantKey("uploads/1/a.wav", "x0.80.wav"))
+	require.Equal(t, "dictations/2/speech.waveform.json", VariantKey("dictations/2/speech.mp3", "waveform.json"))
+	require.Equal(t, "raw.x2.00.wav", VariantKey("raw", "x2.00.wav"))
+}
+
+func TestDeleteWithVariants(t *testing.T) {
+	ctx := context.Background()
+	store, err := NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"a/b.wav", "a/b.x0.80.wav", "a/c.wav"} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("RIFF"), 4, ""))
+	}
+
+	require.NoError(t, DeleteWithVariants(ctx, store, "a/b.wav", "x0.80.wav", "x1.20.wav"))
+	for _, key := range []string{"a/b.wav", "a/b.x0.80.wav"} {
+		_, err := store.Open(ctx, key)
+		require.ErrorIs(t, err, ErrNotFound)
+	}
+	object, err := store.Open(ctx, "a/c.wav")
+	require.NoError(t, err)
+	object.Close()
+}
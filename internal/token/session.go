@@ -0,0 +1,84 @@
+package token
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// AttemptSessionPayload identifies a dictation attempt started on the server.
+// The registered ID is the session ID and IssuedAt is the server start time.
+type AttemptSessionPayload struct {
+	UserID      int64 `json:"user_id"`
+	DictationID int64 `json:"dictation_id"`
+	jwt.RegisteredClaims
+}
+
+// SessionMaker signs and verifies attempt sessions. Sessions are signed with a
+// key derived from the token secret, so an access token is never accepted as
+// a session and a session never authenticates a request.
+type SessionMaker struct {
+	secretKey []byte
+}
+
+func NewSessionMaker(secretKey string) (*SessionMaker, error) {
+	if len(secretKey) < minSecretKeySize {
+		return nil, fmt.Errorf("invalid size of the secret key: must be atleast %d characters", minSecretKeySize)
+	}
+	key := sha256.Sum256([]byte("attempt-session:" + secretKey))
+	return &SessionMaker{secretKey: key[:]}, nil
+}
+
+// CreateSession signs a session for sessionID that expires after duration.
+func (maker *SessionMaker) CreateSession(sessionID uuid.UUID, userID, dictationID int64, startedAt time.Time, duration time.Duration) (string, *AttemptSessionPayload, error) {
+	payload := &AttemptSessionPayload{
+		UserID:      userID,
+		DictationID: dictationID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        sessionID.String(),
+			IssuedAt:  jwt.NewNumericDate(startedAt),
+			ExpiresAt: jwt.NewNumericDate(startedAt.Add(duration)),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, payload).SignedString(maker.secretKey)
+	if err != nil {
+		return "", nil, err
+	}
+	return token, payload, nil
+}
+
+// VerifySession checks the session's signature and expiry.
+func (maker *SessionMaker) VerifySession(token string) (*AttemptSessionPayload, error) {
+	keyFunc := func(token *jwt.Token) (interface{}, error) {
+		_, ok := token.Method.(*jwt.SigningMethodHMAC)
+		if !ok {
+			return nil, ErrInvalidToken
+		}
+		return maker.secretKey, nil
+	}
+	jwtToken, err := jwt.ParseWithClaims(token, &AttemptSessionPayload{}, keyFunc)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+	payload, ok := jwtToken.Claims.(*AttemptSessionPayload)
+	if !ok {
+		return nil, ErrInvalidToken
+	}
+	return payload, nil
+}
+
+// SessionID parses the session ID from the payload.
+func (payload *AttemptSessionPayload) SessionID() (uuid.UUID, error) {
+	id, err := uuid.Parse(payload.ID)
+	if err != nil {
+		return uuid.Nil, ErrInvalidToken
+	}
+	return id, nil
+}
@@ -0,0 +1,63 @@
+package token
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/nilesh0729/PixelScribe/internal/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionMaker(t *testing.T) {
+	secret := util.RandomString(32)
+	maker, err := NewSessionMaker(secret)
+	require.NoError(t, err)
+
+	sessionID := uuid.New()
+	startedAt := time.Now()
+	token, created, err := maker.CreateSession(sessionID, 7, 42, startedAt, time.Minute)
+	require.NoError(t, err)
+	require.NotEmpty(t, token)
+
+	payload, err := maker.VerifySession(token)
+	require.NoError(t, err)
+	require.Equal(t, created.ID, payload.ID)
+	require.Equal(t, int64(7), payload.UserID)
+	require.Equal(t, int64(42), payload.DictationID)
+	require.WithinDuration(t, startedAt, payload.IssuedAt.Time, time.Second)
+
+	id, err := payload.SessionID()
+	require.NoError(t, err)
+	require.Equal(t, sessionID, id)
+}
+
+func TestExpiredSession(t *testing.T) {
+	maker, err := NewSessionMaker(util.RandomString(32))
+	require.NoError(t, err)
+
+	token, _, err := maker.CreateSession(uuid.New(), 1, 1, time.Now().Add(-2*time.Minute), time.Minute)
+	require.NoError(t, err)
+
+	payload, err := maker.VerifySession(token)
+	require.EqualError(t, err, ErrExpiredToken.Error())
+	require.Nil(t, payload)
+}
+
+func TestSessionAndAccessTokensAreNotInterchangeable(t *testing.T) {
+	secret := util.RandomString(32)
+	sessions, err := NewSessionMaker(secret)
+	require.NoError(t, err)
+	access, err := NewJWTMaker(secret)
+	require.NoError(t, err)
+
+	accessToken, err := access.CreateToken(1, util.RandomUsername(), time.Minute)
+	require.NoError(t, err)
+	_, err = sessions.VerifySession(accessToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+
+	sessionToken, _, err := sessions.CreateSession(uuid.New(), 1, 1, time.Now(), time.Minute)
+	require.NoError(t, err)
+	_, err = access.VerifyToken(sessionToken)
+	require.EqualError(t, err, ErrInvalidToken.Error())
+}
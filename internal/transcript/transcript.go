@@ -0,0 +1,157 @@
+// Package transcript holds the reference text of audio dictations, which
+// attempts are scored against, optionally split into segments timed against
+// the recording.
+package transcript
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ErrNoSegment is returned by Lookup for a segment number the transcript
+// doesn't have.
+var ErrNoSegment = errors.New("no such segment")
+
+// Segment is a stretch of the recording and the words spoken in it.
+// Offsets are in milliseconds from the start of the audio.
+type Segment struct {
+	StartMs int64  `json:"start_ms"`
+	EndMs   int64  `json:"end_ms"`
+	Text    string `json:"text"`
+}
+
+// Parse decodes stored segments. Empty input yields no segments.
+func Parse(data []byte) ([]Segment, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var segments []Segment
+	if err := json.Unmarshal(data, &segments); err != nil {
+		return nil, fmt.Errorf("invalid transcript segments: %w", err)
+	}
+	return segments, nil
+}
+
+// Validate checks that segments are in order, don't overlap, have text and
+// fall within a recording of durationMs milliseconds. A durationMs of 0
+// means the length is unknown and is not checked.
+func Validate(segments []Segment, durationMs int64) error {
+	var prevEnd int64
+	for i, seg := range segments {
+		switch {
+		case seg.StartMs < 0 || seg.EndMs <= seg.StartMs:
+			return fmt.Errorf("segment %d: end_ms must be after start_ms", i+1)
+		case seg.StartMs < prevEnd:
+			return fmt.Errorf("segment %d overlaps the one before it", i+1)
+		case durationMs > 0 && seg.EndMs > durationMs:
+			return fmt.Errorf("segment %d ends after the audio, which is %d ms long", i+1, durationMs)
+		case strings.TrimSpace(seg.Text) == "":
+			return fmt.Errorf("segment %d has no text", i+1)
+		}
+		prevEnd = seg.EndMs
+	}
+	return nil
+}
+
+// Join returns the text of segments in order, separated by spaces.
+func Join(segments []Segment) string {
+	texts := make([]string, len(segments))
+	for i, seg := range segments {
+		texts[i] = strings.TrimSpace(seg.Text)
+	}
+	return strings.Join(texts, " ")
+}
+
+// Resolve validates a transcript and its segments and returns the text to
+// score against. Empty text is taken from the segments; otherwise the
+// segments must cover exactly its words, so scoring a segment and the whole
+// dictation agree.
+func Resolve(text string, segments []Segment, durationMs int64) (string, error) {
+	if err := Validate(segments, durationMs); err != nil {
+		return "", err
+	}
+	text = strings.TrimSpace(text)
+	if text == "" {
+		if len(segments) == 0 {
+			return "", errors.New("transcript is empty")
+		}
+		return Join(segments), nil
+	}
+	if len(segments) > 0 && !sameWords(text, Join(segments)) {
+		return "", errors.New("segment texts don't match the transcript")
+	}
+	return text, nil
+}
+
+// sameWords reports whether a and b differ only in whitespace.
+func sameWords(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) != len(wb) {
+		return false
+	}
+	for i := range wa {
+		if wa[i] != wb[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// Lookup returns segment n, counting from 1, of stored segments.
+func Lookup(data []byte, n int) (Segment, error) {
+	segments, err := Parse(data)
+	if err != nil {
+		return Segment{}, err
+	}
+	if n < 1 || n > len(segments) {
+		return Segment{}, fmt.Errorf("%w: %d", ErrNoSegment, n)
+	}
+	return segments[n-1], nil
+}
+
+// Span locates a segment's words in the transcript, as character offsets
+// from its start; End is exclusive.
+type Span struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Spans finds each segment's words in text, in order. Segments that run
+// past the end of text, which Resolve prevents, get empty spans at its end.
+func Spans(text string, segments []Segment) []Span {
+	// Start and end offsets, in characters, of each word of text
+	type word struct{ start, end int }
+	var words []word
+	inWord := false
+	pos := 0
+	for _, r := range text {
+		if unicode.IsSpace(r) {
+			inWord = false
+		} else if !inWord {
+			words = append(words, word{start: pos, end: pos + 1})
+			inWord = true
+		} else {
+			words[len(words)-1].end = pos + 1
+		}
+		pos++
+	}
+
+	spans := make([]Span, len(segments))
+	next := 0
+	for i, seg := range segments {
+		n := len(strings.Fields(seg.Text))
+		if next+n > len(words) || n == 0 {
+			end := utf8.RuneCountInString(text)
+			spans[i] = Span{Start: end, End: end}
+			next = len(words)
+			continue
+		}
+		spans[i] = Span{Start: words[next].start, End: words[next+n-1].end}
+		next += n
+	}
+	return spans
+}
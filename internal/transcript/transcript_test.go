@@ -0,0 +1,152 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	segments, err := Parse(nil)
+	require.NoError(t, err)
+	require.Empty(t, segments)
+
+	segments, err = Parse([]byte(`[{"start_ms":0,"end_ms":1500,"text":"Dear Sir,"}]`))
+	require.NoError(t, err)
+	require.Equal(t, []Segment{{StartMs: 0, EndMs: 1500, Text: "Dear Sir,"}}, segments)
+
+	_, err = Parse([]byte(`{"start_ms":0}`))
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name       string
+		segments   []Segment
+		durationMs int64
+		err        string
+	}{
+		{
+			name: "OK",
+			segments: []Segment{
+				{StartMs: 0, EndMs: 1000, Text: "one"},
+				{StartMs: 1000, EndMs: 2500, Text: "two"},
+				{StartMs: 3000, EndMs: 4000, Text: "three"},
+			},
+			durationMs: 4000,
+		},
+		{
+			name:     "UnknownDuration",
+			segments: []Segment{{StartMs: 0, EndMs: 90000, Text: "one"}},
+		},
+		{
+			name:     "Empty",
+			segments: nil,
+		},
+		{
+			name:     "EndBeforeStart",
+			segments: []Segment{{StartMs: 500, EndMs: 500, Text: "one"}},
+			err:      "segment 1: end_ms must be after start_ms",
+		},
+		{
+			name:     "NegativeStart",
+			segments: []Segment{{StartMs: -1, EndMs: 500, Text: "one"}},
+			err:      "segment 1: end_ms must be after start_ms",
+		},
+		{
+			name: "Overlap",
+			segments: []Segment{
+				{StartMs: 0, EndMs: 1000, Text: "one"},
+				{StartMs: 900, EndMs: 2000, Text: "two"},
+			},
+			err: "segment 2 overlaps the one before it",
+		},
+		{
+			name:       "PastEnd",
+			segments:   []Segment{{StartMs: 0, EndMs: 4001, Text: "one"}},
+			durationMs: 4000,
+			err:        "segment 1 ends after the audio, which is 4000 ms long",
+		},
+		{
+			name:     "NoText",
+			segments: []Segment{{StartMs: 0, EndMs: 1000, Text: "  "}},
+			err:      "segment 1 has no text",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := Validate(tc.segments, tc.durationMs)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	segments := []Segment{
+		{StartMs: 0, EndMs: 1200, Text: " Dear Sir, "},
+		{StartMs: 1500, EndMs: 4000, Text: "thank you for your letter."},
+	}
+
+	text, err := Resolve("", segments, 4000)
+	require.NoError(t, err)
+	require.Equal(t, "Dear Sir, thank you for your letter.", text)
+
+	// Whitespace and line breaks in the transcript are kept
+	text, err = Resolve("Dear Sir,\nthank you for  your letter.\n", segments, 4000)
+	require.NoError(t, err)
+	require.Equal(t, "Dear Sir,\nthank you for  your letter.", text)
+
+	text, err = Resolve("Dear Madam", nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, "Dear Madam", text)
+
+	_, err = Resolve("Dear Madam, thank you for your letter.", segments, 4000)
+	require.EqualError(t, err, "segment texts don't match the transcript")
+
+	_, err = Resolve(" ", nil, 0)
+	require.EqualError(t, err, "transcript is empty")
+
+	_, err = Resolve("", segments, 3000)
+	require.Error(t, err)
+}
+
+func TestLookup(t *testing.T) {
+	data := []byte(`[{"start_ms":0,"end_ms":1000,"text":"one"},{"start_ms":1000,"end_ms":2000,"text":"two"}]`)
+
+	seg, err := Lookup(data, 2)
+	require.NoError(t, err)
+	require.Equal(t, Segment{StartMs: 1000, EndMs: 2000, Text: "two"}, seg)
+
+	_, err = Lookup(data, 3)
+	require.ErrorIs(t, err, ErrNoSegment)
+	_, err = Lookup(data, 0)
+	require.ErrorIs(t, err, ErrNoSegment)
+	_, err = Lookup(nil, 1)
+	require.ErrorIs(t, err, ErrNoSegment)
+}
+
+func TestSpans(t *testing.T) {
+	text := "Dear Sir,\n  thank you for your letter. नमस्ते दुनिया"
+	segments := []Segment{
+		{Text: "Dear Sir,"},
+		{Text: "thank you for your letter."},
+		{Text: "नमस्ते दुनिया"},
+	}
+
+	spans := Spans(text, segments)
+	runes := []rune(text)
+	require.Len(t, spans, 3)
+	for i, span := range spans {
+		require.Equal(t, segments[i].Text, string(runes[span.Start:span.End]))
+	}
+
+	// Segments beyond the text are placed at its end
+	spans = Spans("Dear Sir,", segments)
+	require.Equal(t, Span{Start: 0, End: 9}, spans[0])
+	require.Equal(t, Span{Start: 9, End: 9}, spans[2])
+}
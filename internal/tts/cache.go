@@ -0,0 +1,266 @@
+package tts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Key is the content address of the audio p returns for req: a hash of the
+// provider, model, voice, speed, format and text. req should already have
+// its defaults filled in, so that equal audio gets equal keys.
+func Key(p Provider, req Request) string {
+	h := sha256.New()
+	for _, part := range []string{
+		p.Name(),
+		p.Model(),
+		req.Voice,
+		strconv.FormatFloat(req.Speed, 'f', -1, 64),
+		string(req.Format),
+		req.Text,
+	} {
+		// Length prefixes keep the parts from running into each other
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CacheStats are the counters of a FileCache.
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+	Bytes   int64 `json:"bytes"`
+}
+
+type cacheEntry struct {
+	path     string
+	format   Format
+	size     int64
+	lastUsed time.Time
+}
+
+// FileCache stores synthesized audio on the local filesystem, one file per
+// Key. When the files grow past maxBytes the least recently used are
+// removed, and entries not used for maxAge expire. It is safe for
+// concurrent use.
+type FileCache struct {
+	dir      string
+	maxBytes int64
+	maxAge   time.Duration
+	now      func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	bytes   int64
+	hits    int64
+	misses  int64
+}
+
+// NewFileCache opens the cache in dir, creating it if needed and indexing
+// the audio already there. A maxBytes or maxAge of zero means no limit.
+func NewFileCache(dir string, maxBytes int64, maxAge time.Duration) (*FileCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cannot create TTS cache directory: %w", err)
+	}
+	c := &FileCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		maxAge:   maxAge,
+		now:      time.Now,
+		entries:  map[string]*cacheEntry{},
+	}
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		name := d.Name()
+		ext := filepath.Ext(name)
+		if ext == "" || strings.HasPrefix(name, ".") {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		key := strings.TrimSuffix(name, ext)
+		c.entries[key] = &cacheEntry{
+			path:     path,
+			format:   Format(ext[1:]),
+			size:     info.Size(),
+			lastUsed: info.ModTime(),
+		}
+		c.bytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot index TTS cache: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictLocked()
+	return c, nil
+}
+
+// Get returns the audio stored under key.
+func (c *FileCache) Get(key string) (Audio, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if ok && c.expiredLocked(e) {
+		c.removeLocked(key)
+		ok = false
+	}
+	if !ok {
+		c.misses++
+		return Audio{}, false
+	}
+
+	data, err := os.ReadFile(e.path)
+	if err != nil {
+		// Removed behind our back; forget it
+		c.removeLocked(key)
+		c.misses++
+		return Audio{}, false
+	}
+	e.lastUsed = c.now()
+	// The modification time records use across restarts
+	_ = os.Chtimes(e.path, e.lastUsed, e.lastUsed)
+	c.hits++
+	return Audio{Data: data, Format: e.format}, true
+}
+
+// Put stores audio under key, then evicts entries beyond the size limit.
+func (c *FileCache) Put(key string, audio Audio) error {
+	path := filepath.Join(c.dir, key[:2], key+"."+string(audio.Format))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Write to a temporary file and rename it, so readers never see a
+	// partial file
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(audio.Data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if old, ok := c.entries[key]; ok {
+		if old.path != path {
+			_ = os.Remove(old.path)
+		}
+		c.bytes -= old.size
+	}
+	c.entries[key] = &cacheEntry{
+		path:     path,
+		format:   audio.Format,
+		size:     int64(len(audio.Data)),
+		lastUsed: c.now(),
+	}
+	c.bytes += int64(len(audio.Data))
+	c.evictLocked()
+	return nil
+}
+
+// Stats returns the cache's hit and miss counts since it was opened and its
+// current size.
+func (c *FileCache) Stats() CacheStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return CacheStats{
+		Hits:    c.hits,
+		Misses:  c.misses,
+		Entries: len(c.entries),
+		Bytes:   c.bytes,
+	}
+}
+
+func (c *FileCache) expiredLocked(e *cacheEntry) bool {
+	return c.maxAge > 0 && c.now().Sub(e.lastUsed) > c.maxAge
+}
+
+// evictLocked drops expired entries, then the least recently used ones
+// until the cache fits in maxBytes.
+func (c *FileCache) evictLocked() {
+	keys := make([]string, 0, len(c.entries))
+	for key, e := range c.entries {
+		if c.expiredLocked(e) {
+			c.removeLocked(key)
+			continue
+		}
+		keys = append(keys, key)
+	}
+	if c.maxBytes <= 0 || c.bytes <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return c.entries[keys[i]].lastUsed.Before(c.entries[keys[j]].lastUsed)
+	})
+	for _, key := range keys {
+		if c.bytes <= c.maxBytes {
+			break
+		}
+		c.removeLocked(key)
+	}
+}
+
+func (c *FileCache) removeLocked(key string) {
+	e := c.entries[key]
+	_ = os.Remove(e.path)
+	c.bytes -= e.size
+	delete(c.entries, key)
+}
+
+// CachedProvider serves repeated requests to a Provider from a FileCache.
+type CachedProvider struct {
+	Provider
+	cache *FileCache
+}
+
+// NewCachedProvider wraps p so that audio is looked up in cache by Key
+// before calling p, and stored there afterwards.
+func NewCachedProvider(p Provider, cache *FileCache) *CachedProvider {
+	return &CachedProvider{Provider: p, cache: cache}
+}
+
+func (p *CachedProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	key := Key(p.Provider, req)
+	if audio, ok := p.cache.Get(key); ok {
+		return audio, nil
+	}
+
+	audio, err := p.Provider.Synthesize(ctx, req)
+	if err != nil {
+		return Audio{}, err
+	}
+	// A failed write only costs a later cache miss
+	_ = p.cache.Put(key, audio)
+	return audio, nil
+}
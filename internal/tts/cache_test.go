@@ -0,0 +1,113 @@
+package tts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// countingProvider counts the calls that reach the wrapped provider.
+type countingProvider struct {
+	Provider
+	calls int
+}
+
+func (p *countingProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	p.calls++
+	return p.Provider.Synthesize(ctx, req)
+}
+
+func TestKey(t *testing.T) {
+	p := NewFakeProvider()
+	req := Request{Text: "hello world", Voice: "sine", Speed: 1}
+	require.Equal(t, Key(p, req), Key(p, req))
+	require.Len(t, Key(p, req), 64)
+
+	for _, other := range []Request{
+		{Text: "hello world!", Voice: "sine", Speed: 1},
+		{Text: "hello world", Voice: "low", Speed: 1},
+		{Text: "hello world", Voice: "sine", Speed: 1.5},
+		{Text: "hello world", Voice: "sine", Speed: 1, Format: FormatWAV},
+	} {
+		require.NotEqual(t, Key(p, req), Key(p, other))
+	}
+	require.NotEqual(t, Key(p, req), Key(NewOpenAIProvider(Options{}), req))
+}
+
+func TestCachedProvider(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), 0, 0)
+	require.NoError(t, err)
+	counting := &countingProvider{Provider: NewFakeProvider()}
+	provider := NewCachedProvider(counting, cache)
+
+	req := Request{Text: "one two three", Voice: "sine", Speed: 1}
+	first, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+	second, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+
+	require.Equal(t, 1, counting.calls)
+	require.Equal(t, first, second)
+	require.Equal(t, "fake", provider.Name())
+
+	stats := cache.Stats()
+	require.Equal(t, int64(1), stats.Hits)
+	require.Equal(t, int64(1), stats.Misses)
+	require.Equal(t, 1, stats.Entries)
+	require.Equal(t, int64(len(first.Data)), stats.Bytes)
+}
+
+func TestFileCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewFileCache(dir, 10, 0)
+	require.NoError(t, err)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	put := func(key string) {
+		now = now.Add(time.Second)
+		require.NoError(t, cache.Put(key, Audio{Data: []byte("abcd"), Format: FormatMP3}))
+	}
+
+	put("aa01")
+	put("aa02")
+	now = now.Add(time.Second)
+	_, ok := cache.Get("aa01")
+	require.True(t, ok)
+
+	// A third entry needs room; aa02 is the least recently used
+	put("aa03")
+	_, ok = cache.Get("aa02")
+	require.False(t, ok)
+	_, ok = cache.Get("aa01")
+	require.True(t, ok)
+	require.Equal(t, int64(8), cache.Stats().Bytes)
+
+	// Entries survive reopening the cache
+	reopened, err := NewFileCache(dir, 10, 0)
+	require.NoError(t, err)
+	audio, ok := reopened.Get("aa03")
+	require.True(t, ok)
+	require.Equal(t, Audio{Data: []byte("abcd"), Format: FormatMP3}, audio)
+	require.Equal(t, 2, reopened.Stats().Entries)
+}
+
+func TestFileCacheExpiry(t *testing.T) {
+	cache, err := NewFileCache(t.TempDir(), 0, time.Hour)
+	require.NoError(t, err)
+
+	now := time.Now()
+	cache.now = func() time.Time { return now }
+	require.NoError(t, cache.Put("bb01", Audio{Data: []byte("abcd"), Format: FormatWAV}))
+
+	now = now.Add(30 * time.Minute)
+	_, ok := cache.Get("bb01")
+	require.True(t, ok)
+
+	now = now.Add(2 * time.Hour)
+	_, ok = cache.Get("bb01")
+	require.False(t, ok)
+	require.Zero(t, cache.Stats().Entries)
+}
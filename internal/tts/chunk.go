@@ -0,0 +1,255 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+)
+
+const (
+	// DefaultMaxChunkChars keeps chunks under the 4,096 character input
+	// limit of the OpenAI speech endpoint.
+	DefaultMaxChunkChars = 4000
+	// DefaultChunkConcurrency is how many chunks are synthesized at once.
+	DefaultChunkConcurrency = 4
+)
+
+// SplitText cuts text into chunks of at most maxChars characters, breaking
+// between sentences where possible, then at clause punctuation, then
+// between words. Only a single word longer than maxChars is cut mid-word.
+func SplitText(text string, maxChars int) []string {
+	var chunks []string
+	var current strings.Builder
+	currentChars := 0
+	flush := func() {
+		if s := strings.TrimSpace(current.String()); s != "" {
+			chunks = append(chunks, s)
+		}
+		current.Reset()
+		currentChars = 0
+	}
+
+	for _, sentence := range sentences(text) {
+		n := utf8.RuneCountInString(sentence)
+		if currentChars > 0 && currentChars+1+n > maxChars {
+			flush()
+		}
+		if n > maxChars {
+			chunks = append(chunks, splitLong(sentence, maxChars)...)
+			continue
+		}
+		if currentChars > 0 {
+			current.WriteByte(' ')
+			currentChars++
+		}
+		current.WriteString(sentence)
+		currentChars += n
+	}
+	flush()
+	return chunks
+}
+
+// sentences splits text after sentence-ending punctuation (including the
+// Devanagari danda) and any closing quotes or brackets, and at blank lines.
+func sentences(text string) []string {
+	var out []string
+	runes := []rune(text)
+	start := 0
+	for i := 0; i < len(runes); i++ {
+		end := -1
+		switch runes[i] {
+		case '.', '!', '?', '…', '।', '॥':
+			j := i + 1
+			for j < len(runes) && strings.ContainsRune(`"'”’)]`, runes[j]) {
+				j++
+			}
+			if j == len(runes) || unicode.IsSpace(runes[j]) {
+				end = j
+			}
+		case '\n':
+			if i+1 < len(runes) && runes[i+1] == '\n' {
+				end = i
+			}
+		}
+		if end < 0 {
+			continue
+		}
+		if s := strings.TrimSpace(string(runes[start:end])); s != "" {
+			out = append(out, s)
+		}
+		start = end
+		i = end
+	}
+	if s := strings.TrimSpace(string(runes[start:])); s != "" {
+		out = append(out, s)
+	}
+	return out
+}
+
+// splitLong cuts a sentence longer than maxChars, preferring to break after
+// clause punctuation and otherwise at the last space that fits.
+func splitLong(sentence string, maxChars int) []string {
+	var out []string
+	runes := []rune(sentence)
+	for len(runes) > maxChars {
+		cut, space := -1, -1
+		for i := maxChars; i > 0; i-- {
+			if unicode.IsSpace(runes[i]) {
+				if space < 0 {
+					space = i
+				}
+				if strings.ContainsRune(",;:—", runes[i-1]) {
+					cut = i
+					break
+				}
+			}
+		}
+		// A clause break is only worth it if it keeps most of the chunk
+		if cut < maxChars/2 {
+			cut = space
+		}
+		if cut <= 0 {
+			cut = maxChars
+		}
+		out = append(out, strings.TrimSpace(string(runes[:cut])))
+		runes = []rune(strings.TrimSpace(string(runes[cut:])))
+	}
+	if len(runes) > 0 {
+		out = append(out, string(runes))
+	}
+	return out
+}
+
+// Duration returns how long a plays for.
+func (a Audio) Duration() (time.Duration, error) {
+	switch a.Format {
+	case FormatWAV:
+		return audio.WAVDuration(a.Data)
+	case FormatMP3:
+		return audio.MP3Duration(a.Data)
+	default:
+		return 0, fmt.Errorf("unknown audio format %q", a.Format)
+	}
+}
+
+// Concat joins audio clips of the same format into one.
+func Concat(clips []Audio) (Audio, error) {
+	if len(clips) == 1 {
+		return clips[0], nil
+	}
+	files := make([][]byte, len(clips))
+	for i, c := range clips {
+		if c.Format != clips[0].Format {
+			return Audio{}, fmt.Errorf("cannot join %s audio to %s audio", c.Format, clips[0].Format)
+		}
+		files[i] = c.Data
+	}
+
+	var data []byte
+	var err error
+	switch clips[0].Format {
+	case FormatWAV:
+		data, err = audio.ConcatWAV(files...)
+	case FormatMP3:
+		data, err = audio.ConcatMP3(files...)
+	default:
+		err = fmt.Errorf("cannot join %s audio", clips[0].Format)
+	}
+	if err != nil {
+		return Audio{}, err
+	}
+	return Audio{Data: data, Format: clips[0].Format}, nil
+}
+
+// ChunkedProvider splits long text into chunks the wrapped provider
+// accepts, synthesizes them concurrently and joins the audio in order.
+// Wrapping a CachedProvider caches every chunk on its own.
+type ChunkedProvider struct {
+	Provider
+	maxChars    int
+	concurrency int
+}
+
+// NewChunkedProvider wraps p to send at most maxChars characters per
+// request and run at most concurrency requests at a time. Zero values take
+// DefaultMaxChunkChars and DefaultChunkConcurrency.
+func NewChunkedProvider(p Provider, maxChars, concurrency int) *ChunkedProvider {
+	if maxChars <= 0 {
+		maxChars = DefaultMaxChunkChars
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultChunkConcurrency
+	}
+	return &ChunkedProvider{Provider: p, maxChars: maxChars, concurrency: concurrency}
+}
+
+func (p *ChunkedProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	chunks := SplitText(req.Text, p.maxChars)
+	if len(chunks) <= 1 {
+		return p.Provider.Synthesize(ctx, req)
+	}
+
+	reqs := make([]Request, len(chunks))
+	for i, chunk := range chunks {
+		reqs[i] = req
+		reqs[i].Text = chunk
+	}
+	clips, err := synthesizeAll(ctx, p.Provider, reqs, p.concurrency)
+	if err != nil {
+		return Audio{}, err
+	}
+	return Concat(clips)
+}
+
+// synthesizeAll runs reqs through p, at most concurrency at a time, and
+// returns the audio in the order of reqs. The first failure cancels the
+// requests still running.
+func synthesizeAll(ctx context.Context, p Provider, reqs []Request, concurrency int) ([]Audio, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	clips := make([]Audio, len(reqs))
+	errs := make([]error, len(reqs))
+	slots := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, req := range reqs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				errs[i] = ctx.Err()
+				return
+			}
+			defer func() { <-slots }()
+
+			clips[i], errs[i] = p.Synthesize(ctx, req)
+			if errs[i] != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+
+	// Report the first request's own failure rather than the cancellations
+	// it caused
+	for _, err := range errs {
+		if err != nil && !errors.Is(err, context.Canceled) {
+			return nil, err
+		}
+	}
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return clips, nil
+}
@@ -0,0 +1,167 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitText(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		maxChars int
+		want     []string
+	}{
+		{
+			name:     "FitsInOne",
+			text:     "One. Two.",
+			maxChars: 100,
+			want:     []string{"One. Two."},
+		},
+		{
+			name:     "PacksSentences",
+			text:     "First one. Second one! Third one? Fourth.",
+			maxChars: 25,
+			want:     []string{"First one. Second one!", "Third one? Fourth."},
+		},
+		{
+			name:     "ClosingQuotes",
+			text:     `He said "stop." Then he left.`,
+			maxChars: 16,
+			want:     []string{`He said "stop."`, "Then he left."},
+		},
+		{
+			name:     "Abbreviations",
+			text:     "Pi is 3.14 exactly. Or not.",
+			maxChars: 20,
+			want:     []string{"Pi is 3.14 exactly.", "Or not."},
+		},
+		{
+			name:     "Danda",
+			text:     "यह पहला वाक्य है। यह दूसरा है।",
+			maxChars: 20,
+			want:     []string{"यह पहला वाक्य है।", "यह दूसरा है।"},
+		},
+		{
+			name:     "BlankLine",
+			text:     "A heading\n\nThe body",
+			maxChars: 12,
+			want:     []string{"A heading", "The body"},
+		},
+		{
+			name:     "LongSentenceAtClause",
+			text:     "the first clause here, and then a second clause",
+			maxChars: 30,
+			want:     []string{"the first clause here,", "and then a second clause"},
+		},
+		{
+			name:     "LongSentenceAtSpace",
+			text:     "one two three four five six",
+			maxChars: 10,
+			want:     []string{"one two", "three four", "five six"},
+		},
+		{
+			name:     "OverlongWord",
+			text:     "abcdefghij",
+			maxChars: 4,
+			want:     []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "Empty",
+			text:     "  \n ",
+			maxChars: 10,
+			want:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := SplitText(tc.text, tc.maxChars)
+			require.Equal(t, tc.want, got)
+			for _, chunk := range got {
+				require.LessOrEqual(t, len([]rune(chunk)), tc.maxChars)
+			}
+		})
+	}
+}
+
+// concurrencyProvider records the most chunks it synthesized at once and fails
+// any text containing "fail".
+type concurrencyProvider struct {
+	Provider
+	mu      sync.Mutex
+	running int
+	peak    int
+}
+
+func (p *concurrencyProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	p.mu.Lock()
+	p.running++
+	p.peak = max(p.peak, p.running)
+	p.mu.Unlock()
+	defer func() {
+		p.mu.Lock()
+		p.running--
+		p.mu.Unlock()
+	}()
+
+	time.Sleep(5 * time.Millisecond)
+	if strings.Contains(req.Text, "fail") {
+		return Audio{}, errors.New("provider failed")
+	}
+	return p.Provider.Synthesize(ctx, req)
+}
+
+func TestChunkedProvider(t *testing.T) {
+	base := &concurrencyProvider{Provider: NewFakeProvider()}
+	provider := NewChunkedProvider(base, 12, 2)
+	require.Equal(t, "fake", provider.Name())
+
+	// Eight chunks of two words each
+	text := strings.Repeat("one two. ", 8)
+	clip, err := provider.Synthesize(context.Background(), Request{Text: text})
+	require.NoError(t, err)
+	require.Equal(t, FormatWAV, clip.Format)
+	require.Equal(t, 2, base.peak)
+
+	// The joined audio plays for as long as the chunks together
+	single, err := base.Provider.Synthesize(context.Background(), Request{Text: "one two."})
+	require.NoError(t, err)
+	want, err := audio.WAVDuration(single.Data)
+	require.NoError(t, err)
+	got, err := audio.WAVDuration(clip.Data)
+	require.NoError(t, err)
+	require.Equal(t, 8*want, got)
+
+	_, err = provider.Synthesize(context.Background(), Request{Text: text + "now fail."})
+	require.EqualError(t, err, "provider failed")
+}
+
+func TestChunkedProviderOrder(t *testing.T) {
+	provider := NewChunkedProvider(NewFakeProvider(), 10, 3)
+
+	// Chunks of different lengths finish out of order but join in order
+	clip, err := provider.Synthesize(context.Background(), Request{Text: "a b c d e. f. g h i."})
+	require.NoError(t, err)
+	joined, err := audio.DecodeWAV(clip.Data)
+	require.NoError(t, err)
+
+	var parts []audio.PCM
+	for _, chunk := range []string{"a b c d e.", "f. g h i."} {
+		part, err := NewFakeProvider().Synthesize(context.Background(), Request{Text: chunk})
+		require.NoError(t, err)
+		pcm, err := audio.DecodeWAV(part.Data)
+		require.NoError(t, err)
+		parts = append(parts, pcm)
+	}
+	want, err := audio.ConcatPCM(parts...)
+	require.NoError(t, err)
+	require.Equal(t, want.Data, joined.Data)
+}
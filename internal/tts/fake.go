@@ -0,0 +1,84 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"strings"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+)
+
+const (
+	fakeSampleRate = 16000
+	// At speed 1 the fake voice takes a short tone and a pause per word,
+	// about 160 words per minute.
+	fakeToneSeconds  = 0.25
+	fakePauseSeconds = 0.125
+)
+
+// fakeVoices differ only in pitch.
+var (
+	fakeVoices = []Voice{
+		{ID: "sine", Name: "Sine", Default: true},
+		{ID: "low", Name: "Low sine"},
+		{ID: "high", Name: "High sine"},
+	}
+	fakeFrequencies = map[string]float64{"sine": 440, "low": 220, "high": 880}
+)
+
+// FakeProvider is a deterministic offline provider for tests and local
+// development. Every word of the text becomes a beep followed by silence,
+// so the same request always yields the same WAV and the duration follows
+// the word count and speed.
+type FakeProvider struct{}
+
+// NewFakeProvider returns a FakeProvider.
+func NewFakeProvider() FakeProvider {
+	return FakeProvider{}
+}
+
+func (FakeProvider) Name() string {
+	return "fake"
+}
+
+func (FakeProvider) Model() string {
+	return "sine"
+}
+
+func (FakeProvider) Voices() []Voice {
+	return fakeVoices
+}
+
+// Synthesize always returns 16 kHz mono 16-bit PCM WAV, whatever format was
+// requested.
+func (p FakeProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	if err := ctx.Err(); err != nil {
+		return Audio{}, err
+	}
+	if err := Validate(p, req); err != nil {
+		return Audio{}, err
+	}
+	frequency := fakeFrequencies[DefaultVoice(p)]
+	if req.Voice != "" {
+		frequency = fakeFrequencies[req.Voice]
+	}
+	speed := req.Speed
+	if speed <= 0 {
+		speed = 1
+	}
+	tone := int(fakeToneSeconds / speed * fakeSampleRate)
+	pause := int(fakePauseSeconds / speed * fakeSampleRate)
+
+	words := len(strings.Fields(req.Text))
+	pcm := audio.PCM{SampleRate: fakeSampleRate, Channels: 1, BitsPerSample: 16}
+	pcm.Data = make([]byte, 0, words*(tone+pause)*2)
+	for range words {
+		for i := range tone {
+			v := 0.3 * math.Sin(2*math.Pi*frequency*float64(i)/fakeSampleRate)
+			pcm.Data = binary.LittleEndian.AppendUint16(pcm.Data, uint16(int16(v*math.MaxInt16)))
+		}
+		pcm.Data = append(pcm.Data, make([]byte, pause*2)...)
+	}
+	return Audio{Data: audio.EncodeWAV(pcm), Format: FormatWAV}, nil
+}
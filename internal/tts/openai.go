@@ -0,0 +1,133 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+	defaultOpenAIModel   = "tts-1"
+)
+
+var openAIVoices = []Voice{
+	{ID: "alloy", Name: "Alloy", Default: true},
+	{ID: "ash", Name: "Ash"},
+	{ID: "coral", Name: "Coral"},
+	{ID: "echo", Name: "Echo"},
+	{ID: "fable", Name: "Fable"},
+	{ID: "nova", Name: "Nova"},
+	{ID: "onyx", Name: "Onyx"},
+	{ID: "sage", Name: "Sage"},
+	{ID: "shimmer", Name: "Shimmer"},
+}
+
+// APIError is returned when a provider's API answers with an error status.
+type APIError struct {
+	Provider   string
+	StatusCode int
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("%s API failed with status %d: %s", e.Provider, e.StatusCode, e.Body)
+}
+
+// OpenAIProvider synthesizes speech with the OpenAI audio speech endpoint,
+// or any server that implements it.
+type OpenAIProvider struct {
+	apiKey  string
+	baseURL string
+	model   string
+	client  *http.Client
+}
+
+// NewOpenAIProvider returns a provider for opts.BaseURL, defaulting to the
+// public OpenAI API and the tts-1 model. One HTTP client is shared by all
+// requests so connections are reused.
+func NewOpenAIProvider(opts Options) *OpenAIProvider {
+	p := &OpenAIProvider{
+		apiKey:  opts.APIKey,
+		baseURL: strings.TrimSuffix(opts.BaseURL, "/"),
+		model:   opts.Model,
+		client:  &http.Client{Timeout: 2 * time.Minute},
+	}
+	if p.baseURL == "" {
+		p.baseURL = defaultOpenAIBaseURL
+	}
+	if p.model == "" {
+		p.model = defaultOpenAIModel
+	}
+	return p
+}
+
+func (p *OpenAIProvider) Name() string {
+	return "openai"
+}
+
+func (p *OpenAIProvider) Model() string {
+	return p.model
+}
+
+func (p *OpenAIProvider) Voices() []Voice {
+	return openAIVoices
+}
+
+type openAISpeechRequest struct {
+	Model          string  `json:"model"`
+	Input          string  `json:"input"`
+	Voice          string  `json:"voice"`
+	ResponseFormat Format  `json:"response_format"`
+	Speed          float64 `json:"speed,omitempty"`
+}
+
+func (p *OpenAIProvider) Synthesize(ctx context.Context, req Request) (Audio, error) {
+	payload := openAISpeechRequest{
+		Model:          p.model,
+		Input:          req.Text,
+		Voice:          req.Voice,
+		ResponseFormat: req.Format,
+		Speed:          req.Speed,
+	}
+	if payload.Voice == "" {
+		payload.Voice = DefaultVoice(p)
+	}
+	if payload.ResponseFormat == "" {
+		payload.ResponseFormat = FormatMP3
+	}
+
+	jsonData, err := json.Marshal(payload)
+	if err != nil {
+		return Audio{}, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/audio/speech", bytes.NewReader(jsonData))
+	if err != nil {
+		return Audio{}, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+	response, err := p.client.Do(request)
+	if err != nil {
+		return Audio{}, err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 4096))
+		return Audio{}, &APIError{Provider: p.Name(), StatusCode: response.StatusCode, Body: string(body)}
+	}
+
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return Audio{}, err
+	}
+	return Audio{Data: data, Format: payload.ResponseFormat}, nil
+}
@@ -0,0 +1,161 @@
+package tts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nilesh0729/PixelScribe/internal/audio"
+)
+
+// Dictation rates accepted by Pace, in words per minute. Shorthand is
+// usually practised between 60 and 120.
+const (
+	MinWPM = 10
+	MaxWPM = 250
+	// DefaultPhraseWords is the longest phrase Pace reads without a pause.
+	DefaultPhraseWords = 8
+)
+
+// ErrPaceTooFast is returned by Pace when the voice alone takes longer than
+// the target rate allows; a higher speed is needed.
+var ErrPaceTooFast = errors.New("voice is too slow for the target WPM")
+
+// PhraseTiming places one phrase in paced audio. Start and End bound its
+// speech; the pause for writing it follows until the next phrase starts.
+type PhraseTiming struct {
+	Text  string
+	Words int
+	Start time.Duration
+	End   time.Duration
+}
+
+// Paced is dictation audio read at a fixed number of words per minute.
+type Paced struct {
+	Audio    Audio
+	WPM      float64
+	Duration time.Duration
+	Phrases  []PhraseTiming
+}
+
+// SplitPhrases breaks text into the phrases a dictation is read in: at
+// punctuation, with longer clauses cut into near-equal runs of at most
+// maxWords words.
+func SplitPhrases(text string, maxWords int) []string {
+	var phrases []string
+	var clause []string
+	flush := func() {
+		// Split n words into the fewest runs that fit, as evenly as possible
+		n := len(clause)
+		runs := (n + maxWords - 1) / maxWords
+		for i := range runs {
+			phrases = append(phrases, strings.Join(clause[i*n/runs:(i+1)*n/runs], " "))
+		}
+		clause = clause[:0]
+	}
+	for _, word := range strings.Fields(text) {
+		clause = append(clause, word)
+		if endsClause(word) {
+			flush()
+		}
+	}
+	if len(clause) > 0 {
+		flush()
+	}
+	return phrases
+}
+
+// endsClause reports whether word ends in punctuation a reader pauses at,
+// ignoring closing quotes and brackets.
+func endsClause(word string) bool {
+	word = strings.TrimRight(word, `"'”’)]`)
+	last, _ := utf8.DecodeLastRuneInString(word)
+	return strings.ContainsRune(",;:.!?…।॥—", last)
+}
+
+// Pace reads text phrase by phrase and inserts silence so the whole
+// dictation runs at wpm words per minute. Each phrase is followed by a
+// pause in proportion to its length, keeping the words spoken at any point
+// in step with the target rate. Phrases and words are those of req.Text as
+// written; say, if not nil, turns each phrase into what the provider
+// reads, so "21" is read as "twenty-one" but still counts as one word.
+// Phrases are synthesized as WAV, at most concurrency at a time.
+func Pace(ctx context.Context, p Provider, req Request, wpm float64, concurrency int, say func(phrase string) string) (Paced, error) {
+	if wpm < MinWPM || wpm > MaxWPM {
+		return Paced{}, fmt.Errorf("wpm must be between %d and %d", MinWPM, MaxWPM)
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultChunkConcurrency
+	}
+	phrases := SplitPhrases(req.Text, DefaultPhraseWords)
+	if len(phrases) == 0 {
+		return Paced{}, errors.New("nothing to read")
+	}
+
+	reqs := make([]Request, len(phrases))
+	for i, phrase := range phrases {
+		reqs[i] = req
+		reqs[i].Text = phrase
+		if say != nil {
+			reqs[i].Text = say(phrase)
+		}
+		reqs[i].Format = FormatWAV
+	}
+	clips, err := synthesizeAll(ctx, p, reqs, concurrency)
+	if err != nil {
+		return Paced{}, err
+	}
+
+	speech := make([]audio.PCM, len(clips))
+	words := make([]int, len(clips))
+	totalWords := 0
+	var spoken time.Duration
+	for i, clip := range clips {
+		speech[i], err = audio.DecodeWAV(clip.Data)
+		if err != nil {
+			return Paced{}, fmt.Errorf("pacing needs WAV audio: %w", err)
+		}
+		words[i] = len(strings.Fields(phrases[i]))
+		totalWords += words[i]
+		spoken += speech[i].Duration()
+	}
+	target := time.Duration(float64(totalWords) / wpm * float64(time.Minute))
+	if spoken > target {
+		return Paced{}, fmt.Errorf("%w: %d words take %s to read, over the %s allowed", ErrPaceTooFast, totalWords, spoken.Round(time.Second), target.Round(time.Second))
+	}
+
+	// Phrase i's slot ends when the words up to and including it are due,
+	// so rounding and long phrases never accumulate drift
+	parts := make([]audio.PCM, 0, 2*len(speech))
+	timings := make([]PhraseTiming, len(speech))
+	var pos time.Duration
+	wordsDue := 0
+	for i, clip := range speech {
+		timings[i] = PhraseTiming{Text: phrases[i], Words: words[i], Start: pos}
+		pos += clip.Duration()
+		timings[i].End = pos
+		parts = append(parts, clip)
+
+		wordsDue += words[i]
+		slotEnd := time.Duration(float64(wordsDue) / float64(totalWords) * float64(target))
+		if gap := slotEnd - pos; gap > 0 {
+			silence := audio.Silence(clip, gap)
+			parts = append(parts, silence)
+			pos += silence.Duration()
+		}
+	}
+
+	joined, err := audio.ConcatPCM(parts...)
+	if err != nil {
+		return Paced{}, err
+	}
+	return Paced{
+		Audio:    Audio{Data: audio.EncodeWAV(joined), Format: FormatWAV},
+		WPM:      float64(totalWords) / joined.Duration().Minutes(),
+		Duration: joined.Duration(),
+		Phrases:  timings,
+	}, nil
+}
@@ -0,0 +1,95 @@
+package tts
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitPhrases(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		maxWords int
+		want     []string
+	}{
+		{
+			name:     "Punctuation",
+			text:     "Dear Sir, we thank you. Yours faithfully",
+			maxWords: 8,
+			want:     []string{"Dear Sir,", "we thank you.", "Yours faithfully"},
+		},
+		{
+			name:     "ClosingQuote",
+			text:     `He said "wait." Then left`,
+			maxWords: 8,
+			want:     []string{`He said "wait."`, "Then left"},
+		},
+		{
+			name:     "Danda",
+			text:     "यह पहला वाक्य है। दूसरा",
+			maxWords: 8,
+			want:     []string{"यह पहला वाक्य है।", "दूसरा"},
+		},
+		{
+			name:     "EvenRuns",
+			text:     "one two three four five six seven",
+			maxWords: 3,
+			want:     []string{"one two", "three four", "five six seven"},
+		},
+		{
+			name:     "Empty",
+			text:     " \n ",
+			maxWords: 8,
+			want:     nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, SplitPhrases(tc.text, tc.maxWords))
+		})
+	}
+}
+
+func TestPace(t *testing.T) {
+	// Twelve words in four phrases; the fake voice reads 160 WPM
+	text := "Dear Sir, we thank you for your letter. We shall reply soon."
+	paced, err := Pace(context.Background(), NewFakeProvider(), Request{Text: text}, 60, 2, nil)
+	require.NoError(t, err)
+
+	require.Equal(t, FormatWAV, paced.Audio.Format)
+	require.InDelta(t, 12*time.Second, paced.Duration, float64(time.Millisecond))
+	require.InDelta(t, 60, paced.WPM, 0.01)
+
+	require.Len(t, paced.Phrases, 3)
+	require.Equal(t, PhraseTiming{Text: "Dear Sir,", Words: 2, End: 750 * time.Millisecond}, paced.Phrases[0])
+	// Each phrase starts when the words before it are due at 60 WPM
+	require.InDelta(t, 2*time.Second, paced.Phrases[1].Start, float64(time.Millisecond))
+	require.InDelta(t, 8*time.Second, paced.Phrases[2].Start, float64(time.Millisecond))
+	require.InDelta(t, 9500*time.Millisecond, paced.Phrases[2].End, float64(time.Millisecond))
+
+	_, err = Pace(context.Background(), NewFakeProvider(), Request{Text: text}, 200, 2, nil)
+	require.ErrorIs(t, err, ErrPaceTooFast)
+	// A faster voice reaches the same rate
+	_, err = Pace(context.Background(), NewFakeProvider(), Request{Text: text, Speed: 1.5}, 200, 2, nil)
+	require.NoError(t, err)
+
+	_, err = Pace(context.Background(), NewFakeProvider(), Request{Text: text}, 5, 2, nil)
+	require.Error(t, err)
+}
+
+func TestPaceCountsWrittenWords(t *testing.T) {
+	// Each phrase is read twice over, but only the written words set the pace
+	twice := func(phrase string) string { return phrase + " " + phrase }
+	paced, err := Pace(context.Background(), NewFakeProvider(), Request{Text: "Dear Sir, thank you."}, 60, 2, twice)
+	require.NoError(t, err)
+
+	require.InDelta(t, 4*time.Second, paced.Duration, float64(time.Millisecond))
+	require.InDelta(t, 60, paced.WPM, 0.01)
+	require.Len(t, paced.Phrases, 2)
+	require.Equal(t, PhraseTiming{Text: "Dear Sir,", Words: 2, End: 1500 * time.Millisecond}, paced.Phrases[0])
+	require.InDelta(t, 2*time.Second, paced.Phrases[1].Start, float64(time.Millisecond))
+}
@@ -0,0 +1,161 @@
+// Package tts turns dictation text into speech through a pluggable Provider.
+package tts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// Format is an audio encoding a provider can return.
+type Format string
+
+const (
+	FormatMP3 Format = "mp3"
+	FormatWAV Format = "wav"
+)
+
+// ContentType returns the MIME type of audio in format f.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatWAV:
+		return "audio/wav"
+	default:
+		return "audio/mpeg"
+	}
+}
+
+// Request describes one piece of speech to synthesize. Empty fields take the
+// provider's defaults.
+type Request struct {
+	Text   string
+	Voice  string
+	Speed  float64
+	Format Format
+}
+
+// Speech rates accepted in Request.Speed, where 1 is the voice's natural
+// pace.
+const (
+	MinSpeed = 0.25
+	MaxSpeed = 4.0
+)
+
+// Voice is one entry of a provider's voice catalogue.
+type Voice struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Default marks the voice used when a request names none.
+	Default bool `json:"default,omitempty"`
+}
+
+// Audio is synthesized speech.
+type Audio struct {
+	Data   []byte
+	Format Format
+}
+
+// Provider synthesizes speech. Implementations must be safe for concurrent
+// use.
+type Provider interface {
+	// Name identifies the provider, such as "openai".
+	Name() string
+	// Model is the provider's speech model, such as "tts-1".
+	Model() string
+	// Voices lists the voices Synthesize accepts. Exactly one is the
+	// default.
+	Voices() []Voice
+	Synthesize(ctx context.Context, req Request) (Audio, error)
+}
+
+// DefaultVoice returns the ID of p's default voice.
+func DefaultVoice(p Provider) string {
+	for _, v := range p.Voices() {
+		if v.Default {
+			return v.ID
+		}
+	}
+	return ""
+}
+
+// HasVoice reports whether id is in p's voice catalogue.
+func HasVoice(p Provider, id string) bool {
+	for _, v := range p.Voices() {
+		if v.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the voice and speed of req against p. Empty fields are
+// valid since they take the provider's defaults.
+func Validate(p Provider, req Request) error {
+	if req.Voice != "" && !HasVoice(p, req.Voice) {
+		return fmt.Errorf("unknown voice %q for TTS provider %s", req.Voice, p.Name())
+	}
+	if req.Speed != 0 && (req.Speed < MinSpeed || req.Speed > MaxSpeed) {
+		return fmt.Errorf("speed must be between %g and %g", MinSpeed, MaxSpeed)
+	}
+	return nil
+}
+
+// WithDefaults fills the voice and speed req leaves empty, first from the
+// preferred voice and speed where p supports them, then with p's default
+// voice at normal speed.
+func WithDefaults(p Provider, req Request, voice string, speed float64) Request {
+	if req.Voice == "" && voice != "" && HasVoice(p, voice) {
+		req.Voice = voice
+	}
+	if req.Speed == 0 && speed != 0 && Validate(p, Request{Speed: speed}) == nil {
+		req.Speed = speed
+	}
+	if req.Voice == "" {
+		req.Voice = DefaultVoice(p)
+	}
+	if req.Speed == 0 {
+		req.Speed = 1
+	}
+	return req
+}
+
+// Options configure the provider returned by NewProvider. Fields a provider
+// does not use are ignored.
+type Options struct {
+	APIKey string
+	// BaseURL points an HTTP provider at another server, such as a local
+	// stub. Empty uses the vendor's public API.
+	BaseURL string
+	Model   string
+}
+
+var providers = map[string]func(Options) Provider{
+	"openai": func(opts Options) Provider { return NewOpenAIProvider(opts) },
+	"fake":   func(Options) Provider { return NewFakeProvider() },
+}
+
+// DefaultProvider is the provider used when none is configured.
+const DefaultProvider = "openai"
+
+// NewProvider returns the provider registered under name, or DefaultProvider
+// when name is empty.
+func NewProvider(name string, opts Options) (Provider, error) {
+	if name == "" {
+		name = DefaultProvider
+	}
+	newProvider, ok := providers[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown TTS provider %q, available: %v", name, Providers())
+	}
+	return newProvider(opts), nil
+}
+
+// Providers returns the names accepted by NewProvider in sorted order.
+func Providers() []string {
+	names := make([]string, 0, len(providers))
+	for name := range providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,107 @@
+package tts
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIProvider(t *testing.T) {
+	var got openAISpeechRequest
+	stub := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/v1/audio/speech", r.URL.Path)
+		require.Equal(t, "Bearer test-key", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		if got.Input == "fail" {
+			http.Error(w, "quota exceeded", http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte("mp3 data"))
+	}))
+	defer stub.Close()
+
+	provider, err := NewProvider("openai", Options{APIKey: "test-key", BaseURL: stub.URL + "/v1/"})
+	require.NoError(t, err)
+	require.Equal(t, "tts-1", provider.Model())
+
+	audio, err := provider.Synthesize(context.Background(), Request{Text: "hello world", Speed: 1.25})
+	require.NoError(t, err)
+	require.Equal(t, []byte("mp3 data"), audio.Data)
+	require.Equal(t, FormatMP3, audio.Format)
+	require.Equal(t, openAISpeechRequest{
+		Model:          "tts-1",
+		Input:          "hello world",
+		Voice:          "alloy",
+		ResponseFormat: FormatMP3,
+		Speed:          1.25,
+	}, got)
+
+	_, err = provider.Synthesize(context.Background(), Request{Text: "fail"})
+	var apiErr *APIError
+	require.True(t, errors.As(err, &apiErr))
+	require.Equal(t, http.StatusTooManyRequests, apiErr.StatusCode)
+	require.Contains(t, apiErr.Body, "quota exceeded")
+}
+
+func TestFakeProvider(t *testing.T) {
+	provider, err := NewProvider("fake", Options{})
+	require.NoError(t, err)
+
+	req := Request{Text: "one two three four", Voice: "low"}
+	audio, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, FormatWAV, audio.Format)
+	require.Equal(t, "RIFF", string(audio.Data[:4]))
+	require.Equal(t, "WAVE", string(audio.Data[8:12]))
+
+	// Four words at 160 words per minute take 1.5 seconds
+	dataSize := binary.LittleEndian.Uint32(audio.Data[40:44])
+	require.Equal(t, uint32(1.5*fakeSampleRate*2), dataSize)
+	require.Len(t, audio.Data, 44+int(dataSize))
+
+	again, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, audio.Data, again.Data)
+
+	req.Speed = 2
+	fast, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+	require.Equal(t, uint32(0.75*fakeSampleRate*2), binary.LittleEndian.Uint32(fast.Data[40:44]))
+
+	req.Speed = 1
+	req.Voice = "high"
+	high, err := provider.Synthesize(context.Background(), req)
+	require.NoError(t, err)
+	require.Len(t, high.Data, len(audio.Data))
+	require.NotEqual(t, audio.Data, high.Data)
+
+	req.Voice = "alloy"
+	_, err = provider.Synthesize(context.Background(), req)
+	require.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	provider := NewOpenAIProvider(Options{})
+	require.Equal(t, "alloy", DefaultVoice(provider))
+	require.NoError(t, Validate(provider, Request{}))
+	require.NoError(t, Validate(provider, Request{Voice: "nova", Speed: 0.25}))
+	require.Error(t, Validate(provider, Request{Voice: "en-US-Neural2-F"}))
+	require.Error(t, Validate(provider, Request{Speed: 4.5}))
+	require.Error(t, Validate(provider, Request{Speed: -1}))
+}
+
+func TestNewProviderUnknown(t *testing.T) {
+	_, err := NewProvider("polly", Options{})
+	require.Error(t, err)
+
+	provider, err := NewProvider("", Options{})
+	require.NoError(t, err)
+	require.Equal(t, "openai", provider.Name())
+	require.Equal(t, []string{"fake", "openai"}, Providers())
+}
@@ -13,6 +13,62 @@ type Config struct {
 	TokenSymmetricKey  string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	OpenAIKey           string        `mapstructure:"OPENAI_API_KEY"`
+	// TTSProvider selects the tts.Provider: "openai" (the default) or
+	// "fake" for offline development and tests.
+	TTSProvider         string        `mapstructure:"TTS_PROVIDER"`
+	// TTSBaseURL points the provider at another server, such as a local
+	// stub of the OpenAI API. Empty uses the public API.
+	TTSBaseURL          string        `mapstructure:"TTS_BASE_URL"`
+	// TTSModel overrides the provider's speech model, e.g. "tts-1-hd".
+	TTSModel            string        `mapstructure:"TTS_MODEL"`
+	// TTSCacheDir enables the audio cache in that directory. Empty disables
+	// it.
+	TTSCacheDir         string        `mapstructure:"TTS_CACHE_DIR"`
+	// TTSCacheMaxBytes caps the cache size; the least recently used audio
+	// is evicted beyond it. Zero means no limit.
+	TTSCacheMaxBytes    int64         `mapstructure:"TTS_CACHE_MAX_BYTES"`
+	// TTSCacheMaxAge expires audio not played for that long. Zero keeps it.
+	TTSCacheMaxAge      time.Duration `mapstructure:"TTS_CACHE_MAX_AGE"`
+	// TTSMaxChunkChars is the longest text sent to the provider in one
+	// request; longer text is split between sentences. Defaults to 4000.
+	TTSMaxChunkChars    int           `mapstructure:"TTS_MAX_CHUNK_CHARS"`
+	// TTSConcurrency is how many chunks of one text are synthesized at once.
+	// Defaults to 4.
+	TTSConcurrency      int           `mapstructure:"TTS_CONCURRENCY"`
+	// TTSMaxChunks is how many chunks of TTSMaxChunkChars the text of one
+	// /tts/generate request may fill; longer text is refused. Defaults to 10.
+	TTSMaxChunks        int           `mapstructure:"TTS_MAX_CHUNKS"`
+	// StorageBackend selects where dictation audio is stored: "local" in
+	// StorageDir or "s3". Empty means local when StorageDir is set, and
+	// otherwise disables storage, uploads and pre-generated audio.
+	StorageBackend      string        `mapstructure:"STORAGE_BACKEND"`
+	StorageDir          string        `mapstructure:"STORAGE_DIR"`
+	// S3Endpoint points the s3 backend at an S3-compatible server such as
+	// MinIO. Empty uses AWS.
+	S3Endpoint          string        `mapstructure:"S3_ENDPOINT"`
+	S3Region            string        `mapstructure:"S3_REGION"`
+	S3Bucket            string        `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID       string        `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey   string        `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	// S3PathStyle puts the bucket in the URL path, as MinIO requires.
+	S3PathStyle         bool          `mapstructure:"S3_PATH_STYLE"`
+	// UploadMaxBytes caps uploaded audio files. Defaults to 50 MiB.
+	UploadMaxBytes      int64         `mapstructure:"UPLOAD_MAX_BYTES"`
+	// AudioJobWorkers is how many dictations have audio generated at once.
+	// Defaults to 2.
+	AudioJobWorkers     int           `mapstructure:"AUDIO_JOB_WORKERS"`
+	// AudioJobMaxAttempts is how often audio generation is tried before a
+	// dictation is marked failed. Defaults to 3.
+	AudioJobMaxAttempts int           `mapstructure:"AUDIO_JOB_MAX_ATTEMPTS"`
+	// Scorer selects the scoring.Scorer for new attempts, e.g. "wordalign"
+	// or a pinned version such as "wordalign/v7". Empty uses the default.
+	Scorer              string        `mapstructure:"SCORER"`
+	// AttemptSessionDuration is how long a started attempt may take before it
+	// can no longer be submitted. Defaults to two hours.
+	AttemptSessionDuration time.Duration `mapstructure:"ATTEMPT_SESSION_DURATION"`
+	// AdminUsernames may read operational endpoints such as the TTS cache
+	// stats. Set it as a comma-separated list.
+	AdminUsernames         []string      `mapstructure:"ADMIN_USERNAMES"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -35,6 +91,29 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.BindEnv("TOKEN_SYMMETRIC_KEY")
 	viper.BindEnv("ACCESS_TOKEN_DURATION")
 	viper.BindEnv("OPENAI_API_KEY")
+	viper.BindEnv("TTS_PROVIDER")
+	viper.BindEnv("TTS_BASE_URL")
+	viper.BindEnv("TTS_MODEL")
+	viper.BindEnv("TTS_CACHE_DIR")
+	viper.BindEnv("TTS_CACHE_MAX_BYTES")
+	viper.BindEnv("TTS_CACHE_MAX_AGE")
+	viper.BindEnv("TTS_MAX_CHUNK_CHARS")
+	viper.BindEnv("TTS_CONCURRENCY")
+	viper.BindEnv("TTS_MAX_CHUNKS")
+	viper.BindEnv("STORAGE_BACKEND")
+	viper.BindEnv("STORAGE_DIR")
+	viper.BindEnv("S3_ENDPOINT")
+	viper.BindEnv("S3_REGION")
+	viper.BindEnv("S3_BUCKET")
+	viper.BindEnv("S3_ACCESS_KEY_ID")
+	viper.BindEnv("S3_SECRET_ACCESS_KEY")
+	viper.BindEnv("S3_PATH_STYLE")
+	viper.BindEnv("UPLOAD_MAX_BYTES")
+	viper.BindEnv("AUDIO_JOB_WORKERS")
+	viper.BindEnv("AUDIO_JOB_MAX_ATTEMPTS")
+	viper.BindEnv("SCORER")
+	viper.BindEnv("ATTEMPT_SESSION_DURATION")
+	viper.BindEnv("ADMIN_USERNAMES")
 
 	// Try to read config file, but don't fail if it doesn't exist
 	_ = viper.ReadInConfig()  // Ignore all errors from file reading
@@ -8,6 +8,8 @@ export function useDictationEngine(targetContent: string) {
     const [currentText, setCurrentText] = useState('');
     const [startTime, setStartTime] = useState<number | null>(null);
     const [endTime, setEndTime] = useState<number | null>(null);
+    // Every key pressed while typing, corrections included (for KSPC)
+    const [keystrokes, setKeystrokes] = useState(0);
 
     // Audio State
     const audioRef = useRef<HTMLAudioElement | null>(null);
@@ -76,12 +78,17 @@ export function useDictationEngine(targetContent: string) {
         setPhase('typing');
         setStartTime(Date.now());
         setCurrentText('');
+        setKeystrokes(0);
     }, []);
 
     const handleInput = useCallback((text: string) => {
         setCurrentText(text);
     }, []);
 
+    const recordKeystroke = useCallback(() => {
+        setKeystrokes((count) => count + 1);
+    }, []);
+
     const complete = useCallback(() => {
         setEndTime(Date.now());
         setPhase('completed');
@@ -102,10 +109,12 @@ export function useDictationEngine(targetContent: string) {
         controls: {
             startTyping,
             handleInput,
+            recordKeystroke,
             complete
         },
         stats: {
-            timeSpent: timeSpentSeconds
+            timeSpent: timeSpentSeconds,
+            keystrokes
         }
     };
 }
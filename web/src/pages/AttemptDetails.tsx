@@ -1,6 +1,6 @@
 import { useEffect, useState } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
-import { attemptService, type AttemptResponse } from '../services/attempt';
+import { attemptService, type AttemptResponse, type ComparisonData, type ComparisonToken } from '../services/attempt';
 import { dictationService } from '../services/dictation';
 import type { Dictation } from '../types/dictation';
 // Safe import for diff which handles both ESM and CommonJS in Vite
@@ -8,6 +8,56 @@ import * as Diff from 'diff';
 const diffWords = (Diff as any).default?.diffWords || Diff.diffWords;
 import { Loader2, ArrowLeft, Calendar, FileText, CheckCircle, Clock } from 'lucide-react';
 
+const isBreak = (word?: string) => word === '\n' || word === '\n\n';
+
+// Line and paragraph breaks from layout scoring render as the break itself
+// when matched, and as a pilcrow when missed or extra.
+function breakParts(token: ComparisonToken): Diff.Change[] {
+    if (token.op === 'match') {
+        return [{ value: `${token.original}` }];
+    }
+    const parts: Diff.Change[] = [];
+    if (token.typed !== undefined) {
+        parts.push({ value: isBreak(token.typed) ? '¶' : `${token.typed}`, added: true });
+    }
+    if (token.original !== undefined) {
+        parts.push({ value: isBreak(token.original) ? '¶' : `${token.original}`, removed: true });
+    }
+    parts.push({ value: isBreak(token.original) ? `${token.original}` : ' ' });
+    return parts;
+}
+
+// Turns the server's comparison_data into the same added/removed parts the
+// client-side diff produces, so both render with one set of styles.
+function comparisonToParts(comparison: ComparisonData): Diff.Change[] {
+    const parts: Diff.Change[] = [];
+    for (const token of comparison.tokens) {
+        if (isBreak(token.original) || isBreak(token.typed)) {
+            parts.push(...breakParts(token));
+            continue;
+        }
+        switch (token.op) {
+            case 'match':
+                parts.push({ value: `${token.original} ` });
+                break;
+            case 'substitute':
+                parts.push({ value: `${token.typed}`, added: true });
+                parts.push({ value: `${token.original}`, removed: true });
+                parts.push({ value: ' ' });
+                break;
+            case 'omit':
+                parts.push({ value: `${token.original}`, removed: true });
+                parts.push({ value: ' ' });
+                break;
+            case 'insert':
+                parts.push({ value: `${token.typed}`, added: true });
+                parts.push({ value: ' ' });
+                break;
+        }
+    }
+    return parts;
+}
+
 export default function AttemptDetails() {
     const { id } = useParams();
     const navigate = useNavigate();
@@ -28,8 +78,11 @@ export default function AttemptDetails() {
                 const matchedDictation = allDictations.find(d => d.id === attemptData.dictation_id);
                 setDictation(matchedDictation || null);
 
-                if (matchedDictation) {
-                    // Calculate diff
+                if (attemptData.comparison_data?.tokens) {
+                    // Render exactly what the server scored
+                    setDiffParts(comparisonToParts(attemptData.comparison_data));
+                } else if (matchedDictation) {
+                    // Older attempts have no server diff; calculate one locally
                     // valid logic: diffWords(original, typed)
                     // added: true -> present in typed but NOT in original (Extra/Wrong word) -> Red
                     // removed: true -> present in original but NOT in typed (Missed word) -> Green (or Strikeout)
@@ -72,6 +125,11 @@ export default function AttemptDetails() {
                             <CheckCircle className="h-5 w-5 mr-1" />
                             {attempt.accuracy.toFixed(1)}%
                         </div>
+                        {attempt.weighted_accuracy !== undefined && (
+                            <span className="text-xs text-green-600" title="Near-miss words earn partial credit">
+                                {attempt.weighted_accuracy.toFixed(1)}% weighted
+                            </span>
+                        )}
                     </div>
                     <div className="bg-blue-50 px-4 py-2 rounded-xl border border-blue-100 flex flex-col items-center">
                         <span className="text-xs text-blue-600 font-medium uppercase tracking-wider">Time</span>
@@ -80,6 +138,15 @@ export default function AttemptDetails() {
                             {Math.round(attempt.time_spent)}s
                         </div>
                     </div>
+                    <div className="bg-purple-50 px-4 py-2 rounded-xl border border-purple-100 flex flex-col items-center">
+                        <span className="text-xs text-purple-600 font-medium uppercase tracking-wider">Speed</span>
+                        <div className="text-xl font-bold text-purple-700">
+                            {Math.round(attempt.net_wpm || 0)} WPM
+                        </div>
+                        <span className="text-xs text-purple-600">
+                            {Math.round(attempt.gross_wpm || 0)} gross · {Math.round(attempt.cpm || 0)} CPM
+                        </span>
+                    </div>
                 </div>
             </div>
 
@@ -111,11 +178,15 @@ export default function AttemptDetails() {
 
             {/* Server Stats Panel */}
             <div className="grid grid-cols-1 md:grid-cols-3 gap-4">
-                <StatCard title="Total Words" value={attempt.performance_update?.total_attempts || 0} icon={FileText} />
-                {/* Note: In a real app we would pass 'CorrectWords' etc directly in response too, currently relying on perf update which is aggregated. 
-                      Actually attempt object has 'correctWords' calculated on backend but response struct in api/attempt.go didn't include it explicitly in root (my bad). 
-                      It's fine, the visual diff is the key feature here.
-                  */}
+                <StatCard title="Total Words" value={attempt.total_words} icon={FileText} />
+                <StatCard title="Correct Words" value={attempt.correct_words} icon={CheckCircle} />
+                <StatCard title="Missed / Extra" value={`${attempt.omission_errors} / ${attempt.insertion_errors}`} icon={FileText} />
+                <StatCard title="Wrong Words" value={attempt.wrong_word_errors} icon={FileText} />
+                <StatCard title="Spelling" value={attempt.spelling_errors} icon={FileText} />
+                <StatCard title="Case / Punctuation" value={`${attempt.case_errors} / ${attempt.punctuation_errors}`} icon={FileText} />
+                {attempt.paragraph_errors > 0 && (
+                    <StatCard title="Paragraph Breaks" value={attempt.paragraph_errors} icon={FileText} />
+                )}
             </div>
         </div>
     );
@@ -77,6 +77,9 @@ export default function CreateDictation() {
                                 >
                                     <option value="en-US">English (US)</option>
                                     <option value="en-GB">English (UK)</option>
+                                    <option value="hi-IN">Hindi</option>
+                                    <option value="zh-CN">Chinese (Simplified)</option>
+                                    <option value="ja-JP">Japanese</option>
                                 </select>
                             </div>
                         </div>
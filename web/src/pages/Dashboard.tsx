@@ -134,6 +134,9 @@ export default function Dashboard() {
                                                 <p className="text-sm font-medium text-gray-900">
                                                     {Number(item.average_accuracy || 0).toFixed(1)}% Acc
                                                 </p>
+                                                <p className="text-xs text-gray-500 mt-1">
+                                                    {Math.round(item.rolling_net_wpm || 0)} WPM
+                                                </p>
                                                 <p className="text-xs text-gray-500 mt-1">
                                                     {item.total_attempts || 0} attempts
                                                 </p>
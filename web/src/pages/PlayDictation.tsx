@@ -2,7 +2,7 @@ import { useEffect, useState, useRef } from 'react';
 import { useParams, useNavigate } from 'react-router-dom';
 import { dictationService } from '../services/dictation';
 import { attemptService } from '../services/attempt';
-import type { AttemptResponse } from '../services/attempt';
+import type { AttemptResponse, InputEncoding } from '../services/attempt';
 import { useDictationEngine } from '../hooks/useDictationEngine';
 import type { Dictation } from '../types/dictation';
 import { Play, Pause, RotateCcw, Check, ArrowLeft, Ear, Keyboard, Loader2 } from 'lucide-react';
@@ -14,6 +14,8 @@ export default function PlayDictation() {
     const [loading, setLoading] = useState(true);
     const [submitting, setSubmitting] = useState(false);
     const [result, setResult] = useState<AttemptResponse | null>(null);
+    const [sessionToken, setSessionToken] = useState<string | null>(null);
+    const [inputEncoding, setInputEncoding] = useState<InputEncoding>('unicode');
 
     // Load Dictation Data
     useEffect(() => {
@@ -46,6 +48,18 @@ export default function PlayDictation() {
 
     const inputRef = useRef<HTMLTextAreaElement>(null);
 
+    const handleStartTyping = async () => {
+        if (!dictation) return;
+        try {
+            const session = await attemptService.start(dictation.id);
+            setSessionToken(session.session_token);
+            controls.startTyping();
+        } catch (error) {
+            console.error("Failed to start attempt", error);
+            alert("Failed to start attempt.");
+        }
+    };
+
     // Focus input when separate typing phase starts
     useEffect(() => {
         if (phase === 'typing' && inputRef.current) {
@@ -54,13 +68,16 @@ export default function PlayDictation() {
     }, [phase]);
 
     const handleSave = async () => {
-        if (!dictation) return;
+        if (!dictation || !sessionToken) return;
         setSubmitting(true);
         try {
             const response = await attemptService.submit({
                 dictation_id: dictation.id,
+                session_token: sessionToken,
                 typed_text: currentText,
                 time_spent: stats.timeSpent,
+                keystrokes: stats.keystrokes,
+                input_encoding: inputEncoding,
             });
             setResult(response);
             controls.complete(); // Mark engine as completed
@@ -123,7 +140,7 @@ export default function PlayDictation() {
                         </div>
 
                         <div className="pt-8 border-t border-gray-100">
-                            <button onClick={controls.startTyping} disabled={phase === 'loading'} className="w-full flex items-center justify-center px-6 py-4 border-2 border-indigo-600 text-indigo-700 rounded-lg text-lg font-bold hover:bg-indigo-50 transition">
+                            <button onClick={handleStartTyping} disabled={phase === 'loading'} className="w-full flex items-center justify-center px-6 py-4 border-2 border-indigo-600 text-indigo-700 rounded-lg text-lg font-bold hover:bg-indigo-50 transition">
                                 <Keyboard className="h-6 w-6 mr-2" /> I'm Ready to Type
                             </button>
                         </div>
@@ -138,10 +155,26 @@ export default function PlayDictation() {
                             <span>Time: {Math.round(stats.timeSpent)}s</span>
                         </div>
 
+                        {dictation?.language?.startsWith('hi') && (
+                            <label className="flex items-center gap-2 text-sm text-gray-600">
+                                Keyboard font
+                                <select
+                                    value={inputEncoding}
+                                    onChange={(e) => setInputEncoding(e.target.value as InputEncoding)}
+                                    className="border border-gray-300 rounded-md px-2 py-1 text-gray-900"
+                                >
+                                    <option value="unicode">Unicode (Mangal / Inscript)</option>
+                                    <option value="krutidev">Kruti Dev 010</option>
+                                    <option value="devlys">DevLys 010</option>
+                                </select>
+                            </label>
+                        )}
+
                         <textarea
                             ref={inputRef}
                             value={currentText}
                             onChange={(e) => controls.handleInput(e.target.value)}
+                            onKeyDown={controls.recordKeystroke}
                             className="w-full h-80 p-6 text-lg text-gray-900 border-2 border-indigo-100 rounded-xl focus:border-indigo-500 focus:ring-0 resize-none font-mono leading-relaxed shadow-inner bg-gray-50 placeholder:text-gray-400"
                             placeholder="Type what you heard here..."
                         />
@@ -188,6 +221,14 @@ export default function PlayDictation() {
                                 <p className="text-sm text-gray-500 uppercase font-semibold">Time</p>
                                 <p className="text-3xl font-bold text-gray-600">{Math.round(result.time_spent)}s</p>
                             </div>
+                            <div className="bg-gray-50 p-4 rounded-xl">
+                                <p className="text-sm text-gray-500 uppercase font-semibold">Net WPM</p>
+                                <p className="text-3xl font-bold text-gray-600">{Math.round(result.net_wpm)}</p>
+                            </div>
+                            <div className="bg-gray-50 p-4 rounded-xl">
+                                <p className="text-sm text-gray-500 uppercase font-semibold">Gross WPM</p>
+                                <p className="text-3xl font-bold text-gray-600">{Math.round(result.gross_wpm)}</p>
+                            </div>
                         </div>
 
                         <div className="flex flex-col space-y-3">
@@ -1,10 +1,68 @@
 import api from '../lib/axios';
 
 
+export interface StartAttemptResponse {
+    session_token: string;
+    session_id: string;
+    dictation_id: number;
+    segment?: number;
+    started_at: string;
+    expires_at: string;
+}
+
 export interface AttemptRequest {
     dictation_id: number;
+    session_token: string; // from attemptService.start
     typed_text: string;
     time_spent: number; // in seconds
+    keystrokes?: number; // every key pressed, corrections included
+    input_encoding?: InputEncoding;
+}
+
+// Legacy Hindi fonts whose typed text the server converts to Unicode
+export type InputEncoding = 'unicode' | 'krutidev' | 'devlys';
+
+// Server-generated word diff, see docs/schemas/comparison_data.v1.json
+export interface ComparisonSpan {
+    start: number;
+    end: number;
+}
+
+export interface ComparisonToken {
+    op: 'match' | 'substitute' | 'omit' | 'insert';
+    category?: string;
+    // Rule that accepted a differently written match, e.g. "number"
+    tolerance?: string;
+    original?: string;
+    typed?: string;
+    original_index?: number;
+    typed_index?: number;
+    original_words?: number;
+    typed_words?: number;
+    original_span?: ComparisonSpan;
+    typed_span?: ComparisonSpan;
+}
+
+export interface ComparisonData {
+    version: number;
+    tokens: ComparisonToken[];
+}
+
+export interface MarkSheet {
+    rubric: string;
+    total_words: number;
+    full_mistakes: number;
+    half_mistakes: number;
+    omissions: number;
+    substitutions: number;
+    additions: number;
+    spelling: number;
+    capitalisation: number;
+    punctuation: number;
+    paragraphing: number;
+    error_percentage: number;
+    pass_threshold: number;
+    passed: boolean;
 }
 
 export interface AttemptResponse {
@@ -12,19 +70,56 @@ export interface AttemptResponse {
     user_id: number;
     dictation_id: number;
     typed_text: string;
+    input_encoding: InputEncoding;
     attempt_no: number;
+    // Set when only one segment of an audio dictation was attempted
+    segment?: number;
     accuracy: number;
-    time_spent: number;
+    // Accuracy with partial credit for near-miss words
+    weighted_accuracy?: number;
+    time_spent: number; // measured by the server from the attempt session
+    client_time_spent?: number;
+    time_flagged: boolean;
     created_at: string;
+    total_words: number;
+    correct_words: number;
+    wrong_word_errors: number;
+    grammatical_errors: number; // not checked yet, always 0
+    spelling_errors: number;
+    case_errors: number;
+    punctuation_errors: number;
+    omission_errors: number;
+    insertion_errors: number;
+    paragraph_errors: number;
+    gross_wpm: number;
+    net_wpm: number;
+    cpm: number;
+    char_accuracy: number;
+    kspc?: number;
+    comparison_data?: ComparisonData;
+    mark_sheet?: MarkSheet;
     performance_update?: {
         total_attempts: number;
         best_accuracy: number;
         average_accuracy: number;
         average_time: number;
+        rolling_gross_wpm: number;
+        rolling_net_wpm: number;
+        rolling_cpm: number;
+        rolling_char_accuracy: number;
+        best_weighted_accuracy: number;
+        average_weighted_accuracy: number;
     };
 }
 
 export const attemptService = {
+    // Starts the server clock for an attempt; the token must be sent on submit.
+    // With a segment, only that segment is scored.
+    start: async (dictationId: number, segment?: number): Promise<StartAttemptResponse> => {
+        const response = await api.post<StartAttemptResponse>('/attempts/start', { dictation_id: dictationId, segment });
+        return response.data;
+    },
+
     submit: async (data: AttemptRequest): Promise<AttemptResponse> => {
         const response = await api.post<AttemptResponse>('/attempts', data);
         return response.data;
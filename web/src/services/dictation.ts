@@ -1,5 +1,5 @@
 import api from '../lib/axios';
-import type { Dictation, CreateDictationRequest } from '../types/dictation';
+import type { Dictation, CreateDictationRequest, DictationSegment, LexiconEntry, SetTranscriptRequest, Waveform } from '../types/dictation';
 
 export const dictationService = {
     // Get all dictations (with optional pagination/filtering later)
@@ -14,6 +14,71 @@ export const dictationService = {
         return response.data;
     },
 
+    // Create an audio dictation from a recorded MP3 or WAV file; it can only
+    // be scored once it has a transcript
+    upload: async (file: File, title: string, language: string, transcript?: string) => {
+        const form = new FormData();
+        form.append('audio', file);
+        form.append('title', title);
+        form.append('language', language);
+        if (transcript) {
+            form.append('transcript', transcript);
+        }
+        const response = await api.post<Dictation>('/dictations/upload', form);
+        return response.data;
+    },
+
+    // Add the reference transcript an audio dictation is scored against
+    setTranscript: async (id: number, data: SetTranscriptRequest) => {
+        const response = await api.put<Dictation>(`/dictations/${id}/transcript`, data);
+        return response.data;
+    },
+
+    // Replace how the dictation's words are read aloud; a text dictation's
+    // audio is generated again. An empty list removes the lexicon
+    setLexicon: async (id: number, lexicon: LexiconEntry[]) => {
+        const response = await api.put<Dictation>(`/dictations/${id}/lexicon`, { lexicon });
+        return response.data;
+    },
+
+    // Timed segments of an audio dictation, for replaying one at a time
+    getSegments: async (id: number) => {
+        const response = await api.get<DictationSegment[]>(`/dictations/${id}/segments`);
+        return response.data;
+    },
+
+    // Audio of one segment, counting from 1
+    getSegmentAudio: async (id: number, segment: number): Promise<Blob> => {
+        const response = await api.get(`/dictations/${id}/segments/${segment}/audio`, { responseType: 'blob' });
+        return response.data;
+    },
+
+    // Stored audio of a dictation, generated in the background for text
+    // dictations once audio_status is "ready". speed (0.5-2) slows it down
+    // or speeds it up without changing the pitch; WAV audio only. A speed
+    // is prepared in the background: 404 with speed_status "pending" until
+    // it is ready
+    getAudio: async (id: number, speed?: number): Promise<Blob> => {
+        const response = await api.get(`/dictations/${id}/audio`, {
+            params: speed ? { speed } : undefined,
+            responseType: 'blob',
+        });
+        return response.data;
+    },
+
+    // Peaks of the stored audio for drawing a scrubber; 404 with
+    // waveform_status "pending" until it has been computed
+    getWaveform: async (id: number) => {
+        const response = await api.get<Waveform>(`/dictations/${id}/waveform`);
+        return response.data;
+    },
+
+    // Queue a failed audio generation to run again
+    retryAudio: async (id: number) => {
+        const response = await api.post<Dictation>(`/dictations/${id}/audio/retry`);
+        return response.data;
+    },
+
     // Delete a dictation
     delete: async (id: number) => {
         await api.delete(`/dictations/${id}`);
@@ -1,10 +1,68 @@
 import api from '../lib/axios';
 
+export interface Voice {
+    id: string;
+    name: string;
+    default?: boolean;
+}
+
+export interface VoiceCatalogue {
+    provider: string;
+    model: string;
+    min_speed: number;
+    max_speed: number;
+    voices: Voice[];
+}
+
+export interface PhraseTiming {
+    text: string;
+    words: number;
+    start_ms: number;
+    end_ms: number;
+}
+
+export interface PacedTiming {
+    dictation_id: number;
+    voice: string;
+    speed: number;
+    target_wpm: number;
+    wpm: number;
+    duration_ms: number;
+    phrases: PhraseTiming[];
+}
+
+export interface PaceOptions {
+    wpm: number;
+    voice?: string;
+    speed?: number;
+}
+
 export const ttsService = {
-    generateAudio: async (text: string): Promise<Blob> => {
-        const response = await api.post('/tts/generate', { text }, {
+    // Voice and speed default to the user's settings when omitted. With
+    // dictation_id the text is read with that dictation's language and lexicon
+    generateAudio: async (text: string, options: { voice?: string; speed?: number; dictation_id?: number; language?: string } = {}): Promise<Blob> => {
+        const response = await api.post('/tts/generate', { text, ...options }, {
             responseType: 'blob'
         });
         return response.data;
+    },
+
+    // A text dictation read at a target WPM, pausing after each phrase
+    getPacedAudio: async (dictationId: number, options: PaceOptions): Promise<Blob> => {
+        const response = await api.get(`/dictations/${dictationId}/paced-audio`, {
+            params: options,
+            responseType: 'blob'
+        });
+        return response.data;
+    },
+
+    getPacedTiming: async (dictationId: number, options: PaceOptions): Promise<PacedTiming> => {
+        const response = await api.get<PacedTiming>(`/dictations/${dictationId}/paced-timing`, { params: options });
+        return response.data;
+    },
+
+    listVoices: async (): Promise<VoiceCatalogue> => {
+        const response = await api.get<VoiceCatalogue>('/tts/voices');
+        return response.data;
     }
 };
@@ -1,5 +1,59 @@
 
 
+// Differences accepted as matches when scoring. A profile ("strict",
+// "standard", "lenient") enables a preset set of rules.
+export interface Tolerance {
+    profile?: string;
+    spelling_variants?: boolean;
+    numbers?: boolean;
+    contractions?: boolean;
+    homophones?: boolean;
+    alternatives?: string[][];
+}
+
+// Per-dictation scoring settings; "steno" grades attempts like a
+// stenographer skill test (full and half mistakes, pass/fail)
+export interface ScoringOptions {
+    rubric?: string;
+    pass_threshold?: number;
+    tolerance?: Tolerance;
+    // Score missed or extra line and paragraph breaks
+    layout?: boolean;
+}
+
+// How a word or phrase is read aloud, e.g. "Dr." as "Doctor". Scoring
+// accepts the spoken form for the written one.
+export interface LexiconEntry {
+    written: string;
+    spoken: string;
+}
+
+// A timed stretch of an audio dictation and its words
+export interface TranscriptSegment {
+    start_ms: number;
+    end_ms: number;
+    text: string;
+}
+
+// A segment as listed by the server, with where its text sits in content
+export interface DictationSegment extends TranscriptSegment {
+    index: number;
+    span: { start: number; end: number };
+}
+
+// Outline of a dictation's audio in the audiowaveform JSON format: data
+// holds a min and max per pixel, rms one level per pixel, all 8-bit.
+export interface Waveform {
+    version: number;
+    channels: number;
+    sample_rate: number;
+    samples_per_pixel: number;
+    bits: number;
+    length: number;
+    data: number[];
+    rms: number[];
+}
+
 export interface Dictation {
     id: number;
     user_id: number;
@@ -7,7 +61,17 @@ export interface Dictation {
     type: string;
     content: string;
     audio_url: string;
+    // Audio generated for text dictations: "pending", "ready" or "failed"
+    audio_status?: string;
+    audio_error?: string;
+    audio_duration_ms?: number;
+    // False for audio dictations until they have a transcript
+    scorable: boolean;
+    transcript_segments?: TranscriptSegment[];
     language: string;
+    scoring_options?: ScoringOptions;
+    // Pronunciations that win over the user's own lexicon
+    lexicon?: LexiconEntry[];
     created_at: string;
     updated_at: string;
 }
@@ -18,4 +82,12 @@ export interface CreateDictationRequest {
     content: string;
     audio_url?: string;
     language?: string;
+    scoring_options?: ScoringOptions;
+    transcript_segments?: TranscriptSegment[];
+    lexicon?: LexiconEntry[];
+}
+
+export interface SetTranscriptRequest {
+    transcript?: string;
+    segments?: TranscriptSegment[];
 }
@@ -9,4 +9,12 @@ export interface PerformanceSummary {
     average_accuracy: number;
     average_time: number;
     last_attempt_at: string;
+    // Averages over the most recent attempts at this dictation
+    rolling_gross_wpm: number;
+    rolling_net_wpm: number;
+    rolling_cpm: number;
+    rolling_char_accuracy: number;
+    // Accuracy with partial credit for near-miss words
+    best_weighted_accuracy: number;
+    average_weighted_accuracy: number;
 }